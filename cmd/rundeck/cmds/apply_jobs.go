@@ -0,0 +1,117 @@
+package cmds
+
+import (
+	"strings"
+
+	"github.com/lusis/go-rundeck/pkg/cli"
+	rundeck "github.com/lusis/go-rundeck/pkg/rundeck"
+	"github.com/spf13/cobra"
+)
+
+var (
+	applyJobsDryRun bool
+	applyJobsPrune  bool
+)
+
+func applyJobsFunc(cmd *cobra.Command, args []string) error {
+	project := args[0]
+	dir := args[1]
+	ctx, cancel := interruptContext()
+	defer cancel()
+	files, err := rundeck.ReadJobDefinitionsDir(dir)
+	if err != nil {
+		return err
+	}
+	plan, err := cli.Client.PlanJobSyncContext(ctx, project, files, applyJobsPrune)
+	if err != nil {
+		return err
+	}
+	results := map[*rundeck.JobSyncChange]string{}
+	var applyErr error
+	if !applyJobsDryRun {
+		var res *rundeck.JobSyncResult
+		res, applyErr = cli.Client.ApplyJobSyncPlanContext(ctx, plan)
+		results = applyJobsResults(plan, res)
+	}
+	headers := []string{"Action", "ID", "Group", "Name", "Source"}
+	if !applyJobsDryRun {
+		headers = append(headers, "Result")
+	}
+	cli.OutputFormatter.SetHeaders(headers)
+	for _, change := range plan.Changes() {
+		row := []string{
+			string(change.Action),
+			change.ID,
+			change.Group,
+			change.Name,
+			change.Source,
+		}
+		if !applyJobsDryRun {
+			row = append(row, results[change])
+		}
+		if rowErr := cli.OutputFormatter.AddRow(row); rowErr != nil {
+			return rowErr
+		}
+	}
+	cli.OutputFormatter.Draw()
+	return applyErr
+}
+
+// applyJobsResults works out what happened to each change in the plan
+// changes that were never attempted are left out
+func applyJobsResults(plan *rundeck.JobSyncPlan, res *rundeck.JobSyncResult) map[*rundeck.JobSyncChange]string {
+	results := map[*rundeck.JobSyncChange]string{}
+	if res == nil {
+		return results
+	}
+	key := func(group, name string) string {
+		return strings.Trim(group, "/") + "/" + name
+	}
+	if res.Imported != nil {
+		imported := map[string]string{}
+		for _, r := range res.Imported.Succeeded {
+			imported[key(r.Group, r.Name)] = "succeeded"
+		}
+		for _, r := range res.Imported.Skipped {
+			imported[key(r.Group, r.Name)] = "skipped"
+		}
+		for _, r := range res.Imported.Failed {
+			imported[key(r.Group, r.Name)] = "failed: " + r.Messages
+		}
+		for _, change := range append(append([]*rundeck.JobSyncChange{}, plan.Create...), plan.Update...) {
+			results[change] = imported[key(change.Group, change.Name)]
+		}
+	}
+	if res.Deleted != nil {
+		deleted := map[string]string{}
+		for _, r := range res.Deleted.Succeeded {
+			deleted[r.ID] = "succeeded"
+		}
+		for _, r := range res.Deleted.Failed {
+			deleted[r.ID] = "failed: " + r.Message
+		}
+		for _, change := range plan.Delete {
+			results[change] = deleted[change.ID]
+		}
+	}
+	return results
+}
+
+func applyJobsCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "apply project-name directory [--dry-run] [--prune]",
+		Short: "makes the jobs in a project match the job definitions in a directory",
+		Long: `Reads every .yaml, .yml, .xml and .json job definition under a directory and
+imports them into the project in a single request, updating existing jobs.
+
+Definitions are matched to existing jobs by uuid or, if they have no uuid, by group and name.
+Jobs on the server that are not in the directory are only deleted with --prune.
+Use --dry-run to see what would change without changing anything.`,
+		Args: cobra.ExactArgs(2),
+		RunE: applyJobsFunc,
+	}
+	rootCmd := cli.New(cmd)
+	rootCmd.Flags().BoolVar(&applyJobsDryRun, "dry-run", false, "show the plan without applying it")
+	rootCmd.Flags().BoolVar(&applyJobsPrune, "prune", false, "delete jobs on the server that are not in the directory")
+	return rootCmd
+}
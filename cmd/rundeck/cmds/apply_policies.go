@@ -0,0 +1,107 @@
+package cmds
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/lusis/go-rundeck/pkg/cli"
+	rundeck "github.com/lusis/go-rundeck/pkg/rundeck"
+	"github.com/spf13/cobra"
+)
+
+var (
+	applyPoliciesSystem  bool
+	applyPoliciesProject string
+	applyPoliciesDryRun  bool
+	applyPoliciesPrune   bool
+)
+
+func applyPoliciesFunc(cmd *cobra.Command, args []string) error {
+	if applyPoliciesSystem == (applyPoliciesProject != "") {
+		return errors.New("exactly one of --system or --project is required")
+	}
+	dir := args[0]
+	ctx, cancel := interruptContext()
+	defer cancel()
+	files, err := rundeck.ReadACLPolicyDir(dir, !applyPoliciesSystem)
+	if err != nil {
+		return err
+	}
+	plan, err := cli.Client.PlanACLPolicySyncContext(ctx, applyPoliciesProject, files, applyPoliciesPrune)
+	if err != nil {
+		return err
+	}
+	changes := plan.Changes()
+	if len(changes) == 0 {
+		fmt.Println("policies are up to date")
+		return nil
+	}
+	// diffs go to stderr so they don't mix with json or csv output
+	for _, change := range changes {
+		fmt.Fprint(os.Stderr, change.Diff)
+	}
+	results := map[*rundeck.ACLPolicySyncChange]string{}
+	var applyErr error
+	if !applyPoliciesDryRun {
+		var applied []*rundeck.ACLPolicySyncChange
+		applied, applyErr = cli.Client.ApplyACLPolicySyncPlanContext(ctx, plan)
+		var syncErr *rundeck.ACLPolicySyncError
+		if errors.As(applyErr, &syncErr) {
+			results[syncErr.Failed] = "failed"
+			for _, change := range syncErr.RolledBack {
+				results[change] = "rolled back"
+			}
+			for change := range syncErr.RollbackErrors {
+				results[change] = "rollback failed"
+			}
+		} else {
+			for _, change := range applied {
+				results[change] = "succeeded"
+			}
+		}
+	}
+	headers := []string{"Action", "Policy", "Source"}
+	if !applyPoliciesDryRun {
+		headers = append(headers, "Result")
+	}
+	cli.OutputFormatter.SetHeaders(headers)
+	for _, change := range changes {
+		row := []string{
+			string(change.Action),
+			change.Name,
+			change.Source,
+		}
+		if !applyPoliciesDryRun {
+			row = append(row, results[change])
+		}
+		if rowErr := cli.OutputFormatter.AddRow(row); rowErr != nil {
+			return rowErr
+		}
+	}
+	cli.OutputFormatter.Draw()
+	return applyErr
+}
+
+func applyPoliciesCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "apply (--system | --project project-name) directory [--dry-run] [--prune]",
+		Short: "makes the acl policies on the server match the .aclpolicy files in a directory",
+		Long: `Compares every .aclpolicy file in a directory with the system policies or a project's policies
+and shows a diff of each change before creating or updating policies to match.
+
+Every file is validated before anything is changed on the server.
+If the server rejects a change, the changes already applied are rolled back.
+Diffs are written to stderr.
+Policies on the server that are not in the directory are only deleted with --prune.
+Use --dry-run to see the diff without changing anything.`,
+		Args: cobra.ExactArgs(1),
+		RunE: applyPoliciesFunc,
+	}
+	rootCmd := cli.New(cmd)
+	rootCmd.Flags().BoolVar(&applyPoliciesSystem, "system", false, "apply system policies")
+	rootCmd.Flags().StringVar(&applyPoliciesProject, "project", "", "apply the policies of a project")
+	rootCmd.Flags().BoolVar(&applyPoliciesDryRun, "dry-run", false, "show the changes without applying them")
+	rootCmd.Flags().BoolVar(&applyPoliciesPrune, "prune", false, "delete policies on the server that are not in the directory")
+	return rootCmd
+}
@@ -0,0 +1,150 @@
+package cmds
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/lusis/go-rundeck/pkg/cli"
+	rundeck "github.com/lusis/go-rundeck/pkg/rundeck"
+	"github.com/spf13/cobra"
+)
+
+var (
+	checkPolicyUser    string
+	checkPolicyGroups  []string
+	checkPolicyAction  string
+	checkPolicyType    string
+	checkPolicyProject string
+	checkPolicyAttrs   []string
+	checkPolicyServer  bool
+)
+
+func checkPolicyFunc(cmd *cobra.Command, args []string) error {
+	if len(args) == 0 && !checkPolicyServer {
+		return errors.New("policy files or --server are required")
+	}
+	attrs, err := cli.BuildParams(checkPolicyAttrs)
+	if err != nil {
+		return err
+	}
+	evaluator := rundeck.NewACLEvaluator()
+	if checkPolicyServer {
+		client, clientErr := rundeck.NewClientFromEnv()
+		if clientErr != nil {
+			return clientErr
+		}
+		ctx, cancel := interruptContext()
+		defer cancel()
+		var projects []string
+		if checkPolicyProject != "" {
+			projects = append(projects, checkPolicyProject)
+		}
+		evaluator, err = client.GetACLEvaluatorContext(ctx, projects...)
+		if err != nil {
+			return err
+		}
+	}
+	for _, arg := range args {
+		if err := checkPolicyLoad(evaluator, arg); err != nil {
+			return err
+		}
+	}
+
+	decision := evaluator.Evaluate(
+		&rundeck.ACLSubject{Username: checkPolicyUser, Groups: checkPolicyGroups},
+		checkPolicyAction,
+		&rundeck.ACLResource{Type: checkPolicyType, Project: checkPolicyProject, Attributes: attrs})
+	fmt.Println(decision.Reason)
+	if len(decision.Matches) > 0 {
+		cli.OutputFormatter.SetHeaders([]string{
+			"Effect",
+			"Source",
+			"Line",
+			"Rule",
+		})
+		for _, m := range decision.Matches {
+			effect := "allow"
+			if m.Deny {
+				effect = "deny"
+			}
+			if rowErr := cli.OutputFormatter.AddRow([]string{
+				effect,
+				m.Source,
+				strconv.Itoa(m.Line),
+				m.Rule.String(),
+			}); rowErr != nil {
+				return rowErr
+			}
+		}
+		cli.OutputFormatter.Draw()
+	}
+	if !decision.Allowed {
+		return &exitCodeError{code: 1}
+	}
+	return nil
+}
+
+// checkPolicyLoad adds a policy file or a directory of them to the evaluator
+// files without a context are project policies and are scoped to --project like rundeck does
+func checkPolicyLoad(evaluator *rundeck.ACLEvaluator, path string) error {
+	return filepath.Walk(path, func(file string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || (file != path && filepath.Ext(file) != ".aclpolicy") {
+			return nil
+		}
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		policies, err := rundeck.ParseACLPolicy(data)
+		if err != nil {
+			return fmt.Errorf("%s: %s", file, err)
+		}
+		project := checkPolicyProject != ""
+		for _, p := range policies {
+			if p.Context != nil {
+				project = false
+			}
+		}
+		if project {
+			evaluator.AddProjectPolicies(checkPolicyProject, file, policies...)
+		} else {
+			evaluator.AddPolicies(file, policies...)
+		}
+		return nil
+	})
+}
+
+func checkPolicyCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "check [policy-file|policy-dir...] --user user --action action [--server]",
+		Short: "checks if a user may perform an action according to acl policies",
+		Long: `Evaluates acl policies locally and shows the rules that allow or deny an action.
+
+Policies are read from files and directories of .aclpolicy files, and with --server
+from the rundeck server's system policies and the policies of --project.
+Resources in a project need --project. Without it the application context is checked.
+
+rundeck policy check ./acls --user alice --group dev --project shop --action run --type job --attr name=deploy --attr group=app`,
+		RunE: checkPolicyFunc,
+	}
+	rootCmd := cli.New(cmd)
+	// policies may come from files only so server settings are loaded on demand
+	rootCmd.PreRunE = nil
+	rootCmd.Flags().StringVarP(&checkPolicyUser, "user", "u", "", "username to check")
+	rootCmd.Flags().StringSliceVarP(&checkPolicyGroups, "group", "g", nil, "groups (roles) of the user")
+	rootCmd.Flags().StringVarP(&checkPolicyAction, "action", "a", "", "action to check, i.e. read, run or delete")
+	rootCmd.Flags().StringVarP(&checkPolicyType, "type", "t", "job", "resource type: job, node, adhoc, resource, project, project_acl, storage or system_acl")
+	rootCmd.Flags().StringVarP(&checkPolicyProject, "project", "p", "", "project of the resource")
+	rootCmd.Flags().StringSliceVar(&checkPolicyAttrs, "attr", nil, "resource attribute in key=value format, i.e. name=deploy or kind=job")
+	rootCmd.Flags().BoolVar(&checkPolicyServer, "server", false, "load policies from the rundeck server")
+	_ = rootCmd.MarkFlagRequired("user")
+	_ = rootCmd.MarkFlagRequired("action")
+	return rootCmd
+}
@@ -0,0 +1,22 @@
+package cmds
+
+import (
+	"github.com/lusis/go-rundeck/pkg/cli"
+	"github.com/spf13/cobra"
+)
+
+func deleteKeyFunc(cmd *cobra.Command, args []string) error {
+	return cli.Client.DeleteKey(args[0])
+}
+
+func deleteKeyCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "delete path",
+		Short: "deletes a key from the rundeck key storage",
+		Args:  cobra.MinimumNArgs(1),
+		RunE:  deleteKeyFunc,
+	}
+	rootCmd := cli.New(cmd)
+	rootCmd.ResetFlags()
+	return rootCmd
+}
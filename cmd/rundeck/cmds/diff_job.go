@@ -0,0 +1,132 @@
+package cmds
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/lusis/go-rundeck/pkg/cli"
+	rundeck "github.com/lusis/go-rundeck/pkg/rundeck"
+	"github.com/spf13/cobra"
+)
+
+var (
+	diffJobFile       string
+	diffJobFormat     string
+	diffJobOtherURL   string
+	diffJobOtherToken string
+	diffJobExitCode   bool
+)
+
+func diffJobFunc(cmd *cobra.Command, args []string) error {
+	jobid := args[0]
+	ctx, cancel := interruptContext()
+	defer cancel()
+	var diff rundeck.JobDefinitionDiff
+	var err error
+	switch {
+	case diffJobFile != "":
+		if len(args) > 1 || diffJobOtherURL != "" {
+			return errors.New("a file can only be compared with a single job")
+		}
+		local, localErr := diffJobReadFile(jobid)
+		if localErr != nil {
+			return localErr
+		}
+		diff, err = cli.Client.DiffJobDefinitionContext(ctx, jobid, local)
+	default:
+		other := cli.Client
+		otherID := jobid
+		if len(args) > 1 {
+			otherID = args[1]
+		}
+		if diffJobOtherURL != "" {
+			if diffJobOtherToken == "" {
+				return errors.New("--other-token is required with --other-url")
+			}
+			other, err = rundeck.NewTokenAuthClient(diffJobOtherToken, diffJobOtherURL)
+			if err != nil {
+				return err
+			}
+		} else if otherID == jobid {
+			return errors.New("a second job id, --file or --other-url is required")
+		}
+		diff, err = cli.Client.DiffJobsContext(ctx, jobid, other, otherID)
+	}
+	if err != nil {
+		return err
+	}
+	cli.OutputFormatter.SetHeaders([]string{
+		"Change",
+		"Setting",
+		"From",
+		"To",
+	})
+	for _, c := range diff {
+		if rowErr := cli.OutputFormatter.AddRow([]string{
+			string(c.Kind),
+			c.Path,
+			c.From,
+			c.To,
+		}); rowErr != nil {
+			return rowErr
+		}
+	}
+	cli.OutputFormatter.Draw()
+	if diffJobExitCode && len(diff) > 0 {
+		return &exitCodeError{code: 1}
+	}
+	return nil
+}
+
+// diffJobReadFile reads the local definition to compare with a job
+// files holding several jobs are searched for the one with the job's uuid
+func diffJobReadFile(jobid string) (*rundeck.JobDefinition, error) {
+	format := diffJobFormat
+	if format == "" {
+		format = strings.TrimPrefix(strings.ToLower(filepath.Ext(diffJobFile)), ".")
+		if format == "yml" {
+			format = rundeck.JobFormatYAML
+		}
+	}
+	data, err := ioutil.ReadFile(diffJobFile)
+	if err != nil {
+		return nil, err
+	}
+	jobs, err := rundeck.ParseJobDefinitions(data, format)
+	if err != nil {
+		return nil, err
+	}
+	if len(jobs) == 1 {
+		return jobs[0], nil
+	}
+	for _, j := range jobs {
+		if j.UUID == jobid || j.ID == jobid {
+			return j, nil
+		}
+	}
+	return nil, fmt.Errorf("%s has %d jobs and none of them are %s", diffJobFile, len(jobs), jobid)
+}
+
+func diffJobCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "diff job-id [other-job-id] [--file job-definition] [--other-url url --other-token token]",
+		Short: "shows what differs between two job definitions",
+		Long: `Compares a job on the server with a local job definition file, with another job,
+or with the same job on another rundeck server.
+
+Changes are shown setting by setting going from the first job to the file or second job.
+Job uuids and the order of options are ignored. Options are shown by name and steps by number.`,
+		Args: cobra.RangeArgs(1, 2),
+		RunE: diffJobFunc,
+	}
+	rootCmd := cli.New(cmd)
+	rootCmd.Flags().StringVar(&diffJobFile, "file", "", "local job definition to compare with the job")
+	rootCmd.Flags().StringVarP(&diffJobFormat, "job-format", "f", "", "format of the job definition file. defaults to the file extension")
+	rootCmd.Flags().StringVar(&diffJobOtherURL, "other-url", "", "url of another rundeck server to compare the job with")
+	rootCmd.Flags().StringVar(&diffJobOtherToken, "other-token", "", "api token for the other rundeck server")
+	rootCmd.Flags().BoolVar(&diffJobExitCode, "exit-code", false, "exit with 1 when the jobs differ")
+	return rootCmd
+}
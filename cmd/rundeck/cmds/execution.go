@@ -12,5 +12,6 @@ func executionCommands() *cobra.Command {
 	cmd.AddCommand(toggleExecutionCommand())
 	cmd.AddCommand(getExecutionOutputCommand())
 	cmd.AddCommand(tailExecutionOutputCommand())
+	cmd.AddCommand(watchExecutionCommand())
 	return cmd
 }
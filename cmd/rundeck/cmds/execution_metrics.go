@@ -0,0 +1,83 @@
+package cmds
+
+import (
+	"strconv"
+
+	"github.com/lusis/go-rundeck/pkg/cli"
+	rundeck "github.com/lusis/go-rundeck/pkg/rundeck"
+	"github.com/lusis/go-rundeck/pkg/rundeck/responses"
+	"github.com/spf13/cobra"
+)
+
+var (
+	executionMetricsProject string
+	executionMetricsQuery   executionQueryFlags
+)
+
+func executionMetricsFunc(cmd *cobra.Command, args []string) error {
+	query, queryErr := executionMetricsQuery.query()
+	if queryErr != nil {
+		return queryErr
+	}
+	var data *rundeck.ExecutionMetrics
+	var err error
+	if executionMetricsProject != "" {
+		data, err = cli.Client.GetProjectExecutionMetrics(executionMetricsProject, query)
+	} else {
+		data, err = cli.Client.GetExecutionMetrics(query)
+	}
+	if err != nil {
+		return err
+	}
+	cli.OutputFormatter.SetHeaders([]string{
+		"Total",
+		"Succeeded",
+		"Failed",
+		"Failed With Retry",
+		"Aborted",
+		"Timed Out",
+		"Running",
+		"Scheduled",
+		"Other",
+		"Avg Duration",
+		"Min Duration",
+		"Max Duration",
+	})
+	if rowErr := cli.OutputFormatter.AddRow([]string{
+		strconv.Itoa(data.Total),
+		strconv.Itoa(data.Status.Succeeded),
+		strconv.Itoa(data.Status.Failed),
+		strconv.Itoa(data.Status.FailedWithRetry),
+		strconv.Itoa(data.Status.Aborted),
+		strconv.Itoa(data.Status.TimedOut),
+		strconv.Itoa(data.Status.Running),
+		strconv.Itoa(data.Status.Scheduled),
+		strconv.Itoa(data.Status.Other),
+		metricsDuration(data.Duration.Average),
+		metricsDuration(data.Duration.Min),
+		metricsDuration(data.Duration.Max),
+	}); rowErr != nil {
+		return rowErr
+	}
+	cli.OutputFormatter.Draw()
+	return nil
+}
+
+func metricsDuration(d *responses.JSONDuration) string {
+	if d == nil {
+		return ""
+	}
+	return d.String()
+}
+
+func executionMetricsCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "metrics [-p project] [--status failed] [--recent 7d]",
+		Short: "gets execution metrics (counts by status and durations) for executions matching the filters",
+		RunE:  executionMetricsFunc,
+	}
+	rootCmd := cli.New(cmd)
+	rootCmd.Flags().StringVarP(&executionMetricsProject, "project", "p", "", "only get metrics for this project")
+	executionMetricsQuery.addFlags(rootCmd)
+	return rootCmd
+}
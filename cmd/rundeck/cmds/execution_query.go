@@ -0,0 +1,111 @@
+package cmds
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	rundeck "github.com/lusis/go-rundeck/pkg/rundeck"
+	"github.com/spf13/cobra"
+)
+
+// executionQueryFlags holds the cli flags used to build an execution query
+type executionQueryFlags struct {
+	status        string
+	user          string
+	abortedBy     string
+	recent        string
+	older         string
+	begin         string
+	end           string
+	timeFormat    string
+	adhocOnly     bool
+	excludeAdHoc  bool
+	jobIDs        []string
+	excludeJobIDs []string
+	jobs          []string
+	excludeJobs   []string
+	group         string
+	excludeGroup  string
+	executionType string
+}
+
+func (f *executionQueryFlags) addFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&f.status, "status", "", "only executions with this status (succeeded, failed, aborted, running, timedout, failed-with-retry, scheduled, other)")
+	cmd.Flags().StringVar(&f.user, "user", "", "only executions started by this user")
+	cmd.Flags().StringVar(&f.abortedBy, "aborted-by", "", "only executions aborted by this user")
+	cmd.Flags().StringVar(&f.recent, "recent", "", "only executions completed within this long ago (i.e. 90m, 12h, 7d, 2w)")
+	cmd.Flags().StringVar(&f.older, "older", "", "only executions completed more than this long ago (i.e. 90m, 12h, 7d, 2w)")
+	cmd.Flags().StringVar(&f.begin, "begin", "", "only executions completed after this time")
+	cmd.Flags().StringVar(&f.end, "end", "", "only executions completed before this time")
+	cmd.Flags().StringVar(&f.timeFormat, "time-format", runJobDefaultTimeFormat, "golang time format string for --begin and --end")
+	cmd.Flags().BoolVar(&f.adhocOnly, "adhoc-only", false, "only adhoc executions")
+	cmd.Flags().BoolVar(&f.excludeAdHoc, "exclude-adhoc", false, "exclude adhoc executions")
+	cmd.Flags().StringSliceVar(&f.jobIDs, "job-id", []string{}, "only executions of this job id. Can specify multiple times")
+	cmd.Flags().StringSliceVar(&f.excludeJobIDs, "exclude-job-id", []string{}, "exclude executions of this job id. Can specify multiple times")
+	cmd.Flags().StringSliceVar(&f.jobs, "job", []string{}, "only executions of this job (group/name). Can specify multiple times")
+	cmd.Flags().StringSliceVar(&f.excludeJobs, "exclude-job", []string{}, "exclude executions of this job (group/name). Can specify multiple times")
+	cmd.Flags().StringVar(&f.group, "group", "", "only executions of jobs in this group or its subgroups. Use - for jobs without a group")
+	cmd.Flags().StringVar(&f.excludeGroup, "exclude-group", "", "exclude executions of jobs in this group or its subgroups")
+	cmd.Flags().StringVar(&f.executionType, "execution-type", "", "only executions started this way (scheduled, user, user-scheduled)")
+}
+
+// query builds and validates an execution query from the flags
+func (f *executionQueryFlags) query() (*rundeck.ExecutionQuery, error) {
+	q := &rundeck.ExecutionQuery{
+		Status:           rundeck.ExecutionStatus(f.status),
+		User:             f.user,
+		AbortedBy:        f.abortedBy,
+		OnlyAdHoc:        f.adhocOnly,
+		ExcludeAdHoc:     f.excludeAdHoc,
+		JobIDs:           f.jobIDs,
+		ExcludeJobIDs:    f.excludeJobIDs,
+		JobNames:         f.jobs,
+		ExcludeJobNames:  f.excludeJobs,
+		GroupPath:        f.group,
+		ExcludeGroupPath: f.excludeGroup,
+		ExecutionType:    rundeck.ExecutionType(f.executionType),
+	}
+	var err error
+	if q.Recent, err = parseAge(f.recent); err != nil {
+		return nil, err
+	}
+	if q.Older, err = parseAge(f.older); err != nil {
+		return nil, err
+	}
+	if f.begin != "" {
+		if q.Begin, err = time.Parse(f.timeFormat, f.begin); err != nil {
+			return nil, err
+		}
+	}
+	if f.end != "" {
+		if q.End, err = time.Parse(f.timeFormat, f.end); err != nil {
+			return nil, err
+		}
+	}
+	if err := q.Validate(); err != nil {
+		return nil, err
+	}
+	return q, nil
+}
+
+// parseAge parses a duration allowing d (days) and w (weeks) in addition to the units time.ParseDuration accepts
+func parseAge(s string) (time.Duration, error) {
+	if s == "" {
+		return 0, nil
+	}
+	units := map[string]time.Duration{
+		"d": 24 * time.Hour,
+		"w": 7 * 24 * time.Hour,
+	}
+	for suffix, unit := range units {
+		if strings.HasSuffix(s, suffix) {
+			n, err := strconv.Atoi(strings.TrimSuffix(s, suffix))
+			if err != nil {
+				return 0, err
+			}
+			return time.Duration(n) * unit, nil
+		}
+	}
+	return time.ParseDuration(s)
+}
@@ -8,5 +8,6 @@ func executionsCommands() *cobra.Command {
 		Short: "operate on rundeck multiple rundeck executions at once",
 	}
 	cmd.AddCommand(bulkDeleteExecutionsCommand())
+	cmd.AddCommand(executionMetricsCommand())
 	return cmd
 }
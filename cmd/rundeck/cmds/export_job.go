@@ -1,7 +1,7 @@
 package cmds
 
 import (
-	"fmt"
+	"os"
 
 	"github.com/lusis/go-rundeck/pkg/cli"
 	"github.com/spf13/cobra"
@@ -12,12 +12,7 @@ var exportJobFormat string
 func exportJobFunc(cmd *cobra.Command, args []string) error {
 	jobid := args[0]
 
-	res, err := cli.Client.ExportJob(jobid, exportJobFormat)
-	if err != nil {
-		return err
-	}
-	fmt.Println(string(res))
-	return nil
+	return cli.Client.ExportJobTo(jobid, exportJobFormat, os.Stdout)
 }
 
 func exportJobCommand() *cobra.Command {
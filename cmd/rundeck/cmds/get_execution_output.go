@@ -5,16 +5,46 @@ import (
 	"strconv"
 
 	cli "github.com/lusis/go-rundeck/pkg/cli"
+	rundeck "github.com/lusis/go-rundeck/pkg/rundeck"
 	"github.com/spf13/cobra"
 )
 
+// executionOutputFlags holds the cli flags that select part of an execution's output
+type executionOutputFlags struct {
+	node string
+	step string
+	last int
+}
+
+func (f *executionOutputFlags) addFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&f.node, "node", "", "only output from this node")
+	cmd.Flags().StringVar(&f.step, "step", "", "only output from this step context (i.e. 1 or 2/1)")
+	cmd.Flags().IntVar(&f.last, "last", 0, "only the last n lines of output")
+}
+
+func (f *executionOutputFlags) options() []rundeck.ExecutionOutputOption {
+	opts := []rundeck.ExecutionOutputOption{}
+	if f.node != "" {
+		opts = append(opts, rundeck.ExecutionOutputNode(f.node))
+	}
+	if f.step != "" {
+		opts = append(opts, rundeck.ExecutionOutputStep(f.step))
+	}
+	if f.last > 0 {
+		opts = append(opts, rundeck.ExecutionOutputLastLines(f.last))
+	}
+	return opts
+}
+
+var getExecutionOutputFlags executionOutputFlags
+
 func getExecutionOutputFunc(cmd *cobra.Command, args []string) error {
 	id := args[0]
 	eID, eIDerr := strconv.Atoi(id)
 	if eIDerr != nil {
 		return eIDerr
 	}
-	data, err := cli.Client.GetExecutionOutput(eID)
+	data, err := cli.Client.GetExecutionOutput(eID, getExecutionOutputFlags.options()...)
 	if err != nil {
 		return err
 	}
@@ -26,12 +56,13 @@ func getExecutionOutputFunc(cmd *cobra.Command, args []string) error {
 
 func getExecutionOutputCommand() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "output execution-id",
+		Use:   "output execution-id [--node node] [--step step] [--last n]",
 		Short: "gets an execution's output from the rundeck server",
 		Args:  cobra.MinimumNArgs(1),
 		RunE:  getExecutionOutputFunc,
 	}
 	rootCmd := cli.New(cmd)
 	rootCmd.ResetFlags()
+	getExecutionOutputFlags.addFlags(rootCmd)
 	return rootCmd
 }
@@ -0,0 +1,61 @@
+package cmds
+
+import (
+	"fmt"
+
+	"github.com/lusis/go-rundeck/pkg/cli"
+	"github.com/spf13/cobra"
+)
+
+func getKeyFunc(cmd *cobra.Command, args []string) error {
+	data, err := cli.Client.GetKeyMetaData(args[0])
+	if err != nil {
+		return err
+	}
+	cli.OutputFormatter.SetHeaders([]string{"Path", "Name", "Type", "Key Type", "Content Type", "Content Mask", "Size", "URL"})
+	if err := cli.OutputFormatter.AddRow([]string{
+		data.Path,
+		data.Name,
+		data.Type,
+		data.Meta.RundeckKeyType,
+		data.Meta.RundeckContentType,
+		data.Meta.RundeckContentMask,
+		data.Meta.RundeckContentSize,
+		data.URL,
+	}); err != nil {
+		return err
+	}
+	cli.OutputFormatter.Draw()
+	return nil
+}
+
+func getKeyCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "get path",
+		Short: "gets the metadata for a stored key",
+		Args:  cobra.MinimumNArgs(1),
+		RunE:  getKeyFunc,
+	}
+	return cli.New(cmd)
+}
+
+func getKeyContentsFunc(cmd *cobra.Command, args []string) error {
+	data, err := cli.Client.GetKeyContents(args[0])
+	if err != nil {
+		return err
+	}
+	fmt.Print(string(data))
+	return nil
+}
+
+func getKeyContentsCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "contents path",
+		Short: "prints the contents of a stored public key",
+		Args:  cobra.MinimumNArgs(1),
+		RunE:  getKeyContentsFunc,
+	}
+	rootCmd := cli.New(cmd)
+	rootCmd.ResetFlags()
+	return rootCmd
+}
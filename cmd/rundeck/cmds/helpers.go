@@ -1,8 +1,13 @@
 package cmds
 
 import (
+	"context"
 	"fmt"
+	"os"
+	"os/signal"
 	"strings"
+
+	rundeck "github.com/lusis/go-rundeck/pkg/rundeck"
 )
 
 // ParseSliceKeyValue parses a cobra StringSlice into a map[string]string split on an = sign
@@ -23,3 +28,38 @@ func ParseSliceKeyValue(s []string) (map[string]string, error) {
 	}
 	return res, nil
 }
+
+// pagingOptions builds the iterator options for the --max, --limit and --all flags
+// without --all or --limit only a single page is returned
+func pagingOptions(max, limit int, all bool) []rundeck.PagingOption {
+	pageSize := rundeck.DefaultPageSize
+	if max > 0 {
+		pageSize = max
+	}
+	opts := []rundeck.PagingOption{rundeck.PageSize(pageSize)}
+	switch {
+	case all:
+	case limit > 0:
+		opts = append(opts, rundeck.PageLimit(limit))
+	default:
+		opts = append(opts, rundeck.PageLimit(pageSize))
+	}
+	return opts
+}
+
+// interruptContext returns a context that is cancelled when the user hits ctrl-c
+// so long running commands can stop cleanly
+func interruptContext() (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(context.Background())
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	go func() {
+		defer signal.Stop(interrupt)
+		select {
+		case <-interrupt:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	return ctx, cancel
+}
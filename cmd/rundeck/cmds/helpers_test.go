@@ -2,6 +2,7 @@ package cmds
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 )
@@ -33,3 +34,36 @@ func TestParseNoEqual(t *testing.T) {
 	require.Error(t, err)
 	require.Len(t, res, 0)
 }
+
+func TestPagingOptions(t *testing.T) {
+	require.Len(t, pagingOptions(0, 0, false), 2)
+	require.Len(t, pagingOptions(10, 50, false), 2)
+	require.Len(t, pagingOptions(10, 0, true), 1)
+}
+
+func TestParseAge(t *testing.T) {
+	testCases := map[string]time.Duration{
+		"":    0,
+		"90m": 90 * time.Minute,
+		"7d":  7 * 24 * time.Hour,
+		"2w":  14 * 24 * time.Hour,
+	}
+	for in, expected := range testCases {
+		d, err := parseAge(in)
+		require.NoError(t, err)
+		require.Equal(t, expected, d)
+	}
+	_, err := parseAge("xd")
+	require.Error(t, err)
+}
+
+func TestExecutionQueryFlags(t *testing.T) {
+	f := &executionQueryFlags{status: "failed", older: "30d", jobIDs: []string{"abc"}}
+	q, err := f.query()
+	require.NoError(t, err)
+	require.Equal(t, 30*24*time.Hour, q.Older)
+	require.Equal(t, []string{"abc"}, q.JobIDs)
+	f.status = "bogus"
+	_, err = f.query()
+	require.Error(t, err)
+}
@@ -7,12 +7,16 @@ import (
 	"github.com/spf13/cobra"
 )
 
+var (
+	projectHistoryMax   int
+	projectHistoryLimit int
+	projectHistoryAll   bool
+)
+
 func projectHistoryFunc(cmd *cobra.Command, args []string) error {
 	projectid := args[0]
-	data, err := cli.Client.ListHistory(projectid)
-	if err != nil {
-		return err
-	}
+	it := cli.Client.IterateHistory(projectid, nil,
+		pagingOptions(projectHistoryMax, projectHistoryLimit, projectHistoryAll)...)
 	cli.OutputFormatter.SetHeaders([]string{
 		"Title",
 		"Status",
@@ -24,7 +28,8 @@ func projectHistoryFunc(cmd *cobra.Command, args []string) error {
 		"User",
 		"Project",
 	})
-	for _, d := range data.Events {
+	for it.Next() {
+		d := it.Event()
 		if rowErr := cli.OutputFormatter.AddRow([]string{
 			d.Title,
 			d.Status,
@@ -39,17 +44,22 @@ func projectHistoryFunc(cmd *cobra.Command, args []string) error {
 			return rowErr
 		}
 	}
-
+	if it.Err() != nil {
+		return it.Err()
+	}
 	cli.OutputFormatter.Draw()
 	return nil
 }
 func projectHistoryCommand() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "history project-name",
+		Use:   "history project-name [-m max] [--limit X|--all]",
 		Short: "gets project history from the rundeck server",
 		Args:  cobra.MinimumNArgs(1),
 		RunE:  projectHistoryFunc,
 	}
 	rootCmd := cli.New(cmd)
+	rootCmd.Flags().IntVarP(&projectHistoryMax, "max", "m", 0, "max results per page")
+	rootCmd.Flags().IntVar(&projectHistoryLimit, "limit", 0, "fetch pages until this many results are returned")
+	rootCmd.Flags().BoolVar(&projectHistoryAll, "all", false, "fetch all pages of results")
 	return rootCmd
 }
@@ -12,7 +12,9 @@ func jobCommands() *cobra.Command {
 	cmd.AddCommand(getJobCommand())
 	cmd.AddCommand(getJobOptsCommand())
 	cmd.AddCommand(exportJobCommand())
+	cmd.AddCommand(diffJobCommand())
 	cmd.AddCommand(importJobCommand())
 	cmd.AddCommand(findJobByNameCommand())
+	cmd.AddCommand(jobForecastCommand())
 	return cmd
 }
@@ -0,0 +1,150 @@
+package cmds
+
+import (
+	"sort"
+	"time"
+
+	"github.com/lusis/go-rundeck/pkg/cli"
+	rundeck "github.com/lusis/go-rundeck/pkg/rundeck"
+	"github.com/spf13/cobra"
+)
+
+var (
+	jobForecastWithin     string
+	jobForecastMax        int
+	jobForecastStart      string
+	jobForecastEnd        string
+	jobForecastTimeFormat string
+)
+
+type forecastEntry struct {
+	fireTime time.Time
+	job      *rundeck.JobForecast
+}
+
+// forecastWindow returns how far ahead to look and the window to filter fire times to
+func forecastWindow() (time.Duration, time.Time, time.Time, error) {
+	within, err := parseAge(jobForecastWithin)
+	if err != nil {
+		return 0, time.Time{}, time.Time{}, err
+	}
+	var start, end time.Time
+	if jobForecastStart != "" {
+		if start, err = time.Parse(jobForecastTimeFormat, jobForecastStart); err != nil {
+			return 0, start, end, err
+		}
+	}
+	if jobForecastEnd != "" {
+		if end, err = time.Parse(jobForecastTimeFormat, jobForecastEnd); err != nil {
+			return 0, start, end, err
+		}
+		// look far enough ahead to cover the end of the window, rounded up to the minute
+		within = time.Until(end).Truncate(time.Minute) + time.Minute
+	} else if within > 0 {
+		end = time.Now().Add(within)
+	}
+	return within, start, end, nil
+}
+
+func drawForecast(forecasts []*rundeck.JobForecast, start, end time.Time) error {
+	entries := []forecastEntry{}
+	for _, f := range forecasts {
+		for _, t := range f.FireTimesBetween(start, end) {
+			entries = append(entries, forecastEntry{fireTime: t, job: f})
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].fireTime.Before(entries[j].fireTime) })
+	cli.OutputFormatter.SetHeaders([]string{
+		"Fire Time",
+		"Job ID",
+		"Name",
+		"Group",
+		"Project",
+	})
+	for _, e := range entries {
+		group := "<none>"
+		if e.job.Group != "" {
+			group = e.job.Group
+		}
+		if rowErr := cli.OutputFormatter.AddRow([]string{
+			e.fireTime.Local().Format(runJobDefaultTimeFormat),
+			e.job.ID,
+			e.job.Name,
+			group,
+			e.job.Project,
+		}); rowErr != nil {
+			return rowErr
+		}
+	}
+	cli.OutputFormatter.Draw()
+	return nil
+}
+
+func jobForecastFunc(cmd *cobra.Command, args []string) error {
+	within, start, end, err := forecastWindow()
+	if err != nil {
+		return err
+	}
+	data, err := cli.Client.GetJobForecast(args[0], within, jobForecastMax)
+	if err != nil {
+		return err
+	}
+	return drawForecast([]*rundeck.JobForecast{data}, start, end)
+}
+
+func projectForecastFunc(cmd *cobra.Command, args []string) error {
+	within, start, end, err := forecastWindow()
+	if err != nil {
+		return err
+	}
+	data, err := cli.Client.GetProjectForecast(args[0], within, jobForecastMax)
+	if err != nil {
+		return err
+	}
+	return drawForecast(data, start, end)
+}
+
+func addForecastFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&jobForecastWithin, "within", "w", "", "how far ahead to look (i.e. 12h, 7d). If not set only the next fire time is shown")
+	cmd.Flags().IntVarP(&jobForecastMax, "max", "m", 0, "max fire times to return per job")
+	cmd.Flags().StringVar(&jobForecastStart, "start", "", "only show fire times after this time")
+	cmd.Flags().StringVar(&jobForecastEnd, "end", "", "only show fire times before this time")
+	cmd.Flags().StringVar(&jobForecastTimeFormat, "time-format", runJobDefaultTimeFormat, "golang time format string for --start and --end")
+}
+
+func jobForecastCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "forecast job-id [-w 24h] [-m max] [--start time --end time]",
+		Short: "shows the upcoming scheduled fire times of a job",
+		Long:  forecastLongHelp,
+		Args:  cobra.MinimumNArgs(1),
+		RunE:  jobForecastFunc,
+	}
+	rootCmd := cli.New(cmd)
+	addForecastFlags(rootCmd)
+	return rootCmd
+}
+
+func projectForecastCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "forecast project-name [-w 24h] [-m max] [--start time --end time]",
+		Short: "shows the upcoming scheduled fire times of all scheduled jobs in a project",
+		Long:  forecastLongHelp,
+		Args:  cobra.MinimumNArgs(1),
+		RunE:  projectForecastFunc,
+	}
+	rootCmd := cli.New(cmd)
+	addForecastFlags(rootCmd)
+	return rootCmd
+}
+
+const forecastLongHelp = `
+# Show the next fire time of a job
+rundeck job forecast <job-id>
+
+# Show all fire times of a job over the next day
+rundeck job forecast <job-id> -w 1d
+
+# Show every job in a project that will fire during a maintenance window
+rundeck project forecast <project> --start 2018-01-03T22:00:00-0500 --end 2018-01-04T02:00:00-0500
+`
@@ -9,5 +9,6 @@ func jobsCommands() *cobra.Command {
 	}
 	cmd.AddCommand(bulkToggleExecutionsCommand())
 	cmd.AddCommand(bulkToggleScheduleCommand())
+	cmd.AddCommand(applyJobsCommand())
 	return cmd
 }
@@ -0,0 +1,17 @@
+package cmds
+
+import "github.com/spf13/cobra"
+
+func keysCommands() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "keys",
+		Short: "operate on the rundeck key storage",
+	}
+	cmd.AddCommand(listKeysCommand())
+	cmd.AddCommand(getKeyCommand())
+	cmd.AddCommand(getKeyContentsCommand())
+	cmd.AddCommand(uploadKeyCommand())
+	cmd.AddCommand(updateKeyCommand())
+	cmd.AddCommand(deleteKeyCommand())
+	return cmd
+}
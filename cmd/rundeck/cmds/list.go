@@ -6,7 +6,7 @@ import (
 
 func listProjectExecutionsCommand() *cobra.Command {
 	cmd := getProjectExecutionsCommand()
-	cmd.Use = "executions project-name [-r] [-m max]"
+	cmd.Use = "executions project-name [-r] [-m max] [--limit X|--all]"
 	return cmd
 }
 
@@ -0,0 +1,59 @@
+package cmds
+
+import (
+	"github.com/lusis/go-rundeck/pkg/cli"
+	rundeck "github.com/lusis/go-rundeck/pkg/rundeck"
+	"github.com/spf13/cobra"
+)
+
+var (
+	listKeysRecursive bool
+)
+
+func listKeysFunc(cmd *cobra.Command, args []string) error {
+	path := ""
+	if len(args) > 0 {
+		path = args[0]
+	}
+	var keys []*rundeck.KeyMeta
+	if listKeysRecursive {
+		data, err := cli.Client.ListKeysRecursive(path)
+		if err != nil {
+			return err
+		}
+		keys = data
+	} else {
+		data, err := cli.Client.ListKeys(path)
+		if err != nil {
+			return err
+		}
+		for _, r := range data.Resources {
+			keys = append(keys, &rundeck.KeyMeta{ListKeysResourceResponse: r})
+		}
+	}
+	cli.OutputFormatter.SetHeaders([]string{"Path", "Type", "Key Type", "Content Type", "Size"})
+	for _, k := range keys {
+		if err := cli.OutputFormatter.AddRow([]string{
+			k.Path,
+			k.Type,
+			k.Meta.RundeckKeyType,
+			k.Meta.RundeckContentType,
+			k.Meta.RundeckContentSize,
+		}); err != nil {
+			return err
+		}
+	}
+	cli.OutputFormatter.Draw()
+	return nil
+}
+
+func listKeysCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list [path] [-r]",
+		Short: "lists the contents of a key storage path",
+		RunE:  listKeysFunc,
+	}
+	rootCmd := cli.New(cmd)
+	rootCmd.Flags().BoolVarP(&listKeysRecursive, "recursive", "r", false, "list all keys under the path including subdirectories")
+	return rootCmd
+}
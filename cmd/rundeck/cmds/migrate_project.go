@@ -0,0 +1,119 @@
+package cmds
+
+import (
+	"io/ioutil"
+	"strings"
+	"time"
+
+	"github.com/lusis/go-rundeck/pkg/cli"
+	rundeck "github.com/lusis/go-rundeck/pkg/rundeck"
+	"github.com/lusis/go-rundeck/pkg/rundeck/archive"
+	"github.com/spf13/cobra"
+	yaml "gopkg.in/yaml.v2"
+)
+
+var (
+	migrateFromProfile  string
+	migrateToProfile    string
+	migrateToProject    string
+	migrateJobUUIDs     string
+	migrateRewriteFile  string
+	migrateExecutions   bool
+	migratePollInterval time.Duration
+)
+
+func migrateProjectFunc(cmd *cobra.Command, args []string) error {
+	projectName := args[0]
+	from, err := rundeck.NewClientFromProfile(migrateFromProfile)
+	if err != nil {
+		return err
+	}
+	to, err := rundeck.NewClientFromProfile(migrateToProfile)
+	if err != nil {
+		return err
+	}
+	opts := []archive.MigrateOption{
+		archive.MigrateJobUUIDs(migrateJobUUIDs),
+		archive.MigrateExecutions(migrateExecutions),
+		archive.MigratePollInterval(migratePollInterval),
+	}
+	if migrateToProject != "" {
+		opts = append(opts, archive.MigrateToProject(migrateToProject))
+	}
+	if migrateRewriteFile != "" {
+		data, readErr := ioutil.ReadFile(migrateRewriteFile)
+		if readErr != nil {
+			return readErr
+		}
+		rewrite := &archive.ConfigRewrite{}
+		if yamlErr := yaml.UnmarshalStrict(data, rewrite); yamlErr != nil {
+			return yamlErr
+		}
+		opts = append(opts, archive.MigrateRewrite(rewrite))
+	}
+	ctx, cancel := interruptContext()
+	defer cancel()
+	res, migrateErr := archive.Migrate(ctx, from, to, projectName, opts...)
+	if res == nil {
+		return migrateErr
+	}
+	cli.OutputFormatter.SetHeaders([]string{
+		"Kind",
+		"Message",
+	})
+	if res.Created {
+		if rowErr := cli.OutputFormatter.AddRow([]string{"project", "created " + res.Target}); rowErr != nil {
+			return rowErr
+		}
+	}
+	if len(res.RewrittenKeys) > 0 {
+		if rowErr := cli.OutputFormatter.AddRow([]string{"config", "rewrote " + strings.Join(res.RewrittenKeys, ",")}); rowErr != nil {
+			return rowErr
+		}
+	}
+	for _, f := range res.Failures {
+		if rowErr := cli.OutputFormatter.AddRow([]string{f.Kind, f.Message}); rowErr != nil {
+			return rowErr
+		}
+	}
+	if res.Response != nil {
+		if rowErr := cli.OutputFormatter.AddRow([]string{"import", res.Response.ImportStatus}); rowErr != nil {
+			return rowErr
+		}
+	}
+	cli.OutputFormatter.Draw()
+	return migrateErr
+}
+
+func migrateProjectCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "migrate project-name --from-profile profile --to-profile profile",
+		Short: "copies a project from one rundeck server to another",
+		Long: `Exports a project from the server in --from-profile and imports it into the server in --to-profile.
+
+Profiles are read from ~/.rundeck/profiles.yaml or the file in RUNDECK_PROFILES.
+The target project is created if it doesn't exist. A --rewrite file can rename
+configuration keys and replace values on the way, i.e. for node sources:
+
+keys:
+  resources.source.1.: resources.source.2.
+  project.ssh-keypath: ""
+values:
+  old.example.com: new.example.com`,
+		Args: cobra.ExactArgs(1),
+		RunE: migrateProjectFunc,
+	}
+	rootCmd := cli.New(cmd)
+	// both clients come from profiles so the RUNDECK_ environment isn't needed
+	rootCmd.PreRunE = nil
+	rootCmd.Flags().StringVar(&migrateFromProfile, "from-profile", "", "profile of the server to migrate from")
+	rootCmd.Flags().StringVar(&migrateToProfile, "to-profile", "", "profile of the server to migrate to")
+	rootCmd.Flags().StringVar(&migrateToProject, "to-project", "", "name of the project on the target server. defaults to the same name")
+	rootCmd.Flags().StringVar(&migrateJobUUIDs, "job-uuids", "preserve", "preserve or remove job uuids. removed uuids are regenerated by the target")
+	rootCmd.Flags().StringVar(&migrateRewriteFile, "rewrite", "", "yaml file of config keys and values to rewrite")
+	rootCmd.Flags().BoolVar(&migrateExecutions, "executions", true, "migrate executions")
+	rootCmd.Flags().DurationVar(&migratePollInterval, "poll-interval", archive.DefaultExportPollInterval, "how often to check if the export is ready")
+	_ = rootCmd.MarkFlagRequired("from-profile")
+	_ = rootCmd.MarkFlagRequired("to-profile")
+	return rootCmd
+}
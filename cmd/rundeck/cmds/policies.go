@@ -14,3 +14,22 @@ func systemPoliciesCommands() *cobra.Command {
 	cmd.AddCommand(deleteSystemACLPolicyCommand())
 	return cmd
 }
+
+func policyCommands() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "policy",
+		Short: "work with aclpolicy files locally",
+	}
+	cmd.AddCommand(validatePolicyCommand())
+	cmd.AddCommand(checkPolicyCommand())
+	return cmd
+}
+
+func policiesCommands() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "policies",
+		Short: "operate on sets of rundeck acl policies",
+	}
+	cmd.AddCommand(applyPoliciesCommand())
+	return cmd
+}
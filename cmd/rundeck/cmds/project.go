@@ -29,8 +29,10 @@ func projectCommands() *cobra.Command {
 	cmd.AddCommand(getJobsCommand())
 	cmd.AddCommand(projectExecutionsCommand())
 	cmd.AddCommand(projectHistoryCommand())
+	cmd.AddCommand(projectForecastCommand())
 	cmd.AddCommand(getProjectConfigCommand())
 	cmd.AddCommand(exportProjectCommand())
+	cmd.AddCommand(migrateProjectCommand())
 	cmd.AddCommand(projectPoliciesCommands())
 	cmd.AddCommand(scmCommands())
 	return cmd
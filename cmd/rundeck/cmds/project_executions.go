@@ -6,26 +6,35 @@ import (
 	"strings"
 
 	"github.com/lusis/go-rundeck/pkg/cli"
+	rundeck "github.com/lusis/go-rundeck/pkg/rundeck"
 	"github.com/spf13/cobra"
 )
 
-var getProjectExecutionsMax string
+var getProjectExecutionsMax int
+var getProjectExecutionsLimit int
+var getProjectExecutionsAll bool
 var getProjectExecutionsRunningOnly bool
+var getProjectExecutionsQuery executionQueryFlags
 var deleteProjectExecutionsMax int
+var deleteProjectExecutionsAll bool
+var deleteProjectExecutionsQuery executionQueryFlags
 
 func deleteProjectExecutionsFunc(cmd *cobra.Command, args []string) error {
 	projectName := args[0]
 
-	eopts := make(map[string]string)
-	eopts["max"] = strconv.Itoa(deleteProjectExecutionsMax)
-	e, listErr := cli.Client.ListProjectExecutions(projectName, eopts)
-	if listErr != nil {
-		return listErr
+	query, queryErr := deleteProjectExecutionsQuery.query()
+	if queryErr != nil {
+		return queryErr
 	}
-
+	// without --all only the first page of matches is deleted, like the server's default page
+	it := cli.Client.IterateExecutionQuery(projectName, query,
+		pagingOptions(deleteProjectExecutionsMax, 0, deleteProjectExecutionsAll)...)
 	var toDelete []int
-	for _, execution := range e.Executions {
-		toDelete = append(toDelete, execution.ID)
+	for it.Next() {
+		toDelete = append(toDelete, it.Execution().ID)
+	}
+	if it.Err() != nil {
+		return it.Err()
 	}
 	if len(toDelete) == 0 {
 		fmt.Println("no executions to delete")
@@ -66,12 +75,12 @@ func getProjectExecutionsWrapperFunc(cmd *cobra.Command, args []string) error {
 }
 func getProjectExecutionsFunc(cmd *cobra.Command, args []string) error {
 	projectid := args[0]
-	options := make(map[string]string)
-	options["max"] = getProjectExecutionsMax
-	data, err := cli.Client.ListProjectExecutions(projectid, options)
-	if err != nil {
-		return err
+	query, queryErr := getProjectExecutionsQuery.query()
+	if queryErr != nil {
+		return queryErr
 	}
+	it := cli.Client.IterateExecutionQuery(projectid, query,
+		pagingOptions(getProjectExecutionsMax, getProjectExecutionsLimit, getProjectExecutionsAll)...)
 	cli.OutputFormatter.SetHeaders([]string{
 		"ID",
 		"Job Name",
@@ -83,7 +92,8 @@ func getProjectExecutionsFunc(cmd *cobra.Command, args []string) error {
 		"End",
 		"Project",
 	})
-	for _, d := range data.Executions {
+	for it.Next() {
+		d := it.Execution()
 		var description = nodescription
 		var name = adhoc
 		if len(d.Job.Name) > 0 {
@@ -114,6 +124,9 @@ func getProjectExecutionsFunc(cmd *cobra.Command, args []string) error {
 			return rowErr
 		}
 	}
+	if it.Err() != nil {
+		return it.Err()
+	}
 	cli.OutputFormatter.Draw()
 	return nil
 }
@@ -165,25 +178,30 @@ func getRunningProjectExectionsFunc(cmd *cobra.Command, args []string) error {
 
 func getProjectExecutionsCommand() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "list project-name [-r] [-m max]",
+		Use:   "list project-name [-r] [-m max] [--limit X|--all]",
 		Short: "gets a list of executions for a project from the rundeck server optionally only running executions",
 		Args:  cobra.MinimumNArgs(1),
 		RunE:  getProjectExecutionsWrapperFunc,
 	}
 	rootCmd := cli.New(cmd)
-	rootCmd.Flags().StringVarP(&getProjectExecutionsMax, "max", "m", "", "max results")
+	rootCmd.Flags().IntVarP(&getProjectExecutionsMax, "max", "m", 0, "max results per page")
+	rootCmd.Flags().IntVar(&getProjectExecutionsLimit, "limit", 0, "fetch pages until this many results are returned")
+	rootCmd.Flags().BoolVar(&getProjectExecutionsAll, "all", false, "fetch all pages of results")
 	rootCmd.Flags().BoolVarP(&getProjectExecutionsRunningOnly, "running-only", "r", false, "show only running executions")
+	getProjectExecutionsQuery.addFlags(rootCmd)
 	return rootCmd
 }
 
 func deleteProjectExecutionsCommand() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "delete project-name [-m X]",
-		Short: "Bulk deletes all executions from a rundeck server for the given project",
+		Use:   "delete project-name [-m X|--all] [--status failed] [--older 30d]",
+		Short: "Bulk deletes all executions from a rundeck server for the given project matching the filters",
 		Args:  cobra.MinimumNArgs(1),
 		RunE:  deleteProjectExecutionsFunc,
 	}
-	cmd.Flags().IntVarP(&deleteProjectExecutionsMax, "max", "m", 0, "max number of executions to delete")
+	cmd.Flags().IntVarP(&deleteProjectExecutionsMax, "max", "m", 0, fmt.Sprintf("max number of executions to delete. Defaults to %d", rundeck.DefaultPageSize))
+	cmd.Flags().BoolVar(&deleteProjectExecutionsAll, "all", false, "delete every matching execution")
+	deleteProjectExecutionsQuery.addFlags(cmd)
 	rootCmd := cli.New(cmd)
 	return rootCmd
 }
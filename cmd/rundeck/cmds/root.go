@@ -1,6 +1,10 @@
 package cmds
 
 import (
+	"errors"
+	"fmt"
+	"os"
+
 	"github.com/spf13/cobra"
 )
 
@@ -14,11 +18,15 @@ func RootCommand() {
 	cmd := &cobra.Command{
 		Use:   "rundeck",
 		Short: "Unified rundeck cli binary",
+		// errors are printed below so an exitCodeError can set the exit code without a message
+		SilenceErrors: true,
 	}
 	cmd.AddCommand(projectCommands(),
 		adHocCommands(),
 		listCommands(),
 		systemPoliciesCommands(),
+		policyCommands(),
+		policiesCommands(),
 		jobCommands(),
 		jobsCommands(),
 		executionCommands(),
@@ -27,6 +35,34 @@ func RootCommand() {
 		tokensCommands(),
 		httpCommand(),
 		scmCommands(),
-		logStorageCommand())
-	_ = cmd.Execute()
+		logStorageCommand(),
+		keysCommands())
+	err := cmd.Execute()
+	if err == nil {
+		return
+	}
+	code := 1
+	var exitErr *exitCodeError
+	if errors.As(err, &exitErr) {
+		code = exitErr.code
+	}
+	if exitErr == nil || exitErr.err != nil {
+		cmd.Println("Error:", err.Error())
+	}
+	os.Exit(code)
+}
+
+// exitCodeError is returned by commands that need to exit with a specific non-zero code,
+// i.e. a failed execution or a denied access check
+// err is printed before exiting. Without it the command has already reported its result
+type exitCodeError struct {
+	code int
+	err  error
+}
+
+func (e *exitCodeError) Error() string {
+	if e.err != nil {
+		return e.err.Error()
+	}
+	return fmt.Sprintf("exit status %d", e.code)
 }
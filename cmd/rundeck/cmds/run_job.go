@@ -1,6 +1,10 @@
 package cmds
 
 import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
 	"strconv"
 	"time"
 
@@ -17,6 +21,9 @@ var (
 	runJobArgString  string
 	runJobTimeFormat string
 	runJobOptions    []string
+	runJobFiles      []string
+	runJobWait       bool
+	runJobTimeout    time.Duration
 )
 
 const runJobDefaultTimeFormat = "2006-01-02T15:04:05-0700"
@@ -28,6 +35,17 @@ func runJobFunc(cmd *cobra.Command, args []string) error {
 	if paramErr != nil {
 		return paramErr
 	}
+	files, fileErr := cli.BuildParams(runJobFiles)
+	if fileErr != nil {
+		return fileErr
+	}
+	for option, path := range files {
+		ref, uploadErr := uploadJobOptionFile(jobid, option, path)
+		if uploadErr != nil {
+			return uploadErr
+		}
+		params[option] = ref
+	}
 	if len(params) > 0 {
 		runOpts = append(runOpts, rundeck.RunJobOpts(params))
 	}
@@ -55,8 +73,8 @@ func runJobFunc(cmd *cobra.Command, args []string) error {
 		}
 		runOpts = append(runOpts, rundeck.RunJobRunAt(rt))
 	}
-	data, err := cli.Client.RunJob(jobid, runOpts...)
-	if err != nil {
+	data, exitCode, err := runJob(jobid, runOpts)
+	if data == nil {
 		return err
 	}
 	cli.OutputFormatter.SetHeaders([]string{
@@ -67,6 +85,7 @@ func runJobFunc(cmd *cobra.Command, args []string) error {
 		"Node Success/Failure Count",
 		"User",
 		"Project",
+		"Status",
 	})
 
 	var description = nodescription
@@ -88,12 +107,74 @@ func runJobFunc(cmd *cobra.Command, args []string) error {
 		strconv.Itoa(len(data.SuccessfulNodes)) + "/" + strconv.Itoa(len(data.FailedNodes)),
 		data.User,
 		data.Project,
+		data.Status,
 	}); rowErr != nil {
 		return rowErr
 	}
 	cli.OutputFormatter.Draw()
+	if err != nil {
+		// the execution keeps running when --wait-timeout expires or the wait is interrupted
+		return &exitCodeError{
+			code: runJobStoppedWaitingExitCode,
+			err:  fmt.Errorf("stopped waiting for execution %d (%s): %s", data.ID, data.Permalink, err),
+		}
+	}
+	if exitCode != 0 {
+		return &exitCodeError{code: exitCode}
+	}
 	return nil
 }
+
+// runJob runs the job, waiting for it to finish with --wait
+// the exit code is non-zero when a waited on execution didn't succeed
+// the execution is returned along with the error when the wait times out or is interrupted
+func runJob(jobid string, runOpts []rundeck.RunJobOption) (*rundeck.Execution, int, error) {
+	if !runJobWait {
+		data, err := cli.Client.RunJob(jobid, runOpts...)
+		return data, 0, err
+	}
+	ctx, cancel := interruptContext()
+	defer cancel()
+	data, err := cli.Client.RunJobAndWaitContext(ctx, jobid, &rundeck.WaitOptions{Timeout: runJobTimeout}, runOpts...)
+	var execErr *rundeck.ExecutionError
+	if errors.As(err, &execErr) {
+		return execErr.Execution, executionExitCode(execErr.Status), nil
+	}
+	if data == nil {
+		return nil, 0, err
+	}
+	// data is the last known state of the execution if the wait ended early
+	return &data.Execution, 0, err
+}
+
+// runJobStoppedWaitingExitCode is the exit code when --wait-timeout expires or the wait is interrupted
+const runJobStoppedWaitingExitCode = 5
+
+// executionExitCode maps the final status of an execution to the exit code of the cli
+func executionExitCode(status rundeck.ExecutionStatus) int {
+	switch status {
+	case rundeck.ExecutionStatusSucceeded:
+		return 0
+	case rundeck.ExecutionStatusFailed, rundeck.ExecutionStatusFailedWithRetry:
+		return 1
+	case rundeck.ExecutionStatusAborted:
+		return 2
+	case rundeck.ExecutionStatusTimedOut:
+		return 3
+	default:
+		return 4
+	}
+}
+
+func uploadJobOptionFile(jobid, option, path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close() // nolint: errcheck
+	return cli.Client.UploadFileForJobOption(jobid, option, filepath.Base(path), f)
+}
+
 func runJobCommand() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "run job-id [-q foo=bar] [-c application/json]",
@@ -104,12 +185,15 @@ func runJobCommand() *cobra.Command {
 	}
 	rootCmd := cli.New(cmd)
 	rootCmd.Flags().StringSliceVarP(&runJobOptions, "options", "o", []string{}, "custom options to pass in format of name=value. Can specify multiple times")
+	rootCmd.Flags().StringSliceVar(&runJobFiles, "file", []string{}, "files to upload for file options in format of name=path. Can specify multiple times")
 	rootCmd.Flags().StringVarP(&runJobRunAs, "user", "u", "", "user to run as")
 	rootCmd.Flags().StringVarP(&runJobNodeFilter, "filter", "f", "", "node filter to use")
 	rootCmd.Flags().StringVarP(&runJobArgString, "argstring", "a", "", "args string to use")
 	rootCmd.Flags().StringVarP(&runJobLogLevel, "loglevel", "l", "", "log level to use")
 	rootCmd.Flags().StringVarP(&runJobRunAtTime, "time", "t", "", "when to run the job. If no format is specified "+runJobDefaultTimeFormat+" is used")
 	rootCmd.Flags().StringVar(&runJobTimeFormat, "time-format", runJobDefaultTimeFormat, "golang time format string")
+	rootCmd.Flags().BoolVarP(&runJobWait, "wait", "w", false, "wait for the execution to finish and exit with its status")
+	rootCmd.Flags().DurationVar(&runJobTimeout, "wait-timeout", 0, "how long to wait with --wait. 0 waits until the execution finishes")
 
 	return rootCmd
 }
@@ -130,6 +214,15 @@ rundeck job run <job-id> -a "-sleeptime 30"
 # Run the job above but using options instead of argstring
 rundeck job run <job-id> -o sleeptime=30 -o someparam=anotherval
 
+# Upload a local file for the job's file option named "config" and run the job
+rundeck job run <job-id> --file config=./settings.json
+
 # Run as another user
 rundeck job run <job-id> -u another-user
+
+# Run the job and wait for it to finish, giving up after 10 minutes
+# The exit code is 0 if the execution succeeded, 1 if it failed, 2 if it was aborted,
+# 3 if it timed out and 4 for any other status. It is 5 if the wait timed out or was
+# interrupted, which leaves the execution running
+rundeck job run <job-id> --wait --wait-timeout 10m
 `
@@ -2,65 +2,65 @@ package cmds
 
 import (
 	"bufio"
+	"context"
+	"fmt"
 	"os"
 	"strconv"
 	"time"
 
 	"github.com/lusis/go-rundeck/pkg/cli"
+	rundeck "github.com/lusis/go-rundeck/pkg/rundeck"
 	"github.com/spf13/cobra"
 )
 
-var tailPollInterval int
+var (
+	tailPollInterval int
+	tailOutputFlags  executionOutputFlags
+)
 
-func getOutput(eID int, offset string, w *bufio.Writer) error {
-	wait := time.Duration(tailPollInterval)
-	time.Sleep(wait * time.Second)
-	iOffset, iOffsetErr := strconv.Atoi(offset)
-	if iOffsetErr != nil {
-		return iOffsetErr
+func tailExecutionOutputFunc(cmd *cobra.Command, args []string) error {
+	if tailPollInterval < 1 {
+		return fmt.Errorf("--interval must be at least 1 second, got %d", tailPollInterval)
 	}
-	data, err := cli.Client.GetExecutionOutputWithOffset(eID, iOffset)
-	if err != nil {
-		return err
+	id := args[0]
+	eID, eIDerr := strconv.Atoi(id)
+	if eIDerr != nil {
+		return eIDerr
 	}
-	for _, entry := range data.Entries {
-		_, err := w.WriteString(entry.Log + "\n")
-		if err != nil {
+	ctx, cancel := interruptContext()
+	defer cancel()
+
+	// the interval is the longest we wait between polls. we poll faster while output is arriving
+	max := time.Duration(tailPollInterval) * time.Second
+	follower := cli.Client.FollowExecutionOutput(ctx, eID, rundeck.FollowPollInterval(rundeck.DefaultFollowMinInterval, max), rundeck.FollowOutput(tailOutputFlags.options()...))
+	w := bufio.NewWriter(os.Stdout)
+	for entry := range follower.Entries() {
+		if _, err := w.WriteString(entry.Log + "\n"); err != nil {
+			cancel()
 			return err
 		}
-
-		flushErr := w.Flush()
-		if flushErr != nil {
-			return flushErr
+		if err := w.Flush(); err != nil {
+			cancel()
+			return err
 		}
 	}
-
-	if data.ExecCompleted {
+	_, err := follower.Wait()
+	if err == context.Canceled {
 		return nil
 	}
-
-	return getOutput(eID, data.Offset, w)
-}
-
-func tailExecutionOutputFunc(cmd *cobra.Command, args []string) error {
-	id := args[0]
-	eID, eIDerr := strconv.Atoi(id)
-	if eIDerr != nil {
-		return eIDerr
-	}
-	w := bufio.NewWriter(os.Stdout)
-	return getOutput(eID, "0", w)
+	return err
 }
 
 func tailExecutionOutputCommand() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "tail execution-id [-i poll-interval]",
+		Use:   "tail execution-id [-i poll-interval] [--node node] [--step step] [--last n]",
 		Short: "tails an execution's output from the rundeck server",
 		Args:  cobra.MinimumNArgs(1),
 		RunE:  tailExecutionOutputFunc,
 	}
 	rootCmd := cli.New(cmd)
 	rootCmd.ResetFlags()
-	rootCmd.Flags().IntVarP(&tailPollInterval, "interval", "i", 2, "interval to poll for more log data in seconds")
+	rootCmd.Flags().IntVarP(&tailPollInterval, "interval", "i", 2, "maximum interval to poll for more log data in seconds")
+	tailOutputFlags.addFlags(rootCmd)
 	return rootCmd
 }
@@ -0,0 +1,103 @@
+package cmds
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/lusis/go-rundeck/pkg/cli"
+	rundeck "github.com/lusis/go-rundeck/pkg/rundeck"
+	"github.com/spf13/cobra"
+)
+
+var (
+	uploadKeyType     string
+	uploadKeyFileName string
+)
+
+func openKeyFile() (io.ReadCloser, error) {
+	if uploadKeyFileName == "" || uploadKeyFileName == "-" {
+		return os.Stdin, nil
+	}
+	return os.Open(uploadKeyFileName)
+}
+
+func drawKeyMeta(data *rundeck.KeyMeta) error {
+	cli.OutputFormatter.SetHeaders([]string{"Path", "Key Type", "Content Type", "URL"})
+	if err := cli.OutputFormatter.AddRow([]string{
+		data.Path,
+		data.Meta.RundeckKeyType,
+		data.Meta.RundeckContentType,
+		data.URL,
+	}); err != nil {
+		return err
+	}
+	cli.OutputFormatter.Draw()
+	return nil
+}
+
+func uploadKeyFunc(cmd *cobra.Command, args []string) error {
+	file, err := openKeyFile()
+	if err != nil {
+		return err
+	}
+	defer file.Close() // nolint: errcheck
+	data, err := cli.Client.UploadKey(args[0], uploadKeyType, file)
+	if err != nil {
+		return err
+	}
+	return drawKeyMeta(data)
+}
+
+func uploadKeyCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "upload path -t [private|public|password] [-f file]",
+		Short: "stores a new key in the rundeck key storage",
+		Long:  uploadKeyLongHelp,
+		Args:  cobra.MinimumNArgs(1),
+		RunE:  uploadKeyFunc,
+	}
+	rootCmd := cli.New(cmd)
+	rootCmd.Flags().StringVarP(&uploadKeyType, "type", "t", "", fmt.Sprintf("type of key to store [%s|%s|%s]", rundeck.KeyTypePrivate, rundeck.KeyTypePublic, rundeck.KeyTypePassword))
+	rootCmd.Flags().StringVarP(&uploadKeyFileName, "file", "f", "-", "file containing the key contents. defaults to stdin")
+	_ = rootCmd.MarkFlagRequired("type")
+	return rootCmd
+}
+
+func updateKeyFunc(cmd *cobra.Command, args []string) error {
+	file, err := openKeyFile()
+	if err != nil {
+		return err
+	}
+	defer file.Close() // nolint: errcheck
+	data, err := cli.Client.UpdateKey(args[0], uploadKeyType, file)
+	if err != nil {
+		return err
+	}
+	return drawKeyMeta(data)
+}
+
+func updateKeyCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "update path -t [private|public|password] [-f file]",
+		Short: "replaces the contents of an existing key in the rundeck key storage",
+		Args:  cobra.MinimumNArgs(1),
+		RunE:  updateKeyFunc,
+	}
+	rootCmd := cli.New(cmd)
+	rootCmd.Flags().StringVarP(&uploadKeyType, "type", "t", "", fmt.Sprintf("type of key to store [%s|%s|%s]", rundeck.KeyTypePrivate, rundeck.KeyTypePublic, rundeck.KeyTypePassword))
+	rootCmd.Flags().StringVarP(&uploadKeyFileName, "file", "f", "-", "file containing the key contents. defaults to stdin")
+	_ = rootCmd.MarkFlagRequired("type")
+	return rootCmd
+}
+
+const uploadKeyLongHelp = `
+# Store a private key
+rundeck keys upload keys/ssh/deploy.pem -t private -f ~/.ssh/deploy.pem
+
+# Store a public key
+rundeck keys upload keys/ssh/deploy.pub -t public -f ~/.ssh/deploy.pub
+
+# Store a password read from stdin
+printf 's3cr3t' | rundeck keys upload keys/db/password -t password
+`
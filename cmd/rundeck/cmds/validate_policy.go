@@ -0,0 +1,77 @@
+package cmds
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strconv"
+
+	"github.com/lusis/go-rundeck/pkg/cli"
+	rundeck "github.com/lusis/go-rundeck/pkg/rundeck"
+	"github.com/spf13/cobra"
+)
+
+var validatePolicyProject bool
+
+func validatePolicyFunc(cmd *cobra.Command, args []string) error {
+	cli.OutputFormatter.SetHeaders([]string{
+		"File",
+		"Line",
+		"Problem",
+	})
+	problems := 0
+	for _, file := range args {
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		validate := rundeck.ValidateACLPolicy
+		if validatePolicyProject {
+			validate = rundeck.ValidateProjectACLPolicy
+		}
+		validationErr := validate(data)
+		if validationErr == nil {
+			continue
+		}
+		policyErr, ok := validationErr.(*rundeck.PolicyValidationError)
+		if !ok {
+			return validationErr
+		}
+		for _, e := range policyErr.Errors {
+			problems++
+			line := ""
+			msg := e.Error()
+			if pe, ok := e.(*rundeck.ACLPolicyError); ok {
+				if pe.Line > 0 {
+					line = strconv.Itoa(pe.Line)
+				}
+				msg = pe.Message
+				if pe.Path != "" {
+					msg = pe.Path + ": " + msg
+				}
+			}
+			if rowErr := cli.OutputFormatter.AddRow([]string{file, line, msg}); rowErr != nil {
+				return rowErr
+			}
+		}
+	}
+	if problems == 0 {
+		fmt.Println("policy is valid")
+		return nil
+	}
+	cli.OutputFormatter.Draw()
+	return &exitCodeError{code: 1}
+}
+
+func validatePolicyCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "validate [--project] policy-file [policy-file...]",
+		Short: "validates aclpolicy files without a rundeck server",
+		Args:  cobra.MinimumNArgs(1),
+		RunE:  validatePolicyFunc,
+	}
+	rootCmd := cli.New(cmd)
+	// validation is local so no server settings are needed
+	rootCmd.PreRunE = nil
+	rootCmd.Flags().BoolVar(&validatePolicyProject, "project", false, "validate project policies, which are stored without a context")
+	return rootCmd
+}
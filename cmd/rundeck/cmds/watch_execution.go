@@ -0,0 +1,54 @@
+package cmds
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/lusis/go-rundeck/pkg/cli"
+	rundeck "github.com/lusis/go-rundeck/pkg/rundeck"
+	"github.com/spf13/cobra"
+)
+
+var (
+	watchPollInterval int
+	watchTimeFormat   string
+)
+
+func watchExecutionFunc(cmd *cobra.Command, args []string) error {
+	eID, eIDerr := strconv.Atoi(args[0])
+	if eIDerr != nil {
+		return eIDerr
+	}
+	ctx, cancel := interruptContext()
+	defer cancel()
+	interval := time.Duration(watchPollInterval) * time.Second
+	watcher := cli.Client.WatchExecutionState(ctx, eID, rundeck.WatchPollInterval(interval))
+	for event := range watcher.Events() {
+		ts := event.Time
+		if ts.IsZero() {
+			ts = time.Now()
+		}
+		fmt.Printf("%s %s\n", ts.Format(watchTimeFormat), event)
+	}
+	_, err := watcher.Wait()
+	if err == context.Canceled {
+		return nil
+	}
+	return err
+}
+
+func watchExecutionCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "watch execution-id [-i poll-interval]",
+		Short: "reports step and node state changes of an execution until it completes",
+		Args:  cobra.MinimumNArgs(1),
+		RunE:  watchExecutionFunc,
+	}
+	rootCmd := cli.New(cmd)
+	rootCmd.ResetFlags()
+	rootCmd.Flags().IntVarP(&watchPollInterval, "interval", "i", 2, "interval to poll for state changes in seconds")
+	rootCmd.Flags().StringVar(&watchTimeFormat, "time-format", time.RFC3339, "golang time format string for event times")
+	return rootCmd
+}
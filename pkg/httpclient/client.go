@@ -1,6 +1,8 @@
 package httpclient
 
 import (
+	"bytes"
+	"context"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -8,9 +10,9 @@ import (
 	"net/http/cookiejar"
 	"net/url"
 	"sync"
+	"time"
 
 	multierror "github.com/hashicorp/go-multierror"
-	"golang.org/x/net/publicsuffix"
 )
 
 // Response represents an http response
@@ -19,20 +21,37 @@ type Response struct {
 	Headers http.Header
 	Cookies []*http.Cookie
 	Status  int
+	// Stream is the unread response body for requests made with StreamBody
+	// Body is empty when Stream is set and the caller must close it
+	Stream io.ReadCloser
 }
 
+// Doer sends http requests. *http.Client satisfies Doer
+// A Doer is expected to be long-lived and shared between requests
+// so connections are pooled. Requests never modify it
+type Doer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// defaultClient is shared by requests that don't set their own Doer
+var defaultClient Doer = &http.Client{}
+
 // Request represents an http request
 type Request struct {
-	httpClient         *http.Client
-	cookieJar          *cookiejar.Jar
+	ctx                context.Context
+	doer               Doer
+	cookieJar          http.CookieJar
 	url                string
 	method             string
 	contentType        string
 	accept             string
 	queryParams        map[string]string
+	queryValues        url.Values
 	body               io.Reader
 	headers            map[string]string
 	allowedStatusCodes []int
+	retryPolicy        *RetryPolicy
+	stream             bool
 	sync.RWMutex
 }
 
@@ -47,8 +66,8 @@ func (cr *Request) getAllowedStatusCodes() []int {
 	return cr.allowedStatusCodes
 }
 
-func (cr *Request) setHTTPClient(c *http.Client) {
-	cr.httpClient = c
+func (cr *Request) setDoer(d Doer) {
+	cr.doer = d
 }
 
 // AddHeaders adds custom headers to the request
@@ -65,8 +84,13 @@ func AddHeaders(h ...map[string]string) RequestOption {
 
 // SetClient sets a custom http.Client to use for the request
 func SetClient(client *http.Client) RequestOption {
+	return WithDoer(client)
+}
+
+// WithDoer sets the Doer used to send the request
+func WithDoer(d Doer) RequestOption {
 	return func(r *Request) error {
-		r.setHTTPClient(client)
+		r.setDoer(d)
 		return nil
 	}
 }
@@ -79,10 +103,23 @@ func QueryParams(m map[string]string) RequestOption {
 	}
 }
 
-// SetCookieJar sets the cookie jar to be used with requests
+// QueryValues sets query params that may have multiple values for the same key
+// QueryValues are merged with any params set via QueryParams
+func QueryValues(v url.Values) RequestOption {
+	return func(r *Request) error {
+		r.queryValues = v
+		return nil
+	}
+}
+
+// SetCookieJar sets a cookie jar to be used with the request
+// cookies from the jar are added to the request and cookies from the response are stored in it
+// without changing the jar of the Doer
 func SetCookieJar(jar *cookiejar.Jar) RequestOption {
 	return func(r *Request) error {
-		r.cookieJar = jar
+		if jar != nil {
+			r.cookieJar = jar
+		}
 		return nil
 	}
 }
@@ -139,6 +176,87 @@ func WithBody(reader io.Reader) RequestOption {
 	}
 }
 
+// ReplayableBody lets the same options be used to send a request more than once
+// Seekable bodies are rewound and other bodies are buffered when they are no larger than maxBuffered bytes
+// Sending the request again with a body that could only be read once fails with ErrBodyNotReplayable
+// It must come after WithBody and a new one is needed for every request
+func ReplayableBody(maxBuffered int64) RequestOption {
+	var replay func() (io.Reader, error)
+	prepared := false
+	return func(r *Request) error {
+		if !prepared {
+			prepared = true
+			first, rp, err := replayBody(r.body, maxBuffered)
+			if err != nil {
+				return err
+			}
+			r.body, replay = first, rp
+			return nil
+		}
+		if replay == nil {
+			return ErrBodyNotReplayable
+		}
+		body, err := replay()
+		if err != nil {
+			return err
+		}
+		r.body = body
+		return nil
+	}
+}
+
+// replayBody prepares body to be read more than once. It returns the reader to send first and a function
+// returning the reader for every later send, which is nil when the body can only be read once
+// Seekable bodies are rewound in place and other bodies are buffered when they fit in maxBuffered bytes
+func replayBody(body io.Reader, maxBuffered int64) (io.Reader, func() (io.Reader, error), error) {
+	if body == nil {
+		return nil, func() (io.Reader, error) { return nil, nil }, nil
+	}
+	if seeker, ok := body.(io.ReadSeeker); ok {
+		if start, err := seeker.Seek(0, io.SeekCurrent); err == nil {
+			if _, isCloser := body.(io.Closer); isCloser {
+				// keep the transport from closing a body that is sent again
+				body = struct{ io.Reader }{seeker}
+			}
+			return body, func() (io.Reader, error) {
+				_, err := seeker.Seek(start, io.SeekStart)
+				return body, err
+			}, nil
+		}
+	}
+	if maxBuffered <= 0 {
+		return body, nil, nil
+	}
+	buf, err := ioutil.ReadAll(io.LimitReader(body, maxBuffered+1))
+	if err != nil {
+		return nil, nil, err
+	}
+	if int64(len(buf)) > maxBuffered {
+		return io.MultiReader(bytes.NewReader(buf), body), nil, nil
+	}
+	return bytes.NewReader(buf), func() (io.Reader, error) { return bytes.NewReader(buf), nil }, nil
+}
+
+// StreamBody returns the response body unread in Response.Stream instead of buffering it in Response.Body
+// Responses with an unexpected status code are still buffered
+func StreamBody() RequestOption {
+	return func(r *Request) error {
+		r.stream = true
+		return nil
+	}
+}
+
+// WithContext sets the context used for the lifetime of the http request
+func WithContext(ctx context.Context) RequestOption {
+	return func(r *Request) error {
+		if ctx == nil {
+			return ErrNilContext
+		}
+		r.ctx = ctx
+		return nil
+	}
+}
+
 // New creates a ClientRequest
 func New(opts ...RequestOption) (*Request, *http.Request, error) {
 	return newHTTPRequest(opts...)
@@ -188,20 +306,11 @@ func setURL(u string) RequestOption {
 // newHTTPRequest returns a new `Request` configured with various options
 func newHTTPRequest(opts ...RequestOption) (*Request, *http.Request, error) {
 	r := &Request{}
-	if r.httpClient == nil {
-		r.setHTTPClient(&http.Client{})
-	}
+	r.setDoer(defaultClient)
 	codes := make([]int, 0)
 	headers := make(map[string]string)
 	r.allowedStatusCodes = codes
 	r.headers = headers
-	jar, jarErr := cookiejar.New(&cookiejar.Options{
-		PublicSuffixList: publicsuffix.List,
-	})
-	if jarErr != nil {
-		return nil, nil, jarErr
-	}
-	r.cookieJar = jar
 	for _, opt := range opts {
 		r.Lock()
 		if err := opt(r); err != nil {
@@ -227,11 +336,16 @@ func (cr *Request) httpRequest() (*http.Request, error) {
 		return nil, uErr
 	}
 
-	if len(cr.queryParams) > 0 {
+	if len(cr.queryParams) > 0 || len(cr.queryValues) > 0 {
 		qs := url.Values{}
 		for q, p := range cr.queryParams {
 			qs.Add(q, p)
 		}
+		for q, vals := range cr.queryValues {
+			for _, p := range vals {
+				qs.Add(q, p)
+			}
+		}
 		u.RawQuery = qs.Encode()
 	}
 
@@ -240,6 +354,9 @@ func (cr *Request) httpRequest() (*http.Request, error) {
 	if reqErr != nil {
 		return nil, reqErr
 	}
+	if cr.ctx != nil {
+		req = req.WithContext(cr.ctx)
+	}
 
 	for k, v := range cr.headers {
 		req.Header.Add(k, v)
@@ -296,37 +413,109 @@ func Head(url string, opts ...RequestOption) (*Response, error) {
 }
 
 func doRequest(opts ...RequestOption) (*Response, error) {
-	response := &Response{}
 	cr, req, reqErr := newHTTPRequest(opts...)
 	if reqErr != nil {
 		return nil, reqErr
 	}
-	cr.httpClient.Jar = cr.cookieJar
+	policy := cr.retryPolicy
+	if !policy.enabledFor(cr.method) {
+		return cr.do(req)
+	}
+	first, replay, bodyErr := replayBody(cr.body, policy.MaxBufferedBody)
+	if bodyErr != nil {
+		return nil, bodyErr
+	}
+	cr.body = first
+	if req, reqErr = cr.httpRequest(); reqErr != nil {
+		return nil, reqErr
+	}
+	if replay == nil {
+		// the body can only be sent once
+		return cr.do(req)
+	}
+	for attempt := 1; ; attempt++ {
+		if attempt > 1 && cr.body != nil {
+			if cr.body, bodyErr = replay(); bodyErr != nil {
+				return nil, bodyErr
+			}
+			if req, reqErr = cr.httpRequest(); reqErr != nil {
+				return nil, reqErr
+			}
+		}
+		response, err := cr.do(req)
+		if attempt >= policy.MaxAttempts || !cr.shouldRetry(req, response, err) {
+			return response, err
+		}
+		if response != nil && response.Stream != nil {
+			_ = response.Stream.Close()
+		}
+		wait := policy.backoff(attempt)
+		if response != nil {
+			if after, ok := retryAfter(response.Headers, time.Now()); ok && after > wait {
+				wait = after
+			}
+		}
+		if sleepErr := sleep(req.Context(), wait); sleepErr != nil {
+			return nil, sleepErr
+		}
+	}
+}
 
-	resp, respErr := cr.httpClient.Do(req)
+// shouldRetry reports if a request that returned the response and error should be attempted again
+func (cr *Request) shouldRetry(req *http.Request, response *Response, err error) bool {
+	if req.Context().Err() != nil {
+		return false
+	}
+	if response == nil {
+		return transientError(err)
+	}
+	// a retryable status the caller explicitly expects is not a failure
+	if err == nil && len(cr.getAllowedStatusCodes()) != 0 {
+		return false
+	}
+	return cr.retryPolicy.retryableStatus(response.Status)
+}
+
+func (cr *Request) do(req *http.Request) (*Response, error) {
+	response := &Response{}
+	if cr.cookieJar != nil {
+		for _, cookie := range cr.cookieJar.Cookies(req.URL) {
+			req.AddCookie(cookie)
+		}
+	}
+	resp, respErr := cr.doer.Do(req)
 	if respErr != nil {
 		return nil, respErr
 	}
-	readBody, readErr := ioutil.ReadAll(resp.Body)
-	if readErr != nil {
-		return nil, readErr
+	if cr.cookieJar != nil {
+		cr.cookieJar.SetCookies(req.URL, resp.Cookies())
 	}
-	response.Body = readBody
 	response.Headers = resp.Header
 	response.Status = resp.StatusCode
 	response.Cookies = append(response.Cookies, resp.Cookies()...)
+	passed := true
 	if len(cr.getAllowedStatusCodes()) != 0 {
-		passed := false
+		passed = false
 		for _, code := range cr.getAllowedStatusCodes() {
 			if resp.StatusCode == code {
 				passed = true
 				break
 			}
 		}
-		if !passed {
-			return response, multierror.Append(ErrInvalidStatusCode, fmt.Errorf("status code %d", response.Status))
-		}
-
+	}
+	// unexpected responses are always buffered so callers can inspect the error body
+	if cr.stream && passed {
+		response.Stream = resp.Body
+		return response, nil
+	}
+	defer func() { _ = resp.Body.Close() }()
+	readBody, readErr := ioutil.ReadAll(resp.Body)
+	if readErr != nil {
+		return nil, readErr
+	}
+	response.Body = readBody
+	if !passed {
+		return response, multierror.Append(ErrInvalidStatusCode, fmt.Errorf("status code %d", response.Status))
 	}
 
 	return response, nil
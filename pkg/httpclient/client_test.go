@@ -1,8 +1,10 @@
 package httpclient
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
+	"io/ioutil"
 	"net/http"
 	"net/http/cookiejar"
 	"net/http/httptest"
@@ -39,7 +41,7 @@ func TestNew(t *testing.T) {
 	require.IsType(t, &http.Request{}, r)
 	require.Len(t, c.allowedStatusCodes, 0)
 	require.Equal(t, DefaultAccept, c.accept)
-	require.Equal(t, c.httpClient.Timeout, http.DefaultClient.Timeout)
+	require.Equal(t, defaultClient, c.doer)
 }
 
 func TestNewWithOpt(t *testing.T) {
@@ -56,7 +58,7 @@ func TestCustomHTTPClient(t *testing.T) {
 	c, r, err := New(SetClient(client))
 	require.NoError(t, err)
 	require.IsType(t, &http.Request{}, r)
-	require.Equal(t, 15*time.Second, c.httpClient.Timeout)
+	require.Equal(t, client, c.doer)
 }
 
 func TestCookieJarDefault(t *testing.T) {
@@ -91,6 +93,49 @@ func TestCookieJarCustom(t *testing.T) {
 	require.Len(t, jar.Cookies(url), 1)
 }
 
+func TestCookieJarDoesNotModifyClient(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, err := r.Cookie("foocookiekey"); err == nil {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		http.SetCookie(w, &http.Cookie{Name: "foocookiekey", Value: "foocookievalue"})
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer ts.Close()
+	jar, err := cookiejar.New(&cookiejar.Options{
+		PublicSuffixList: publicsuffix.List,
+	})
+	require.NoError(t, err)
+	client := &http.Client{}
+	_, err = Get(ts.URL, SetClient(client), SetCookieJar(jar), ExpectStatus(401))
+	require.NoError(t, err)
+	_, err = Get(ts.URL, SetClient(client), SetCookieJar(jar), ExpectStatus(200))
+	require.NoError(t, err)
+	require.Nil(t, client.Jar)
+}
+
+type testDoer struct {
+	requests int
+}
+
+func (d *testDoer) Do(req *http.Request) (*http.Response, error) {
+	d.requests++
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{},
+		Body:       ioutil.NopCloser(strings.NewReader("from doer")),
+	}, nil
+}
+
+func TestWithDoer(t *testing.T) {
+	d := &testDoer{}
+	resp, err := Get("http://localhost:4440/", WithDoer(d), ExpectStatus(200))
+	require.NoError(t, err)
+	require.Equal(t, "from doer", string(resp.Body))
+	require.Equal(t, 1, d.requests)
+}
+
 func TestErrOpt(t *testing.T) {
 	c, r, err := New(testCustomOption())
 	require.Nil(t, c)
@@ -216,3 +261,66 @@ func TestPutWithParams(t *testing.T) {
 	require.NoError(t, jErr)
 	require.Equal(t, "this is my body", res.Data)
 }
+
+func TestWithContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	_, r, err := New(WithContext(ctx))
+	require.NoError(t, err)
+	require.Equal(t, ctx, r.Context())
+}
+
+func TestWithNilContext(t *testing.T) {
+	_, _, err := New(WithContext(nil))
+	require.Equal(t, ErrNilContext, err)
+}
+
+func TestWithContextCancelled(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	resp, err := Get(ts.URL, WithContext(ctx))
+	require.Error(t, err)
+	require.Nil(t, resp)
+}
+
+func TestQueryValues(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, []string{"a", "b"}, r.URL.Query()["id"])
+		require.Equal(t, "bar", r.URL.Query().Get("foo"))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+	_, err := Get(ts.URL, QueryValues(url.Values{"id": []string{"a", "b"}}), QueryParams(map[string]string{"foo": "bar"}))
+	require.NoError(t, err)
+}
+
+func TestStreamBody(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("streamed"))
+	}))
+	defer ts.Close()
+	resp, err := Get(ts.URL, StreamBody(), ExpectStatus(200))
+	require.NoError(t, err)
+	require.Empty(t, resp.Body)
+	require.NotNil(t, resp.Stream)
+	defer resp.Stream.Close() // nolint: errcheck
+	b, err := ioutil.ReadAll(resp.Stream)
+	require.NoError(t, err)
+	require.Equal(t, "streamed", string(b))
+}
+
+func TestStreamBodyUnexpectedStatus(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte("not found"))
+	}))
+	defer ts.Close()
+	resp, err := Get(ts.URL, StreamBody(), ExpectStatus(200))
+	require.Error(t, err)
+	require.Nil(t, resp.Stream)
+	require.Equal(t, "not found", string(resp.Body))
+}
@@ -15,4 +15,9 @@ var (
 	// ErrInvalidStatusCode is the error type returned when the user sets expected
 	// status code with `ExpectStatus`, but it does not match
 	ErrInvalidStatusCode = errors.New("response had an invalid status code")
+	// ErrNilContext is the error returned when a nil context is passed to `WithContext`
+	ErrNilContext = errors.New("nil context")
+	// ErrBodyNotReplayable is the error returned when a request is sent again with a `ReplayableBody`
+	// whose body could only be read once
+	ErrBodyNotReplayable = errors.New("request body cannot be sent again")
 )
@@ -0,0 +1,144 @@
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// RetryPolicy controls how failed requests are retried
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts including the first request
+	// a value of 1 or less disables retries
+	MaxAttempts int
+	// InitialBackoff is the wait before the first retry. Each retry doubles it
+	InitialBackoff time.Duration
+	// MaxBackoff caps the wait between attempts
+	MaxBackoff time.Duration
+	// Jitter is the fraction (0-1) of each backoff that is randomized
+	Jitter float64
+	// RetryableStatusCodes are the response status codes that trigger a retry
+	RetryableStatusCodes []int
+	// RetryNonIdempotent allows retrying methods like POST that may not be safe to repeat
+	RetryNonIdempotent bool
+	// MaxBufferedBody is the largest request body, in bytes, that is held in memory so it can be sent again
+	// Bodies that can be seeked, like *bytes.Reader or *os.File, are rewound instead and never buffered
+	// Requests with other bodies are sent once without retries when this is 0 or the body is larger
+	MaxBufferedBody int64
+}
+
+// DefaultRetryPolicy returns a retry policy suitable for riding out a rundeck restart
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts:          4,
+		InitialBackoff:       250 * time.Millisecond,
+		MaxBackoff:           10 * time.Second,
+		Jitter:               0.2,
+		RetryableStatusCodes: []int{429, 502, 503, 504},
+	}
+}
+
+// WithRetry sets the retry policy for a request
+func WithRetry(p *RetryPolicy) RequestOption {
+	return func(r *Request) error {
+		r.retryPolicy = p
+		return nil
+	}
+}
+
+// enabledFor reports if requests with the given method may be retried
+func (p *RetryPolicy) enabledFor(method string) bool {
+	if p == nil || p.MaxAttempts <= 1 {
+		return false
+	}
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete, http.MethodOptions:
+		return true
+	default:
+		return p.RetryNonIdempotent
+	}
+}
+
+// retryableStatus reports if the status code should be retried
+func (p *RetryPolicy) retryableStatus(code int) bool {
+	for _, c := range p.RetryableStatusCodes {
+		if c == code {
+			return true
+		}
+	}
+	return false
+}
+
+// transientError reports if a request failed with a connection level error that may succeed when retried,
+// like a timeout or a reset or refused connection while the server restarts
+// Errors like invalid certificates, unknown hosts or malformed urls are not retried
+func transientError(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED) {
+		return true
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return netErr.Timeout() || netErr.Temporary()
+	}
+	return false
+}
+
+// backoff returns the wait before the given retry (starting at 1)
+func (p *RetryPolicy) backoff(retry int) time.Duration {
+	d := p.InitialBackoff
+	for i := 1; i < retry && (p.MaxBackoff <= 0 || d < p.MaxBackoff); i++ {
+		d *= 2
+	}
+	if p.MaxBackoff > 0 && d > p.MaxBackoff {
+		d = p.MaxBackoff
+	}
+	if p.Jitter > 0 && d > 0 {
+		jitter := p.Jitter
+		if jitter > 1 {
+			jitter = 1
+		}
+		d -= time.Duration(rand.Float64() * jitter * float64(d))
+	}
+	return d
+}
+
+// retryAfter parses the Retry-After header as either seconds or an http date
+func retryAfter(h http.Header, now time.Time) (time.Duration, bool) {
+	v := h.Get("Retry-After")
+	if v == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(v); err == nil && secs >= 0 {
+		return time.Duration(secs) * time.Second, true
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		if d := t.Sub(now); d > 0 {
+			return d, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
+
+// sleep waits for d or until ctx is done
+func sleep(ctx context.Context, d time.Duration) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
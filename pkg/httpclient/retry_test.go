@@ -0,0 +1,266 @@
+package httpclient
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func testRetryPolicy() *RetryPolicy {
+	p := DefaultRetryPolicy()
+	p.InitialBackoff = time.Millisecond
+	p.MaxBackoff = 5 * time.Millisecond
+	return p
+}
+
+// testFlakyServer fails with status until it has been called failures times
+func testFlakyServer(failures, status int, attempts *int, bodies *[]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*attempts++
+		if bodies != nil {
+			b, _ := ioutil.ReadAll(r.Body)
+			*bodies = append(*bodies, string(b))
+		}
+		if *attempts <= failures {
+			w.WriteHeader(status)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte("ok"))
+	}))
+}
+
+func TestRetryGet(t *testing.T) {
+	attempts := 0
+	ts := testFlakyServer(2, http.StatusServiceUnavailable, &attempts, nil)
+	defer ts.Close()
+	resp, err := Get(ts.URL, ExpectStatus(200), WithRetry(testRetryPolicy()))
+	require.NoError(t, err)
+	require.Equal(t, "ok", string(resp.Body))
+	require.Equal(t, 3, attempts)
+}
+
+func TestRetryGiveUp(t *testing.T) {
+	attempts := 0
+	ts := testFlakyServer(10, http.StatusBadGateway, &attempts, nil)
+	defer ts.Close()
+	resp, err := Get(ts.URL, ExpectStatus(200), WithRetry(testRetryPolicy()))
+	require.Error(t, err)
+	require.Equal(t, http.StatusBadGateway, resp.Status)
+	require.Equal(t, 4, attempts)
+}
+
+func TestRetryNotRetryableStatus(t *testing.T) {
+	attempts := 0
+	ts := testFlakyServer(1, http.StatusInternalServerError, &attempts, nil)
+	defer ts.Close()
+	_, err := Get(ts.URL, ExpectStatus(200), WithRetry(testRetryPolicy()))
+	require.Error(t, err)
+	require.Equal(t, 1, attempts)
+}
+
+func TestRetryExpectedStatus(t *testing.T) {
+	attempts := 0
+	ts := testFlakyServer(1, http.StatusServiceUnavailable, &attempts, nil)
+	defer ts.Close()
+	resp, err := Get(ts.URL, ExpectStatus(200, 503), WithRetry(testRetryPolicy()))
+	require.NoError(t, err)
+	require.Equal(t, http.StatusServiceUnavailable, resp.Status)
+	require.Equal(t, 1, attempts)
+}
+
+func TestRetryPostNotIdempotent(t *testing.T) {
+	attempts := 0
+	ts := testFlakyServer(1, http.StatusServiceUnavailable, &attempts, nil)
+	defer ts.Close()
+	_, err := Post(ts.URL, ExpectStatus(200), WithRetry(testRetryPolicy()))
+	require.Error(t, err)
+	require.Equal(t, 1, attempts)
+}
+
+func TestRetryPostOptIn(t *testing.T) {
+	attempts := 0
+	bodies := []string{}
+	ts := testFlakyServer(1, http.StatusServiceUnavailable, &attempts, &bodies)
+	defer ts.Close()
+	p := testRetryPolicy()
+	p.RetryNonIdempotent = true
+	_, err := Post(ts.URL, ExpectStatus(200), WithRetry(p), WithBody(strings.NewReader("payload")))
+	require.NoError(t, err)
+	require.Equal(t, []string{"payload", "payload"}, bodies)
+}
+
+func TestRetryUnseekableBody(t *testing.T) {
+	testCases := []struct {
+		name      string
+		maxBuffer int64
+		body      string
+		expected  []string
+	}{
+		{"not buffered", 0, "payload", []string{"payload"}},
+		{"buffered", 16, "payload", []string{"payload", "payload"}},
+		{"too large", 4, "payload", []string{"payload"}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			attempts := 0
+			bodies := []string{}
+			ts := testFlakyServer(1, http.StatusServiceUnavailable, &attempts, &bodies)
+			defer ts.Close()
+			p := testRetryPolicy()
+			p.MaxBufferedBody = tc.maxBuffer
+			// hide the Seek method of the reader
+			body := struct{ io.Reader }{strings.NewReader(tc.body)}
+			_, _ = Put(ts.URL, ExpectStatus(200), WithRetry(p), WithBody(body))
+			require.Equal(t, tc.expected, bodies)
+		})
+	}
+}
+
+func TestRetrySeekableBody(t *testing.T) {
+	attempts := 0
+	bodies := []string{}
+	ts := testFlakyServer(2, http.StatusServiceUnavailable, &attempts, &bodies)
+	defer ts.Close()
+	f, err := ioutil.TempFile("", "retry")
+	require.NoError(t, err)
+	defer func() { _ = os.Remove(f.Name()) }()
+	defer func() { _ = f.Close() }()
+	_, err = f.WriteString("skip:payload")
+	require.NoError(t, err)
+	_, err = f.Seek(5, io.SeekStart)
+	require.NoError(t, err)
+	_, err = Put(ts.URL, ExpectStatus(200), WithRetry(testRetryPolicy()), WithBody(f))
+	require.NoError(t, err)
+	require.Equal(t, []string{"payload", "payload", "payload"}, bodies)
+}
+
+func TestRetryConnectionError(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	u := ts.URL
+	ts.Close()
+	p := testRetryPolicy()
+	p.MaxAttempts = 2
+	_, err := Get(u, WithRetry(p))
+	require.Error(t, err)
+}
+
+// errorDoer fails every request with err
+type errorDoer struct {
+	err   error
+	calls int
+}
+
+func (d *errorDoer) Do(req *http.Request) (*http.Response, error) {
+	d.calls++
+	return nil, &url.Error{Op: req.Method, URL: req.URL.String(), Err: d.err}
+}
+
+func TestRetryTransientErrors(t *testing.T) {
+	testCases := []struct {
+		name     string
+		err      error
+		attempts int
+	}{
+		{"refused", &net.OpError{Op: "dial", Net: "tcp", Err: os.NewSyscallError("connect", syscall.ECONNREFUSED)}, 4},
+		{"reset", &net.OpError{Op: "read", Net: "tcp", Err: os.NewSyscallError("read", syscall.ECONNRESET)}, 4},
+		{"timeout", &net.DNSError{Err: "i/o timeout", Name: "rundeck", IsTimeout: true}, 4},
+		{"unknown host", &net.DNSError{Err: "no such host", Name: "rundeck", IsNotFound: true}, 1},
+		{"certificate", x509.UnknownAuthorityError{}, 1},
+		{"other", errors.New("unsupported protocol scheme"), 1},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			doer := &errorDoer{err: tc.err}
+			_, err := Get("http://rundeck", WithDoer(doer), WithRetry(testRetryPolicy()))
+			require.Error(t, err)
+			require.Equal(t, tc.attempts, doer.calls)
+		})
+	}
+}
+
+func TestRetryCancelledDuringBackoff(t *testing.T) {
+	attempts := 0
+	ts := testFlakyServer(10, http.StatusServiceUnavailable, &attempts, nil)
+	defer ts.Close()
+	p := testRetryPolicy()
+	p.InitialBackoff = time.Hour
+	p.MaxBackoff = time.Hour
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err := Get(ts.URL, ExpectStatus(200), WithRetry(p), WithContext(ctx))
+	require.Equal(t, context.DeadlineExceeded, err)
+	require.Equal(t, 1, attempts)
+}
+
+func TestRetryHonorsRetryAfter(t *testing.T) {
+	attempts := 0
+	var waited time.Duration
+	var last time.Time
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts == 1 {
+			last = time.Now()
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		waited = time.Since(last)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+	_, err := Get(ts.URL, ExpectStatus(200), WithRetry(testRetryPolicy()))
+	require.NoError(t, err)
+	require.Equal(t, 2, attempts)
+	require.True(t, waited >= time.Second, "waited %s", waited)
+}
+
+func TestRetryAfterHeader(t *testing.T) {
+	now := time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)
+	testCases := []struct {
+		value    string
+		expected time.Duration
+		ok       bool
+	}{
+		{"", 0, false},
+		{"30", 30 * time.Second, true},
+		{now.Add(time.Minute).Format(http.TimeFormat), time.Minute, true},
+		{now.Add(-time.Minute).Format(http.TimeFormat), 0, true},
+		{"soon", 0, false},
+	}
+	for _, tc := range testCases {
+		h := http.Header{}
+		if tc.value != "" {
+			h.Set("Retry-After", tc.value)
+		}
+		d, ok := retryAfter(h, now)
+		require.Equal(t, tc.ok, ok, tc.value)
+		require.Equal(t, tc.expected, d, tc.value)
+	}
+}
+
+func TestRetryBackoff(t *testing.T) {
+	p := &RetryPolicy{InitialBackoff: 100 * time.Millisecond, MaxBackoff: time.Second}
+	require.Equal(t, 100*time.Millisecond, p.backoff(1))
+	require.Equal(t, 200*time.Millisecond, p.backoff(2))
+	require.Equal(t, 800*time.Millisecond, p.backoff(4))
+	require.Equal(t, time.Second, p.backoff(10))
+	p.Jitter = 0.5
+	for i := 0; i < 20; i++ {
+		d := p.backoff(2)
+		require.True(t, d > 100*time.Millisecond && d <= 200*time.Millisecond, d.String())
+	}
+}
@@ -1,6 +1,7 @@
 package rundeck
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -18,11 +19,16 @@ type ACLPolicies struct {
 // ListSystemACLPolicies gets the system ACL Policies
 // http://rundeck.org/docs/api/index.html#list-system-acl-policies
 func (c *Client) ListSystemACLPolicies() (*ACLPolicies, error) {
+	return c.ListSystemACLPoliciesContext(context.Background())
+}
+
+// ListSystemACLPoliciesContext gets the system ACL Policies
+func (c *Client) ListSystemACLPoliciesContext(ctx context.Context) (*ACLPolicies, error) {
 	if err := c.checkRequiredAPIVersion(responses.ACLResponse{}); err != nil {
 		return nil, err
 	}
 	data := &ACLPolicies{}
-	res, err := c.httpGet("system/acl/", requestJSON(), requestExpects(200))
+	res, err := c.httpGet(ctx, "system/acl/", requestJSON(), requestExpects(200))
 	if err != nil {
 		return nil, err
 	}
@@ -35,11 +41,16 @@ func (c *Client) ListSystemACLPolicies() (*ACLPolicies, error) {
 // GetSystemACLPolicy returns the named acl policy
 // http://rundeck.org/docs/api/index.html#get-an-acl-policy
 func (c *Client) GetSystemACLPolicy(policy string) ([]byte, error) {
+	return c.GetSystemACLPolicyContext(context.Background(), policy)
+}
+
+// GetSystemACLPolicyContext returns the named acl policy
+func (c *Client) GetSystemACLPolicyContext(ctx context.Context, policy string) ([]byte, error) {
 	if err := c.checkRequiredAPIVersion(responses.ACLResponse{}); err != nil {
 		return nil, err
 	}
 	url := fmt.Sprintf("system/acl/%s.aclpolicy", policy)
-	res, err := c.httpGet(url, accept("application/yaml"), requestExpects(200))
+	res, err := c.httpGet(ctx, url, accept("application/yaml"), requestExpects(200))
 	if err != nil {
 		return nil, err
 	}
@@ -49,11 +60,16 @@ func (c *Client) GetSystemACLPolicy(policy string) ([]byte, error) {
 // CreateSystemACLPolicy creates a system acl policy
 // http://rundeck.org/docs/api/index.html#create-an-acl-policy
 func (c *Client) CreateSystemACLPolicy(name string, contents io.Reader) error {
+	return c.CreateSystemACLPolicyContext(context.Background(), name, contents)
+}
+
+// CreateSystemACLPolicyContext creates a system acl policy
+func (c *Client) CreateSystemACLPolicyContext(ctx context.Context, name string, contents io.Reader) error {
 	if err := c.checkRequiredAPIVersion(responses.ACLResponse{}); err != nil {
 		return err
 	}
 	url := fmt.Sprintf("system/acl/%s.aclpolicy", name)
-	res, err := c.httpPost(url, withBody(contents),
+	res, err := c.httpPost(ctx, url, withBody(contents),
 		accept("application/json"),
 		contentType("application/yaml"),
 		requestExpects(201),
@@ -80,11 +96,16 @@ func (c *Client) CreateSystemACLPolicy(name string, contents io.Reader) error {
 // UpdateSystemACLPolicy creates a system acl policy
 // http://rundeck.org/docs/api/index.html#update-an-acl-policy
 func (c *Client) UpdateSystemACLPolicy(name string, contents io.Reader) error {
+	return c.UpdateSystemACLPolicyContext(context.Background(), name, contents)
+}
+
+// UpdateSystemACLPolicyContext creates a system acl policy
+func (c *Client) UpdateSystemACLPolicyContext(ctx context.Context, name string, contents io.Reader) error {
 	if err := c.checkRequiredAPIVersion(responses.ACLResponse{}); err != nil {
 		return err
 	}
 	url := fmt.Sprintf("system/acl/%s.aclpolicy", name)
-	res, err := c.httpPut(url, withBody(contents), accept("application/json"), contentType("application/yaml"), requestExpects(200), requestExpects(400))
+	res, err := c.httpPut(ctx, url, withBody(contents), accept("application/json"), contentType("application/yaml"), requestExpects(200), requestExpects(400))
 	if err != nil {
 		return err
 	}
@@ -107,20 +128,30 @@ func (c *Client) UpdateSystemACLPolicy(name string, contents io.Reader) error {
 // DeleteSystemACLPolicy deletes a system ACL Policy
 // http://rundeck.org/docs/api/index.html#delete-an-acl-policy
 func (c *Client) DeleteSystemACLPolicy(name string) error {
+	return c.DeleteSystemACLPolicyContext(context.Background(), name)
+}
+
+// DeleteSystemACLPolicyContext deletes a system ACL Policy
+func (c *Client) DeleteSystemACLPolicyContext(ctx context.Context, name string) error {
 	if err := c.checkRequiredAPIVersion(responses.ACLResponse{}); err != nil {
 		return err
 	}
-	_, err := c.httpDelete("system/acl/"+name+".aclpolicy", requestJSON(), requestExpects(204))
+	_, err := c.httpDelete(ctx, "system/acl/"+name+".aclpolicy", requestJSON(), requestExpects(204))
 	return err
 }
 
 // ListProjectACLPolicies gets a project ACL Policies
 // http://rundeck.org/docs/api/index.html#list-project-acl-policies
 func (c *Client) ListProjectACLPolicies(name string) (*ACLPolicies, error) {
+	return c.ListProjectACLPoliciesContext(context.Background(), name)
+}
+
+// ListProjectACLPoliciesContext gets a project ACL Policies
+func (c *Client) ListProjectACLPoliciesContext(ctx context.Context, name string) (*ACLPolicies, error) {
 	if err := c.checkRequiredAPIVersion(responses.ACLResponse{}); err != nil {
 		return nil, err
 	}
-	res, err := c.httpGet("project/"+name+"/acl/", requestJSON(), requestExpects(200))
+	res, err := c.httpGet(ctx, "project/"+name+"/acl/", requestJSON(), requestExpects(200))
 	if err != nil {
 		return nil, err
 	}
@@ -134,11 +165,16 @@ func (c *Client) ListProjectACLPolicies(name string) (*ACLPolicies, error) {
 // GetProjectACLPolicy gets a project ACL Policy
 // http://rundeck.org/docs/api/index.html#get-a-project-acl-policy
 func (c *Client) GetProjectACLPolicy(projectName, policyName string) ([]byte, error) {
+	return c.GetProjectACLPolicyContext(context.Background(), projectName, policyName)
+}
+
+// GetProjectACLPolicyContext gets a project ACL Policy
+func (c *Client) GetProjectACLPolicyContext(ctx context.Context, projectName, policyName string) ([]byte, error) {
 	if err := c.checkRequiredAPIVersion(responses.ACLResponse{}); err != nil {
 		return nil, err
 	}
 	url := fmt.Sprintf("project/%s/acl/%s.aclpolicy", projectName, policyName)
-	res, err := c.httpGet(url, accept("application/yaml"), requestExpects(200))
+	res, err := c.httpGet(ctx, url, accept("application/yaml"), requestExpects(200))
 	if err != nil {
 		return nil, err
 	}
@@ -148,21 +184,31 @@ func (c *Client) GetProjectACLPolicy(projectName, policyName string) ([]byte, er
 // DeleteProjectACLPolicy deletes a project ACL Policy
 // http://rundeck.org/docs/api/index.html#delete-a-project-acl-policy
 func (c *Client) DeleteProjectACLPolicy(projectName, policyName string) error {
+	return c.DeleteProjectACLPolicyContext(context.Background(), projectName, policyName)
+}
+
+// DeleteProjectACLPolicyContext deletes a project ACL Policy
+func (c *Client) DeleteProjectACLPolicyContext(ctx context.Context, projectName, policyName string) error {
 	if err := c.checkRequiredAPIVersion(responses.ACLResponse{}); err != nil {
 		return err
 	}
-	_, err := c.httpDelete("project/"+projectName+"/acl/"+policyName+".aclpolicy", requestJSON(), requestExpects(204))
+	_, err := c.httpDelete(ctx, "project/"+projectName+"/acl/"+policyName+".aclpolicy", requestJSON(), requestExpects(204))
 	return err
 }
 
 // CreateProjectACLPolicy creates a project ACL Policy
 // http://rundeck.org/docs/api/index.html#create-a-project-acl-policy
 func (c *Client) CreateProjectACLPolicy(projectName, policyName string, contents io.Reader) error {
+	return c.CreateProjectACLPolicyContext(context.Background(), projectName, policyName, contents)
+}
+
+// CreateProjectACLPolicyContext creates a project ACL Policy
+func (c *Client) CreateProjectACLPolicyContext(ctx context.Context, projectName, policyName string, contents io.Reader) error {
 	if err := c.checkRequiredAPIVersion(responses.ACLResponse{}); err != nil {
 		return err
 	}
 	url := fmt.Sprintf("project/%s/acl/%s.aclpolicy", projectName, policyName)
-	res, err := c.httpPost(url, withBody(contents),
+	res, err := c.httpPost(ctx, url, withBody(contents),
 		accept("application/json"),
 		contentType("application/yaml"),
 		requestExpects(201),
@@ -189,11 +235,16 @@ func (c *Client) CreateProjectACLPolicy(projectName, policyName string, contents
 // UpdateProjectACLPolicy updates a project ACL Policy
 // http://rundeck.org/docs/api/index.html#update-a-project-acl-policy
 func (c *Client) UpdateProjectACLPolicy(projectName, policyName string, contents io.Reader) error {
+	return c.UpdateProjectACLPolicyContext(context.Background(), projectName, policyName, contents)
+}
+
+// UpdateProjectACLPolicyContext updates a project ACL Policy
+func (c *Client) UpdateProjectACLPolicyContext(ctx context.Context, projectName, policyName string, contents io.Reader) error {
 	if err := c.checkRequiredAPIVersion(responses.ACLResponse{}); err != nil {
 		return err
 	}
 	url := fmt.Sprintf("project/%s/acl/%s.aclpolicy", projectName, policyName)
-	res, err := c.httpPut(url, withBody(contents), accept("application/json"), contentType("application/yaml"), requestExpects(200), requestExpects(400))
+	res, err := c.httpPut(ctx, url, withBody(contents), accept("application/json"), contentType("application/yaml"), requestExpects(200), requestExpects(400))
 	if err != nil {
 		return err
 	}
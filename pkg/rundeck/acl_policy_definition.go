@@ -0,0 +1,319 @@
+package rundeck
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	multierror "github.com/hashicorp/go-multierror"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// ACLPolicy is one document of an aclpolicy file
+// An aclpolicy file may hold several documents separated by ---
+type ACLPolicy struct {
+	Description string            `yaml:"description"`
+	Context     *ACLPolicyContext `yaml:"context,omitempty"`
+	// For maps a resource type (i.e. job, node, adhoc, resource, project or storage) to its rules
+	For map[string][]*ACLPolicyRule `yaml:"for,omitempty"`
+	By  *ACLPolicySubjects          `yaml:"by,omitempty"`
+	// Line is the line of the file the document starts on. It is 0 for policies not parsed from a file
+	Line  int `yaml:"-"`
+	lines *policyLines
+}
+
+// ACLPolicyContext is the context a policy applies to
+// Exactly one of Project or Application is set. Project is a regular expression matching project names
+type ACLPolicyContext struct {
+	Project     string `yaml:"project,omitempty"`
+	Application string `yaml:"application,omitempty"`
+}
+
+// ACLPolicyRule allows or denies actions on the resources it matches
+// A rule without any matchers applies to every resource of its type
+type ACLPolicyRule struct {
+	Equals map[string]string `yaml:"equals,omitempty"`
+	// Match values are regular expressions
+	Match map[string]string `yaml:"match,omitempty"`
+	// Contains matches when the resource's value has every listed value, i.e. node tags
+	Contains map[string]ACLPolicyStrings `yaml:"contains,omitempty"`
+	// Subset matches when the resource's value only has listed values
+	Subset map[string]ACLPolicyStrings `yaml:"subset,omitempty"`
+	Allow  ACLPolicyStrings            `yaml:"allow,omitempty"`
+	Deny   ACLPolicyStrings            `yaml:"deny,omitempty"`
+}
+
+// ACLPolicySubjects are the users and groups a policy applies to
+// Values are regular expressions
+type ACLPolicySubjects struct {
+	Group    ACLPolicyStrings `yaml:"group,omitempty"`
+	Username ACLPolicyStrings `yaml:"username,omitempty"`
+}
+
+// ACLPolicyStrings is a policy value that may be written as a single string or a list
+type ACLPolicyStrings []string
+
+// MarshalYAML writes a single value as a plain string
+func (s ACLPolicyStrings) MarshalYAML() (interface{}, error) {
+	if len(s) == 1 {
+		return s[0], nil
+	}
+	return []string(s), nil
+}
+
+// UnmarshalYAML reads either a single string or a list of strings
+func (s *ACLPolicyStrings) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var single string
+	if err := unmarshal(&single); err == nil {
+		*s = ACLPolicyStrings{single}
+		return nil
+	}
+	var list []string
+	if err := unmarshal(&list); err != nil {
+		return err
+	}
+	*s = list
+	return nil
+}
+
+// ACLPolicyError is a problem with a policy document
+type ACLPolicyError struct {
+	// Line is the line in the policy file or 0 if it isn't known
+	Line int
+	// Path is the location of the problem in the document, i.e. for.job[0].allow
+	Path    string
+	Message string
+}
+
+// Error returns the error message
+func (e *ACLPolicyError) Error() string {
+	msg := e.Message
+	if e.Path != "" {
+		msg = e.Path + ": " + msg
+	}
+	if e.Line > 0 {
+		return fmt.Sprintf("line %d: %s", e.Line, msg)
+	}
+	return msg
+}
+
+// ParseACLPolicy parses the documents of an aclpolicy file
+// Unknown keys are rejected so misspelled settings aren't silently ignored
+func ParseACLPolicy(data []byte) ([]*ACLPolicy, error) {
+	policies, errs := parseACLPolicy(data)
+	if len(errs) > 0 {
+		return nil, &UnmarshalError{msg: multierror.Append(errDecoding, errs...).Error()}
+	}
+	return policies, nil
+}
+
+// MarshalACLPolicy writes policies as a multi-document aclpolicy file
+func MarshalACLPolicy(policies []*ACLPolicy) ([]byte, error) {
+	docs := make([]string, 0, len(policies))
+	for _, p := range policies {
+		data, err := yaml.Marshal(p)
+		if err != nil {
+			return nil, &MarshalError{msg: multierror.Append(errEncoding, err).Error()}
+		}
+		docs = append(docs, string(data))
+	}
+	return []byte(strings.Join(docs, "---\n")), nil
+}
+
+var (
+	policySeparator = regexp.MustCompile(`^(---|\.\.\.)(\s.*)?$`)
+	yamlErrorLine   = regexp.MustCompile(`^(?:yaml: )?line (\d+): (.*)$`)
+)
+
+func parseACLPolicy(data []byte) ([]*ACLPolicy, []error) {
+	var policies []*ACLPolicy
+	var errs []error
+	lines := strings.Split(strings.Replace(string(data), "\r\n", "\n", -1), "\n")
+	start := 0
+	for i := 0; i <= len(lines); i++ {
+		if i < len(lines) && !policySeparator.MatchString(lines[i]) {
+			continue
+		}
+		doc := &policyLines{lines: lines[start:i], offset: start}
+		start = i + 1
+		if doc.empty() {
+			continue
+		}
+		p := &ACLPolicy{}
+		if err := yaml.UnmarshalStrict([]byte(strings.Join(doc.lines, "\n")), p); err != nil {
+			errs = append(errs, doc.yamlErrors(err)...)
+			continue
+		}
+		p.Line = doc.first()
+		p.lines = doc
+		policies = append(policies, p)
+	}
+	if len(policies) == 0 && len(errs) == 0 {
+		errs = append(errs, &ACLPolicyError{Message: "no policy documents found"})
+	}
+	return policies, errs
+}
+
+// policyLines is the text of one policy document. It finds the line a value was read from
+// The yaml parser doesn't report positions so block style yaml is scanned by indentation
+type policyLines struct {
+	lines []string
+	// offset is the number of lines in the file before the document
+	offset int
+}
+
+func (d *policyLines) empty() bool {
+	return d.first() == 0
+}
+
+// first returns the file line of the first line with content
+func (d *policyLines) first() int {
+	for i, l := range d.lines {
+		t := strings.TrimSpace(l)
+		if t != "" && !strings.HasPrefix(t, "#") {
+			return d.offset + i + 1
+		}
+	}
+	return 0
+}
+
+// yamlErrors converts the line numbers in yaml errors to file lines
+func (d *policyLines) yamlErrors(err error) []error {
+	var msgs []string
+	if te, ok := err.(*yaml.TypeError); ok {
+		msgs = te.Errors
+	} else {
+		msgs = []string{err.Error()}
+	}
+	errs := make([]error, 0, len(msgs))
+	for _, msg := range msgs {
+		e := &ACLPolicyError{Line: d.first(), Message: strings.TrimPrefix(msg, "yaml: ")}
+		if m := yamlErrorLine.FindStringSubmatch(msg); m != nil {
+			n, _ := strconv.Atoi(m[1]) // nolint: gosec
+			e.Line = d.offset + n
+			e.Message = m[2]
+		}
+		errs = append(errs, e)
+	}
+	return errs
+}
+
+// policyToken is a sequence entry or a mapping key found on a line
+type policyToken struct {
+	line int
+	col  int
+	dash bool
+	key  string
+}
+
+var policyKey = regexp.MustCompile(`^("[^"]*"|'[^']*'|[^\s#"'][^:#]*?)\s*:(\s|$)`)
+
+func (d *policyLines) tokens(i int) []policyToken {
+	l := d.lines[i]
+	t := strings.TrimLeft(l, " ")
+	if t == "" || strings.HasPrefix(t, "#") {
+		return nil
+	}
+	col := len(l) - len(t)
+	var tokens []policyToken
+	for t == "-" || strings.HasPrefix(t, "- ") {
+		tokens = append(tokens, policyToken{line: i, col: col, dash: true})
+		rest := strings.TrimLeft(t[1:], " ")
+		col += len(t) - len(rest)
+		t = rest
+	}
+	if m := policyKey.FindStringSubmatch(t); m != nil {
+		tokens = append(tokens, policyToken{line: i, col: col, key: strings.Trim(m[1], `"'`)})
+	}
+	return tokens
+}
+
+// children returns the tokens nested directly under parent
+func (d *policyLines) children(parent policyToken) []policyToken {
+	var out []policyToken
+	childCol := -1
+	for i := parent.line; i < len(d.lines); i++ {
+		if i < 0 {
+			continue
+		}
+		tokens := d.tokens(i)
+		if len(tokens) == 0 {
+			continue
+		}
+		if i > parent.line {
+			first := tokens[0]
+			if first.col < parent.col || (first.col == parent.col && (parent.dash || !first.dash)) {
+				break
+			}
+		}
+		for _, t := range tokens {
+			if i == parent.line && t.col <= parent.col {
+				continue
+			}
+			if childCol == -1 {
+				childCol = t.col
+			}
+			if t.col == childCol {
+				out = append(out, t)
+			}
+		}
+	}
+	return out
+}
+
+// line returns the file line of the value at path. Path elements are mapping keys or sequence indexes
+// When a value can't be found the line of the closest parent that could be found is returned
+func (d *policyLines) line(path ...interface{}) int {
+	if d == nil {
+		return 0
+	}
+	current := policyToken{line: -1, col: -1}
+	found := d.first()
+	for _, p := range path {
+		var next *policyToken
+		n := 0
+		for _, t := range d.children(current) {
+			t := t
+			switch v := p.(type) {
+			case string:
+				if !t.dash && t.key == v {
+					next = &t
+				}
+			case int:
+				if t.dash {
+					if n == v {
+						next = &t
+					}
+					n++
+				}
+			}
+			if next != nil {
+				break
+			}
+		}
+		if next == nil {
+			return found
+		}
+		current = *next
+		found = d.offset + current.line + 1
+	}
+	return found
+}
+
+// policyPath formats a path the way errors report it
+func policyPath(path ...interface{}) string {
+	var b strings.Builder
+	for _, p := range path {
+		switch v := p.(type) {
+		case int:
+			fmt.Fprintf(&b, "[%d]", v)
+		default:
+			if b.Len() > 0 {
+				b.WriteString(".")
+			}
+			fmt.Fprintf(&b, "%v", v)
+		}
+	}
+	return b.String()
+}
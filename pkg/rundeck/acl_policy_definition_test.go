@@ -0,0 +1,114 @@
+package rundeck
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testACLPolicy = `# admins can do everything
+description: admin project access
+context:
+  project: '.*'
+for:
+  resource:
+    - equals:
+        kind: job
+      allow: [create, delete]
+  job:
+  - match:
+      name: 'deploy.*'
+      group: 'app/.*'
+    allow: '*'
+  - equals:
+      name: secret
+    deny: run
+  node:
+    - contains:
+        tags: [web, prod]
+      subset:
+        tags: web
+      allow: [read, run]
+by:
+  group: admin
+---
+description: admin application access
+context:
+  application: rundeck
+for:
+  project:
+    - allow: '*'
+by:
+  username:
+    - alice
+    - 'ops.*'
+`
+
+func TestParseACLPolicy(t *testing.T) {
+	policies, err := ParseACLPolicy([]byte(testACLPolicy))
+	require.NoError(t, err)
+	require.Len(t, policies, 2)
+
+	p := policies[0]
+	require.Equal(t, 2, p.Line)
+	require.Equal(t, ".*", p.Context.Project)
+	require.Equal(t, ACLPolicyStrings{"create", "delete"}, p.For["resource"][0].Allow)
+	require.Equal(t, ACLPolicyStrings{"*"}, p.For["job"][0].Allow)
+	require.Equal(t, "app/.*", p.For["job"][0].Match["group"])
+	require.Equal(t, ACLPolicyStrings{"run"}, p.For["job"][1].Deny)
+	require.Equal(t, ACLPolicyStrings{"web", "prod"}, p.For["node"][0].Contains["tags"])
+	require.Equal(t, ACLPolicyStrings{"web"}, p.For["node"][0].Subset["tags"])
+	require.Equal(t, ACLPolicyStrings{"admin"}, p.By.Group)
+
+	p = policies[1]
+	require.Equal(t, 27, p.Line)
+	require.Equal(t, "rundeck", p.Context.Application)
+	require.Equal(t, ACLPolicyStrings{"alice", "ops.*"}, p.By.Username)
+
+	data, err := MarshalACLPolicy(policies)
+	require.NoError(t, err)
+	again, err := ParseACLPolicy(data)
+	require.NoError(t, err)
+	require.Len(t, again, 2)
+	require.Equal(t, policies[0].For, again[0].For)
+	require.Equal(t, policies[1].By, again[1].By)
+}
+
+func TestParseACLPolicyErrors(t *testing.T) {
+	_, err := ParseACLPolicy([]byte("# nothing here\n---\n"))
+	require.IsType(t, &UnmarshalError{}, err)
+
+	_, errs := parseACLPolicy([]byte("description: one\n---\ndescription: two\nfor:\n  job:\n    - alow: run\n"))
+	require.Len(t, errs, 1)
+	require.Equal(t, 6, errs[0].(*ACLPolicyError).Line)
+	require.Contains(t, errs[0].Error(), "alow")
+
+	_, errs = parseACLPolicy([]byte("description: one\n---\ndescription: [\n"))
+	require.Len(t, errs, 1)
+	require.Equal(t, 3, errs[0].(*ACLPolicyError).Line)
+}
+
+func TestPolicyLines(t *testing.T) {
+	policies, err := ParseACLPolicy([]byte(testACLPolicy))
+	require.NoError(t, err)
+	lines := policies[0].lines
+	for _, tc := range []struct {
+		path []interface{}
+		line int
+	}{
+		{nil, 2},
+		{[]interface{}{"context", "project"}, 4},
+		{[]interface{}{"for", "resource", 0}, 7},
+		{[]interface{}{"for", "resource", 0, "allow"}, 9},
+		{[]interface{}{"for", "job", 0, "match", "group"}, 13},
+		{[]interface{}{"for", "job", 1}, 15},
+		{[]interface{}{"for", "job", 1, "deny"}, 17},
+		{[]interface{}{"for", "node", 0, "subset", "tags"}, 22},
+		{[]interface{}{"for", "node", 0, "allow", 1}, 23},
+		{[]interface{}{"for", "job", 3}, 10},
+		{[]interface{}{"by", "group"}, 25},
+	} {
+		require.Equal(t, tc.line, lines.line(tc.path...), policyPath(tc.path...))
+	}
+	require.Equal(t, 36, policies[1].lines.line("by", "username", 1))
+}
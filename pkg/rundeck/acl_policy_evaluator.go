@@ -0,0 +1,300 @@
+package rundeck
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// ACLSubject is the user access is checked for
+type ACLSubject struct {
+	Username string
+	// Groups are the user's roles
+	Groups []string
+}
+
+// ACLResource is what access is checked to
+type ACLResource struct {
+	// Type is the policy resource type, i.e. job, node, adhoc, resource, project or storage
+	Type string
+	// Project is the project the resource is in. It is empty for application resources like projects or storage
+	Project string
+	// Attributes are what rules match against, i.e. name, group and uuid for a job, nodename and tags for a node,
+	// kind for a generic resource or path for storage. Multiple values are comma separated
+	Attributes map[string]string
+}
+
+// ACLRuleMatch is a rule that allowed or denied an action
+type ACLRuleMatch struct {
+	// Source is the file or policy the rule was loaded from
+	Source      string
+	Line        int
+	Description string
+	// Type is the resource type the rule is for and Index its position in that type's rules
+	Type  string
+	Index int
+	Rule  *ACLPolicyRule
+	Deny  bool
+}
+
+// ACLDecision is the result of an access check
+type ACLDecision struct {
+	Allowed bool
+	// Matches are the rules that denied the action or, if none did, the ones that allowed it
+	Matches []*ACLRuleMatch
+	Reason  string
+}
+
+// ACLEvaluator answers access questions locally with the same rules rundeck uses:
+// a matching deny always wins, otherwise a matching allow is required
+type ACLEvaluator struct {
+	policies []*aclSourcePolicy
+}
+
+type aclSourcePolicy struct {
+	source string
+	policy *ACLPolicy
+}
+
+// NewACLEvaluator returns an evaluator with no policies, which denies everything
+func NewACLEvaluator() *ACLEvaluator {
+	return &ACLEvaluator{}
+}
+
+// AddPolicies adds system policies. source is reported in the matches of access checks
+func (e *ACLEvaluator) AddPolicies(source string, policies ...*ACLPolicy) {
+	for _, p := range policies {
+		e.policies = append(e.policies, &aclSourcePolicy{source: source, policy: p})
+	}
+}
+
+// AddProjectPolicies adds policies stored in a project. They only apply to that project's resources
+func (e *ACLEvaluator) AddProjectPolicies(project, source string, policies ...*ACLPolicy) {
+	for _, p := range policies {
+		scoped := *p
+		scoped.Context = &ACLPolicyContext{Project: regexp.QuoteMeta(project)}
+		e.policies = append(e.policies, &aclSourcePolicy{source: source, policy: &scoped})
+	}
+}
+
+// Evaluate checks if subject may perform action on resource
+func (e *ACLEvaluator) Evaluate(subject *ACLSubject, action string, resource *ACLResource) *ACLDecision {
+	var allows, denies []*ACLRuleMatch
+	applies := false
+	for _, sp := range e.policies {
+		p := sp.policy
+		if !p.appliesTo(subject) || !p.inContext(resource.Project) {
+			continue
+		}
+		applies = true
+		for i, rule := range p.For[resource.Type] {
+			if rule == nil || !rule.matches(resource.Attributes) {
+				continue
+			}
+			m := &ACLRuleMatch{
+				Source:      sp.source,
+				Line:        p.lines.line("for", resource.Type, i),
+				Description: p.Description,
+				Type:        resource.Type,
+				Index:       i,
+				Rule:        rule,
+			}
+			switch {
+			case policyActionIn(action, rule.Deny):
+				m.Deny = true
+				denies = append(denies, m)
+			case policyActionIn(action, rule.Allow):
+				allows = append(allows, m)
+			}
+		}
+	}
+	switch {
+	case len(denies) > 0:
+		return &ACLDecision{Matches: denies, Reason: fmt.Sprintf("%s is denied by %d rule(s)", action, len(denies))}
+	case len(allows) > 0:
+		return &ACLDecision{Allowed: true, Matches: allows, Reason: fmt.Sprintf("%s is allowed by %d rule(s)", action, len(allows))}
+	case !applies:
+		return &ACLDecision{Reason: fmt.Sprintf("no policy applies to %s in this context", subject)}
+	default:
+		return &ACLDecision{Reason: fmt.Sprintf("no rule allows %s", action)}
+	}
+}
+
+// String returns the username and groups of the subject
+func (s *ACLSubject) String() string {
+	if len(s.Groups) == 0 {
+		return s.Username
+	}
+	return fmt.Sprintf("%s (%s)", s.Username, strings.Join(s.Groups, ","))
+}
+
+// appliesTo reports if the policy's by section matches the subject
+func (p *ACLPolicy) appliesTo(s *ACLSubject) bool {
+	if p.By == nil {
+		return false
+	}
+	for _, u := range p.By.Username {
+		if policyRegexMatch(u, s.Username) {
+			return true
+		}
+	}
+	for _, g := range p.By.Group {
+		for _, group := range s.Groups {
+			if policyRegexMatch(g, group) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// inContext reports if the policy applies to resources in project. An empty project is the application context
+func (p *ACLPolicy) inContext(project string) bool {
+	if p.Context == nil {
+		return false
+	}
+	if project == "" {
+		return p.Context.Application != ""
+	}
+	return p.Context.Project != "" && policyRegexMatch(p.Context.Project, project)
+}
+
+// matches reports if every matcher of the rule matches the attributes
+func (r *ACLPolicyRule) matches(attrs map[string]string) bool {
+	for k, v := range r.Equals {
+		if attr, ok := attrs[k]; !ok || attr != v {
+			return false
+		}
+	}
+	for k, v := range r.Match {
+		if attr, ok := attrs[k]; !ok || !policyRegexMatch(v, attr) {
+			return false
+		}
+	}
+	for k, values := range r.Contains {
+		set := policyAttributeSet(attrs[k])
+		for _, v := range values {
+			if !set[v] {
+				return false
+			}
+		}
+	}
+	for k, values := range r.Subset {
+		attr, ok := attrs[k]
+		if !ok {
+			return false
+		}
+		for v := range policyAttributeSet(attr) {
+			if !stringInSlice(v, values) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// String returns the rule in a compact form, i.e. match{name=deploy.*} allow[run,read]
+func (r *ACLPolicyRule) String() string {
+	var parts []string
+	for _, m := range []struct {
+		key    string
+		values map[string]ACLPolicyStrings
+	}{
+		{"equals", singlePolicyStrings(r.Equals)},
+		{"match", singlePolicyStrings(r.Match)},
+		{"contains", r.Contains},
+		{"subset", r.Subset},
+	} {
+		if len(m.values) == 0 {
+			continue
+		}
+		attrs := make([]string, 0, len(m.values))
+		for k, v := range m.values {
+			attrs = append(attrs, k+"="+strings.Join(v, ","))
+		}
+		sort.Strings(attrs)
+		parts = append(parts, m.key+"{"+strings.Join(attrs, " ")+"}")
+	}
+	if len(r.Allow) > 0 {
+		parts = append(parts, "allow["+strings.Join(r.Allow, ",")+"]")
+	}
+	if len(r.Deny) > 0 {
+		parts = append(parts, "deny["+strings.Join(r.Deny, ",")+"]")
+	}
+	return strings.Join(parts, " ")
+}
+
+func policyActionIn(action string, actions ACLPolicyStrings) bool {
+	for _, a := range actions {
+		if a == "*" || a == action {
+			return true
+		}
+	}
+	return false
+}
+
+// policyRegexMatch matches the whole value like rundeck does. Invalid expressions never match
+func policyRegexMatch(expr, value string) bool {
+	re, err := regexp.Compile("^(?:" + expr + ")$")
+	if err != nil {
+		return false
+	}
+	return re.MatchString(value)
+}
+
+func policyAttributeSet(value string) map[string]bool {
+	set := map[string]bool{}
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			set[v] = true
+		}
+	}
+	return set
+}
+
+// GetACLEvaluator returns an evaluator with the server's system policies and the policies of projects
+func (c *Client) GetACLEvaluator(projects ...string) (*ACLEvaluator, error) {
+	return c.GetACLEvaluatorContext(context.Background(), projects...)
+}
+
+// GetACLEvaluatorContext returns an evaluator with the server's system policies and the policies of projects
+func (c *Client) GetACLEvaluatorContext(ctx context.Context, projects ...string) (*ACLEvaluator, error) {
+	e := NewACLEvaluator()
+	list, err := c.ListSystemACLPoliciesContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range list.Resources {
+		name := strings.TrimSuffix(r.Name, ".aclpolicy")
+		data, err := c.GetSystemACLPolicyContext(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		policies, err := ParseACLPolicy(data)
+		if err != nil {
+			return nil, fmt.Errorf("system policy %s: %s", name, err)
+		}
+		e.AddPolicies("system/"+r.Name, policies...)
+	}
+	for _, project := range projects {
+		list, err := c.ListProjectACLPoliciesContext(ctx, project)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range list.Resources {
+			name := strings.TrimSuffix(r.Name, ".aclpolicy")
+			data, err := c.GetProjectACLPolicyContext(ctx, project, name)
+			if err != nil {
+				return nil, err
+			}
+			policies, err := ParseACLPolicy(data)
+			if err != nil {
+				return nil, fmt.Errorf("project %s policy %s: %s", project, name, err)
+			}
+			e.AddProjectPolicies(project, "project/"+project+"/"+r.Name, policies...)
+		}
+	}
+	return e, nil
+}
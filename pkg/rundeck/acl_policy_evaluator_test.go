@@ -0,0 +1,122 @@
+package rundeck
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	responses "github.com/lusis/go-rundeck/pkg/rundeck/responses"
+	"github.com/stretchr/testify/require"
+)
+
+func testACLEvaluator(t *testing.T) *ACLEvaluator {
+	e := NewACLEvaluator()
+	policies, err := ParseACLPolicy([]byte(testACLPolicy))
+	require.NoError(t, err)
+	e.AddPolicies("admin.aclpolicy", policies...)
+	project, err := ParseACLPolicy([]byte(`description: deployers
+for:
+  job:
+    - match:
+        group: 'app(/.*)?'
+      allow: [read, run]
+    - equals:
+        name: drop-tables
+      deny: '*'
+  node:
+    - subset:
+        tags: [web, prod]
+      allow: run
+by:
+  group: deploy
+`))
+	require.NoError(t, err)
+	e.AddProjectPolicies("shop", "shop.aclpolicy", project...)
+	return e
+}
+
+func TestACLEvaluatorEvaluate(t *testing.T) {
+	e := testACLEvaluator(t)
+	admin := &ACLSubject{Username: "root", Groups: []string{"admin"}}
+	deployer := &ACLSubject{Username: "bob", Groups: []string{"users", "deploy"}}
+	job := func(project, group, name string) *ACLResource {
+		return &ACLResource{Type: "job", Project: project, Attributes: map[string]string{"group": group, "name": name}}
+	}
+
+	d := e.Evaluate(admin, "run", job("anything", "app/web", "deploy-web"))
+	require.True(t, d.Allowed)
+	require.Len(t, d.Matches, 1)
+	require.Equal(t, "admin.aclpolicy", d.Matches[0].Source)
+	require.Equal(t, 11, d.Matches[0].Line)
+	require.Equal(t, "match{group=app/.* name=deploy.*} allow[*]", d.Matches[0].Rule.String())
+
+	d = e.Evaluate(admin, "run", job("anything", "app/web", "secret"))
+	require.False(t, d.Allowed)
+	require.True(t, d.Matches[0].Deny)
+	require.Equal(t, 15, d.Matches[0].Line)
+
+	d = e.Evaluate(deployer, "run", job("shop", "app", "deploy"))
+	require.True(t, d.Allowed)
+	require.Equal(t, "shop.aclpolicy", d.Matches[0].Source)
+	require.Equal(t, 4, d.Matches[0].Line)
+	require.False(t, e.Evaluate(deployer, "delete", job("shop", "app", "deploy")).Allowed)
+	require.False(t, e.Evaluate(deployer, "run", job("shop", "application", "deploy")).Allowed)
+	require.False(t, e.Evaluate(deployer, "run", job("shop", "app", "drop-tables")).Allowed)
+
+	d = e.Evaluate(deployer, "run", job("other", "app", "deploy"))
+	require.False(t, d.Allowed)
+	require.Empty(t, d.Matches)
+	require.Equal(t, "no policy applies to bob (users,deploy) in this context", d.Reason)
+
+	node := func(tags string) *ACLResource {
+		return &ACLResource{Type: "node", Project: "shop", Attributes: map[string]string{"nodename": "web1", "tags": tags}}
+	}
+	require.True(t, e.Evaluate(deployer, "run", node("web, prod")).Allowed)
+	require.False(t, e.Evaluate(deployer, "run", node("web,db")).Allowed)
+	require.False(t, e.Evaluate(admin, "read", node("web,prod,db")).Allowed)
+	require.False(t, e.Evaluate(admin, "read", node("web")).Allowed)
+
+	project := &ACLResource{Type: "project", Attributes: map[string]string{"name": "shop"}}
+	require.True(t, e.Evaluate(&ACLSubject{Username: "opsy"}, "admin", project).Allowed)
+	d = e.Evaluate(&ACLSubject{Username: "alice"}, "read", &ACLResource{Type: "storage", Attributes: map[string]string{"path": "keys/a"}})
+	require.False(t, d.Allowed)
+	require.Equal(t, "no rule allows read", d.Reason)
+	require.False(t, e.Evaluate(&ACLSubject{Username: "alicex"}, "read", project).Allowed)
+}
+
+func TestGetACLEvaluator(t *testing.T) {
+	system, err := responses.GetTestData("foo.aclpolicy")
+	require.NoError(t, err)
+	project, err := responses.GetTestData("project.aclpolicy")
+	require.NoError(t, err)
+	client, server, err := newTestRundeckClientWithHandler(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/system/acl/"):
+			_, _ = w.Write([]byte(`{"path":"","type":"directory","resources":[{"path":"admin.aclpolicy","type":"file","name":"admin.aclpolicy"}]}`))
+		case strings.HasSuffix(r.URL.Path, "/system/acl/admin.aclpolicy"):
+			_, _ = w.Write(system)
+		case strings.HasSuffix(r.URL.Path, "/project/shop/acl/"):
+			_, _ = w.Write([]byte(`{"path":"","type":"directory","resources":[{"path":"auser.aclpolicy","type":"file","name":"auser.aclpolicy"}]}`))
+		case strings.HasSuffix(r.URL.Path, "/project/shop/acl/auser.aclpolicy"):
+			_, _ = w.Write(project)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	defer server.Close()
+	require.NoError(t, err)
+
+	e, err := client.GetACLEvaluator("shop")
+	require.NoError(t, err)
+	auser := &ACLSubject{Username: "auser"}
+	job := &ACLResource{Type: "job", Project: "shop", Attributes: map[string]string{"name": "x"}}
+	d := e.Evaluate(auser, "run", job)
+	require.True(t, d.Allowed)
+	require.Equal(t, "project/shop/auser.aclpolicy", d.Matches[0].Source)
+	job.Project = "other"
+	require.False(t, e.Evaluate(auser, "run", job).Allowed)
+
+	d = e.Evaluate(&ACLSubject{Username: "x", Groups: []string{"admin"}}, "create", &ACLResource{Type: "storage", Attributes: map[string]string{"path": "keys/x"}})
+	require.True(t, d.Allowed)
+	require.Equal(t, "system/admin.aclpolicy", d.Matches[0].Source)
+}
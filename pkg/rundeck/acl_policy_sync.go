@@ -0,0 +1,310 @@
+package rundeck
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// ACLPolicySyncAction is what applying a sync plan does to a policy
+type ACLPolicySyncAction string
+
+const (
+	// ACLPolicySyncCreate is a policy that only exists locally
+	ACLPolicySyncCreate ACLPolicySyncAction = "create"
+	// ACLPolicySyncUpdate is a policy whose local contents differ from the server's
+	ACLPolicySyncUpdate ACLPolicySyncAction = "update"
+	// ACLPolicySyncDelete is a policy that only exists on the server
+	ACLPolicySyncDelete ACLPolicySyncAction = "delete"
+)
+
+// aclPolicyName is the set of names rundeck allows for policy files
+var aclPolicyName = regexp.MustCompile(`^[a-zA-Z0-9,.+_-]+$`)
+
+// ACLPolicyFile is the contents of an aclpolicy file along with the policy name it is stored as
+type ACLPolicyFile struct {
+	// Name is the file name without the .aclpolicy extension
+	Name     string
+	Path     string
+	Contents []byte
+}
+
+// ACLPolicySyncChange is a single policy in a sync plan
+type ACLPolicySyncChange struct {
+	Action ACLPolicySyncAction
+	Name   string
+	// Source is the file the policy was read from. It is empty for deletes
+	Source   string
+	Contents []byte
+	// Current is the server's policy when the plan was made. It is nil for creates
+	// and is used to undo the change if applying the plan fails
+	Current []byte
+	// Diff is a unified diff from the server's policy to the local one
+	Diff string
+}
+
+// ACLPolicySyncPlan is the set of changes needed to make the server's policies match a set of files
+type ACLPolicySyncPlan struct {
+	// Project is the project the policies are stored in. It is empty for system policies
+	Project string
+	Create  []*ACLPolicySyncChange
+	Update  []*ACLPolicySyncChange
+	Delete  []*ACLPolicySyncChange
+	// Unchanged are the names of policies that already match
+	Unchanged []string
+}
+
+// Changes returns every change in the plan in the order they are applied
+func (p *ACLPolicySyncPlan) Changes() []*ACLPolicySyncChange {
+	var changes []*ACLPolicySyncChange
+	changes = append(changes, p.Create...)
+	changes = append(changes, p.Update...)
+	return append(changes, p.Delete...)
+}
+
+// ReadACLPolicyDir reads the .aclpolicy files in a directory
+// Every file is validated as a system policy, or a project policy when project is true, before any are returned
+// so a partly invalid set of policies is never applied
+func ReadACLPolicyDir(dir string, project bool) ([]*ACLPolicyFile, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.aclpolicy"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+	var files []*ACLPolicyFile
+	var errs []error
+	for _, path := range paths {
+		data, err := ioutil.ReadFile(path) // nolint: gosec
+		if err != nil {
+			return nil, err
+		}
+		name := strings.TrimSuffix(filepath.Base(path), ".aclpolicy")
+		if !aclPolicyName.MatchString(name) {
+			errs = append(errs, fmt.Errorf("%s: invalid policy name %q", path, name))
+		}
+		validate := ValidateACLPolicy
+		if project {
+			validate = ValidateProjectACLPolicy
+		}
+		if vErr := validate(data); vErr != nil {
+			if pErr, ok := vErr.(*PolicyValidationError); ok {
+				for _, e := range pErr.Errors {
+					errs = append(errs, fmt.Errorf("%s: %s", path, e))
+				}
+			} else {
+				errs = append(errs, fmt.Errorf("%s: %s", path, vErr))
+			}
+		}
+		files = append(files, &ACLPolicyFile{Name: name, Path: path, Contents: data})
+	}
+	if len(errs) > 0 {
+		return nil, policyValidationError(errs)
+	}
+	return files, nil
+}
+
+// PlanACLPolicySync compares policy files with the system policies, or a project's policies when project isn't empty
+// Policies on the server with no matching file are only planned for deletion when prune is true
+func (c *Client) PlanACLPolicySync(project string, files []*ACLPolicyFile, prune bool) (*ACLPolicySyncPlan, error) {
+	return c.PlanACLPolicySyncContext(context.Background(), project, files, prune)
+}
+
+// PlanACLPolicySyncContext compares policy files with the system policies, or a project's policies when project isn't empty
+func (c *Client) PlanACLPolicySyncContext(ctx context.Context, project string, files []*ACLPolicyFile, prune bool) (*ACLPolicySyncPlan, error) {
+	var list *ACLPolicies
+	var err error
+	if project == "" {
+		list, err = c.ListSystemACLPoliciesContext(ctx)
+	} else {
+		list, err = c.ListProjectACLPoliciesContext(ctx, project)
+	}
+	if err != nil {
+		return nil, err
+	}
+	existing := map[string]bool{}
+	for _, r := range list.Resources {
+		existing[strings.TrimSuffix(r.Name, ".aclpolicy")] = true
+	}
+
+	plan := &ACLPolicySyncPlan{Project: project}
+	local := map[string]bool{}
+	for _, f := range files {
+		if local[f.Name] {
+			return nil, fmt.Errorf("policy %s is defined more than once (%s)", f.Name, f.Path)
+		}
+		local[f.Name] = true
+		change := &ACLPolicySyncChange{Name: f.Name, Source: f.Path, Contents: f.Contents}
+		if !existing[f.Name] {
+			change.Action = ACLPolicySyncCreate
+			change.Diff = aclPolicyDiff(f.Name, nil, f.Path, f.Contents)
+			plan.Create = append(plan.Create, change)
+			continue
+		}
+		current, err := c.getACLPolicy(ctx, project, f.Name)
+		if err != nil {
+			return nil, err
+		}
+		if normalizeACLPolicy(current) == normalizeACLPolicy(f.Contents) {
+			plan.Unchanged = append(plan.Unchanged, f.Name)
+			continue
+		}
+		change.Action = ACLPolicySyncUpdate
+		change.Current = current
+		change.Diff = aclPolicyDiff(f.Name, current, f.Path, f.Contents)
+		plan.Update = append(plan.Update, change)
+	}
+	if prune {
+		names := make([]string, 0, len(existing))
+		for name := range existing {
+			if !local[name] {
+				names = append(names, name)
+			}
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			current, err := c.getACLPolicy(ctx, project, name)
+			if err != nil {
+				return nil, err
+			}
+			plan.Delete = append(plan.Delete, &ACLPolicySyncChange{
+				Action:  ACLPolicySyncDelete,
+				Name:    name,
+				Current: current,
+				Diff:    aclPolicyDiff(name, current, "", nil),
+			})
+		}
+	}
+	return plan, nil
+}
+
+func (c *Client) getACLPolicy(ctx context.Context, project, name string) ([]byte, error) {
+	if project == "" {
+		return c.GetSystemACLPolicyContext(ctx, name)
+	}
+	return c.GetProjectACLPolicyContext(ctx, project, name)
+}
+
+// normalizeACLPolicy ignores line ending and trailing whitespace differences the server may introduce
+func normalizeACLPolicy(data []byte) string {
+	return strings.TrimRight(string(bytes.Replace(data, []byte("\r\n"), []byte("\n"), -1)), " \t\n")
+}
+
+// aclPolicyDiff returns a unified diff between policies. A nil policy is one that doesn't exist
+func aclPolicyDiff(name string, from []byte, toFile string, to []byte) string {
+	lines := func(data []byte) []string {
+		if data == nil {
+			return nil
+		}
+		return difflib.SplitLines(normalizeACLPolicy(data) + "\n")
+	}
+	fromFile := name + ".aclpolicy (server)"
+	if from == nil {
+		fromFile = "/dev/null"
+	}
+	if to == nil {
+		toFile = "/dev/null"
+	}
+	diff, _ := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{ // nolint: gosec
+		A:        lines(from),
+		B:        lines(to),
+		FromFile: fromFile,
+		ToFile:   toFile,
+		Context:  3,
+	})
+	return diff
+}
+
+// ACLPolicySyncError is returned when the server rejects a change while applying a sync plan
+type ACLPolicySyncError struct {
+	// Failed is the change the server rejected
+	Failed *ACLPolicySyncChange
+	Err    error
+	// RolledBack are the changes applied before the failure that were undone
+	RolledBack []*ACLPolicySyncChange
+	// RollbackErrors are the changes that could not be undone and are still applied
+	RollbackErrors map[*ACLPolicySyncChange]error
+}
+
+func (e *ACLPolicySyncError) Error() string {
+	msg := fmt.Sprintf("%s of policy %s failed: %s", e.Failed.Action, e.Failed.Name, e.Err)
+	if len(e.RolledBack) > 0 {
+		msg += fmt.Sprintf("; rolled back %d applied change(s)", len(e.RolledBack))
+	}
+	names := make([]string, 0, len(e.RollbackErrors))
+	for change, err := range e.RollbackErrors {
+		names = append(names, fmt.Sprintf("%s of policy %s (%s)", change.Action, change.Name, err))
+	}
+	sort.Strings(names)
+	if len(names) > 0 {
+		msg += "; could not roll back " + strings.Join(names, ", ") + " and the previous policies are in the plan"
+	}
+	return msg
+}
+
+// ApplyACLPolicySyncPlan creates, updates and then deletes the planned policies
+// If the server rejects a change, the changes applied before it are undone using the server's policies
+// recorded in the plan and an *ACLPolicySyncError is returned. The changes that are still applied are returned
+func (c *Client) ApplyACLPolicySyncPlan(plan *ACLPolicySyncPlan) ([]*ACLPolicySyncChange, error) {
+	return c.ApplyACLPolicySyncPlanContext(context.Background(), plan)
+}
+
+// ApplyACLPolicySyncPlanContext creates, updates and then deletes the planned policies
+// Changes are undone after a failure even if ctx is done so the server isn't left with a partial set of policies
+func (c *Client) ApplyACLPolicySyncPlanContext(ctx context.Context, plan *ACLPolicySyncPlan) ([]*ACLPolicySyncChange, error) {
+	var applied []*ACLPolicySyncChange
+	for _, change := range plan.Changes() {
+		if err := c.setACLPolicy(ctx, plan.Project, change.Action, change.Name, change.Contents); err != nil {
+			syncErr := &ACLPolicySyncError{Failed: change, Err: err, RollbackErrors: map[*ACLPolicySyncChange]error{}}
+			var remaining []*ACLPolicySyncChange
+			for i := len(applied) - 1; i >= 0; i-- {
+				undo := applied[i]
+				if rbErr := c.undoACLPolicyChange(context.Background(), plan.Project, undo); rbErr != nil {
+					syncErr.RollbackErrors[undo] = rbErr
+					remaining = append([]*ACLPolicySyncChange{undo}, remaining...)
+					continue
+				}
+				syncErr.RolledBack = append(syncErr.RolledBack, undo)
+			}
+			return remaining, syncErr
+		}
+		applied = append(applied, change)
+	}
+	return applied, nil
+}
+
+// undoACLPolicyChange puts back the server's policy from before change was applied
+func (c *Client) undoACLPolicyChange(ctx context.Context, project string, change *ACLPolicySyncChange) error {
+	switch change.Action {
+	case ACLPolicySyncCreate:
+		return c.setACLPolicy(ctx, project, ACLPolicySyncDelete, change.Name, nil)
+	case ACLPolicySyncUpdate:
+		return c.setACLPolicy(ctx, project, ACLPolicySyncUpdate, change.Name, change.Current)
+	default:
+		return c.setACLPolicy(ctx, project, ACLPolicySyncCreate, change.Name, change.Current)
+	}
+}
+
+// setACLPolicy performs a single sync action on a system policy or, when project isn't empty, a project policy
+func (c *Client) setACLPolicy(ctx context.Context, project string, action ACLPolicySyncAction, name string, contents []byte) error {
+	switch {
+	case action == ACLPolicySyncCreate && project == "":
+		return c.CreateSystemACLPolicyContext(ctx, name, bytes.NewReader(contents))
+	case action == ACLPolicySyncCreate:
+		return c.CreateProjectACLPolicyContext(ctx, project, name, bytes.NewReader(contents))
+	case action == ACLPolicySyncUpdate && project == "":
+		return c.UpdateSystemACLPolicyContext(ctx, name, bytes.NewReader(contents))
+	case action == ACLPolicySyncUpdate:
+		return c.UpdateProjectACLPolicyContext(ctx, project, name, bytes.NewReader(contents))
+	case action == ACLPolicySyncDelete && project == "":
+		return c.DeleteSystemACLPolicyContext(ctx, name)
+	default:
+		return c.DeleteProjectACLPolicyContext(ctx, project, name)
+	}
+}
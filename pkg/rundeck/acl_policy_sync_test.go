@@ -0,0 +1,169 @@
+package rundeck
+
+import (
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testProjectPolicy = `description: deployers
+for:
+  job:
+    - allow: [read, run]
+by:
+  group: deploy
+`
+
+func testACLPolicyDir(t *testing.T, files map[string]string) (string, func()) {
+	dir, err := ioutil.TempDir("", "policies")
+	require.NoError(t, err)
+	for name, content := range files {
+		require.NoError(t, ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0600))
+	}
+	return dir, func() { _ = os.RemoveAll(dir) }
+}
+
+func TestReadACLPolicyDir(t *testing.T) {
+	dir, cleanup := testACLPolicyDir(t, map[string]string{
+		"deploy.aclpolicy": testProjectPolicy,
+		"readme.md":        "ignored",
+	})
+	defer cleanup()
+	files, err := ReadACLPolicyDir(dir, true)
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	require.Equal(t, "deploy", files[0].Name)
+
+	_, err = ReadACLPolicyDir(dir, false)
+	require.IsType(t, &PolicyValidationError{}, err)
+	require.Contains(t, err.Error(), "deploy.aclpolicy: line 1: context is required")
+
+	bad, badCleanup := testACLPolicyDir(t, map[string]string{
+		"ok.aclpolicy":       testProjectPolicy,
+		"bad name.aclpolicy": testProjectPolicy,
+		"broken.aclpolicy":   "description: broken\nfor:\n  job:\n    - match:\n        name: x\nby:\n  group: a\n",
+	})
+	defer badCleanup()
+	_, err = ReadACLPolicyDir(bad, true)
+	require.Error(t, err)
+	errs := err.(*PolicyValidationError).Errors
+	require.Len(t, errs, 2)
+	require.Contains(t, errs[0].Error(), `invalid policy name "bad name"`)
+	require.Contains(t, errs[1].Error(), "broken.aclpolicy: line 4: for.job[0]: rule must allow or deny")
+}
+
+func TestACLPolicySync(t *testing.T) {
+	server := map[string]string{
+		"deploy": strings.Replace(testProjectPolicy, "[read, run]", "[read]", 1),
+		"same":   testProjectPolicy + "\n\n",
+		"old":    testProjectPolicy,
+	}
+	var calls []string
+	reject := map[string]bool{"update deploy.aclpolicy": true}
+	restored := map[string]string{}
+	client, srv, err := newTestRundeckClientWithHandler(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		path := r.URL.Path
+		switch {
+		case r.Method == http.MethodGet && strings.HasSuffix(path, "/project/shop/acl/"):
+			var resources []string
+			for name := range server {
+				resources = append(resources, `{"path":"`+name+`.aclpolicy","type":"file","name":"`+name+`.aclpolicy"}`)
+			}
+			_, _ = w.Write([]byte(`{"path":"","type":"directory","resources":[` + strings.Join(resources, ",") + `]}`))
+		case r.Method == http.MethodGet:
+			name := strings.TrimSuffix(filepath.Base(path), ".aclpolicy")
+			_, _ = w.Write([]byte(server[name]))
+		default:
+			call := map[string]string{http.MethodPost: "create ", http.MethodPut: "update ", http.MethodDelete: "delete "}[r.Method] + filepath.Base(path)
+			calls = append(calls, call)
+			if reject[call] {
+				w.WriteHeader(400)
+				_, _ = w.Write([]byte(`{"valid":false,"policies":[{"policy":"` + filepath.Base(path) + `","errors":["rejected"]}]}`))
+				return
+			}
+			body, _ := ioutil.ReadAll(r.Body)
+			restored[call] = string(body)
+			switch r.Method {
+			case http.MethodPost:
+				w.WriteHeader(201)
+				_, _ = w.Write([]byte(`{}`))
+			case http.MethodPut:
+				_, _ = w.Write([]byte(`{}`))
+			default:
+				w.WriteHeader(204)
+			}
+		}
+	})
+	defer srv.Close()
+	require.NoError(t, err)
+
+	files := []*ACLPolicyFile{
+		{Name: "deploy", Path: "acls/deploy.aclpolicy", Contents: []byte(testProjectPolicy)},
+		{Name: "same", Path: "acls/same.aclpolicy", Contents: []byte(testProjectPolicy)},
+		{Name: "new", Path: "acls/new.aclpolicy", Contents: []byte(testProjectPolicy)},
+	}
+	plan, err := client.PlanACLPolicySync("shop", files, false)
+	require.NoError(t, err)
+	require.Len(t, plan.Create, 1)
+	require.Equal(t, "new", plan.Create[0].Name)
+	require.Contains(t, plan.Create[0].Diff, "--- /dev/null\n+++ acls/new.aclpolicy\n")
+	require.Len(t, plan.Update, 1)
+	require.Equal(t, "deploy", plan.Update[0].Name)
+	require.Contains(t, plan.Update[0].Diff, "--- deploy.aclpolicy (server)\n+++ acls/deploy.aclpolicy\n")
+	require.Contains(t, plan.Update[0].Diff, "-    - allow: [read]\n+    - allow: [read, run]\n")
+	require.Equal(t, []string{"same"}, plan.Unchanged)
+	require.Empty(t, plan.Delete)
+
+	_, err = client.PlanACLPolicySync("shop", append(files, files[0]), false)
+	require.Error(t, err)
+
+	plan, err = client.PlanACLPolicySync("shop", files, true)
+	require.NoError(t, err)
+	require.Len(t, plan.Delete, 1)
+	require.Equal(t, "old", plan.Delete[0].Name)
+	require.Contains(t, plan.Delete[0].Diff, "+++ /dev/null")
+
+	require.Equal(t, server["deploy"], string(plan.Update[0].Current))
+	require.Equal(t, server["old"], string(plan.Delete[0].Current))
+	require.Nil(t, plan.Create[0].Current)
+
+	// the create before the rejected update is undone
+	applied, err := client.ApplyACLPolicySyncPlan(plan)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "update of policy deploy failed")
+	require.Contains(t, err.Error(), "rolled back 1 applied change(s)")
+	syncErr := err.(*ACLPolicySyncError)
+	require.Equal(t, plan.Update[0], syncErr.Failed)
+	require.Equal(t, []*ACLPolicySyncChange{plan.Create[0]}, syncErr.RolledBack)
+	require.Empty(t, applied)
+	require.Equal(t, []string{"create new.aclpolicy", "update deploy.aclpolicy", "delete new.aclpolicy"}, calls)
+
+	// every change before a rejected delete is undone in reverse order with the server's old policies
+	calls = nil
+	reject = map[string]bool{"delete old.aclpolicy": true, "delete new.aclpolicy": true}
+	applied, err = client.ApplyACLPolicySyncPlan(plan)
+	require.Error(t, err)
+	require.Equal(t, []string{
+		"create new.aclpolicy", "update deploy.aclpolicy", "delete old.aclpolicy",
+		"update deploy.aclpolicy", "delete new.aclpolicy",
+	}, calls)
+	require.Equal(t, server["deploy"], restored["update deploy.aclpolicy"])
+	require.Equal(t, []*ACLPolicySyncChange{plan.Create[0]}, applied)
+	syncErr = err.(*ACLPolicySyncError)
+	require.Equal(t, []*ACLPolicySyncChange{plan.Update[0]}, syncErr.RolledBack)
+	require.Contains(t, syncErr.RollbackErrors, plan.Create[0])
+	require.Contains(t, err.Error(), "could not roll back create of policy new")
+
+	calls = nil
+	reject = map[string]bool{}
+	applied, err = client.ApplyACLPolicySyncPlan(plan)
+	require.NoError(t, err)
+	require.Len(t, applied, 3)
+	require.Equal(t, []string{"create new.aclpolicy", "update deploy.aclpolicy", "delete old.aclpolicy"}, calls)
+}
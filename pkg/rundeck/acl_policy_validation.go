@@ -0,0 +1,209 @@
+package rundeck
+
+import (
+	"fmt"
+	"regexp"
+	"regexp/syntax"
+	"sort"
+
+	multierror "github.com/hashicorp/go-multierror"
+)
+
+// aclPolicyTypes are the resource types each policy context can have rules for
+var aclPolicyTypes = map[string][]string{
+	"project":     {"resource", "job", "node", "adhoc"},
+	"application": {"resource", "project", "project_acl", "storage", "system_acl"},
+}
+
+// ValidateACLPolicy parses and validates the documents of an aclpolicy file
+// Errors are *ACLPolicyError values with the line of the file they were found on
+func ValidateACLPolicy(data []byte) error {
+	return validateACLPolicy(data, false)
+}
+
+// ValidateProjectACLPolicy validates an aclpolicy file stored in a project
+// Project policies don't have a context. rundeck sets it to the project they are stored in
+func ValidateProjectACLPolicy(data []byte) error {
+	return validateACLPolicy(data, true)
+}
+
+func validateACLPolicy(data []byte, project bool) error {
+	policies, errs := parseACLPolicy(data)
+	for _, p := range policies {
+		errs = append(errs, p.validate(project)...)
+	}
+	return policyValidationError(errs)
+}
+
+// Validate checks a policy document for problems rundeck would reject it for
+func (p *ACLPolicy) Validate() error {
+	return policyValidationError(p.validate(false))
+}
+
+func policyValidationError(errs []error) error {
+	if len(errs) == 0 {
+		return nil
+	}
+	return &PolicyValidationError{msg: multierror.Append(errValidation, errs...).Error(), Errors: errs}
+}
+
+func (p *ACLPolicy) validate(project bool) []error {
+	var errs []error
+	fail := func(path []interface{}, format string, args ...interface{}) {
+		errs = append(errs, &ACLPolicyError{Line: p.lines.line(path...), Path: policyPath(path...), Message: fmt.Sprintf(format, args...)})
+	}
+	if p.Description == "" {
+		fail(nil, "description is required")
+	}
+
+	context := ""
+	switch {
+	case project && p.Context != nil:
+		fail([]interface{}{"context"}, "context must not be set in a project policy")
+	case project:
+		context = "project"
+	case p.Context == nil:
+		fail(nil, "context is required")
+	case p.Context.Project != "" && p.Context.Application != "":
+		fail([]interface{}{"context"}, "context must have either project or application, not both")
+	case p.Context.Project != "":
+		context = "project"
+		if err := validPolicyRegex(p.Context.Project); err != nil {
+			fail([]interface{}{"context", "project"}, "%s", err)
+		}
+	case p.Context.Application != "":
+		context = "application"
+		if p.Context.Application != "rundeck" {
+			fail([]interface{}{"context", "application"}, "application must be rundeck")
+		}
+	default:
+		fail([]interface{}{"context"}, "context must have either project or application")
+	}
+
+	if len(p.For) == 0 {
+		fail(nil, "for is required")
+	}
+	types := make([]string, 0, len(p.For))
+	for t := range p.For {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+	for _, t := range types {
+		path := []interface{}{"for", t}
+		if context != "" && !stringInSlice(t, aclPolicyTypes[context]) {
+			fail(path, "%s is not a resource type in %s context", t, context)
+		}
+		if len(p.For[t]) == 0 {
+			fail(path, "at least one rule is required")
+		}
+		for i, rule := range p.For[t] {
+			rulePath := append(path[:len(path):len(path)], i)
+			if rule == nil {
+				fail(rulePath, "rule is empty")
+				continue
+			}
+			for _, e := range rule.validate() {
+				fail(append(rulePath, e.path...), "%s", e.msg)
+			}
+		}
+	}
+
+	switch {
+	case p.By == nil:
+		fail(nil, "by is required")
+	case len(p.By.Group) == 0 && len(p.By.Username) == 0:
+		fail([]interface{}{"by"}, "by must have a group or username")
+	default:
+		for _, s := range []struct {
+			key    string
+			values ACLPolicyStrings
+		}{{"group", p.By.Group}, {"username", p.By.Username}} {
+			for i, v := range s.values {
+				if err := validPolicyRegex(v); err != nil {
+					fail([]interface{}{"by", s.key, i}, "%s", err)
+				}
+			}
+		}
+	}
+	return errs
+}
+
+type policyRuleError struct {
+	path []interface{}
+	msg  string
+}
+
+func (r *ACLPolicyRule) validate() []policyRuleError {
+	var errs []policyRuleError
+	fail := func(path []interface{}, format string, args ...interface{}) {
+		errs = append(errs, policyRuleError{path: path, msg: fmt.Sprintf(format, args...)})
+	}
+	if len(r.Allow) == 0 && len(r.Deny) == 0 {
+		fail(nil, "rule must allow or deny at least one action")
+	}
+	for _, a := range []struct {
+		key     string
+		actions ACLPolicyStrings
+	}{{"allow", r.Allow}, {"deny", r.Deny}} {
+		for i, action := range a.actions {
+			if action == "" {
+				fail([]interface{}{a.key, i}, "action cannot be empty")
+			}
+		}
+	}
+	for _, m := range []struct {
+		key    string
+		values map[string]ACLPolicyStrings
+	}{
+		{"equals", singlePolicyStrings(r.Equals)},
+		{"match", singlePolicyStrings(r.Match)},
+		{"contains", r.Contains},
+		{"subset", r.Subset},
+	} {
+		attrs := make([]string, 0, len(m.values))
+		for attr := range m.values {
+			attrs = append(attrs, attr)
+		}
+		sort.Strings(attrs)
+		for _, attr := range attrs {
+			if attr == "" {
+				fail([]interface{}{m.key}, "attribute name cannot be empty")
+			}
+			if m.key != "match" {
+				continue
+			}
+			for _, v := range m.values[attr] {
+				if err := validPolicyRegex(v); err != nil {
+					fail([]interface{}{m.key, attr}, "%s", err)
+				}
+			}
+		}
+	}
+	return errs
+}
+
+func singlePolicyStrings(m map[string]string) map[string]ACLPolicyStrings {
+	out := make(map[string]ACLPolicyStrings, len(m))
+	for k, v := range m {
+		out[k] = ACLPolicyStrings{v}
+	}
+	return out
+}
+
+// validPolicyRegex checks a policy's regular expression
+// rundeck uses java regular expressions but they are checked with go's RE2 syntax, which doesn't support
+// lookarounds, backreferences or possessive quantifiers, so the error says so when one of those may be the cause
+func validPolicyRegex(expr string) error {
+	_, err := regexp.Compile(expr)
+	if err == nil {
+		return nil
+	}
+	if sErr, ok := err.(*syntax.Error); ok {
+		switch sErr.Code {
+		case syntax.ErrInvalidPerlOp, syntax.ErrInvalidEscape, syntax.ErrInvalidRepeatOp:
+			return fmt.Errorf("invalid regular expression %q: %s (expressions are checked with go's RE2 syntax, "+
+				"which lacks java features like lookarounds, backreferences and possessive quantifiers that rundeck accepts)", expr, err)
+		}
+	}
+	return fmt.Errorf("invalid regular expression %q: %s", expr, err)
+}
@@ -0,0 +1,99 @@
+package rundeck
+
+import (
+	"testing"
+
+	responses "github.com/lusis/go-rundeck/pkg/rundeck/responses"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateACLPolicy(t *testing.T) {
+	require.NoError(t, ValidateACLPolicy([]byte(testACLPolicy)))
+
+	err := ValidateACLPolicy([]byte(`description: broken
+context:
+  project: '[a-'
+for:
+  storage:
+    - allow: read
+  job:
+    - match:
+        name: '(deploy'
+    - equals:
+        name: x
+      allow: ['']
+by:
+  username: alice
+---
+context:
+  application: other
+for:
+  job:
+    - allow: run
+by: {}
+`))
+	require.IsType(t, &PolicyValidationError{}, err)
+	errs := err.(*PolicyValidationError).Errors
+	var got []string
+	for _, e := range errs {
+		got = append(got, e.Error())
+	}
+	require.Equal(t, []string{
+		`line 3: context.project: invalid regular expression "[a-": error parsing regexp: missing closing ]: ` + "`[a-`",
+		`line 8: for.job[0]: rule must allow or deny at least one action`,
+		`line 9: for.job[0].match.name: invalid regular expression "(deploy": error parsing regexp: missing closing ): ` + "`(deploy`",
+		`line 12: for.job[1].allow[0]: action cannot be empty`,
+		`line 5: for.storage: storage is not a resource type in project context`,
+		`line 16: description is required`,
+		`line 17: context.application: application must be rundeck`,
+		`line 19: for.job: job is not a resource type in application context`,
+		`line 21: by: by must have a group or username`,
+	}, got)
+}
+
+func TestValidateACLPolicyJavaRegex(t *testing.T) {
+	err := ValidateProjectACLPolicy([]byte(`description: lookahead
+for:
+  job:
+    - match:
+        name: '(?!test).*'
+      allow: run
+by:
+  group: dev
+`))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `line 5: for.job[0].match.name: invalid regular expression "(?!test).*"`)
+	require.Contains(t, err.Error(), "checked with go's RE2 syntax, which lacks java features like lookarounds")
+}
+
+func TestACLPolicyValidate(t *testing.T) {
+	p := &ACLPolicy{Description: "built in code"}
+	err := p.Validate()
+	require.Error(t, err)
+	require.Equal(t, []string{"context is required", "for is required", "by is required"}, func() []string {
+		var msgs []string
+		for _, e := range err.(*PolicyValidationError).Errors {
+			msgs = append(msgs, e.Error())
+		}
+		return msgs
+	}())
+
+	p.Context = &ACLPolicyContext{Project: "test"}
+	p.For = map[string][]*ACLPolicyRule{"job": {{Allow: ACLPolicyStrings{"run"}}}}
+	p.By = &ACLPolicySubjects{Group: ACLPolicyStrings{"dev"}}
+	require.NoError(t, p.Validate())
+}
+
+func TestValidateProjectACLPolicy(t *testing.T) {
+	data, err := responses.GetTestData("project.aclpolicy")
+	require.NoError(t, err)
+	require.NoError(t, ValidateProjectACLPolicy(data))
+	require.Error(t, ValidateACLPolicy(data))
+
+	data, err = responses.GetTestData("foo.aclpolicy")
+	require.NoError(t, err)
+	require.NoError(t, ValidateACLPolicy(data))
+	err = ValidateProjectACLPolicy(data)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "line 3: context: context must not be set in a project policy")
+}
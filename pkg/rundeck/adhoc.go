@@ -2,6 +2,7 @@ package rundeck
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -55,6 +56,11 @@ func CmdKeepGoing(b bool) AdHocRunOption {
 // RunAdHocCommand runs an adhoc job - all nodes by default
 // http://rundeck.org/docs/api/index.html#running-adhoc-commands
 func (c *Client) RunAdHocCommand(projectID string, exec string, opts ...AdHocRunOption) (*AdHocExecution, error) {
+	return c.RunAdHocCommandContext(context.Background(), projectID, exec, opts...)
+}
+
+// RunAdHocCommandContext runs an adhoc job - all nodes by default
+func (c *Client) RunAdHocCommandContext(ctx context.Context, projectID string, exec string, opts ...AdHocRunOption) (*AdHocExecution, error) {
 	if err := c.checkRequiredAPIVersion(responses.AdHocExecutionResponse{}); err != nil {
 		return nil, err
 	}
@@ -74,7 +80,7 @@ func (c *Client) RunAdHocCommand(projectID string, exec string, opts ...AdHocRun
 	if bodyErr != nil {
 		return nil, bodyErr
 	}
-	res, err := c.httpPost("project/"+projectID+"/run/command", requestExpects(200), requestJSON(), withBody(bytes.NewReader(body)))
+	res, err := c.httpPost(ctx, "project/"+projectID+"/run/command", requestExpects(200), requestJSON(), withBody(bytes.NewReader(body)))
 	if err != nil {
 		return nil, err
 	}
@@ -157,6 +163,11 @@ func ScriptFileExtension(e string) AdHocScriptOption {
 // Because script contents can be overly complicated and large,
 // we do not currently run scripts via json body post
 func (c *Client) RunAdHocScript(projectID string, scriptData io.Reader, opts ...AdHocScriptOption) (*AdHocExecution, error) {
+	return c.RunAdHocScriptContext(context.Background(), projectID, scriptData, opts...)
+}
+
+// RunAdHocScriptContext runs a Script ad-hoc
+func (c *Client) RunAdHocScriptContext(ctx context.Context, projectID string, scriptData io.Reader, opts ...AdHocScriptOption) (*AdHocExecution, error) {
 	if err := c.checkRequiredAPIVersion(responses.AdHocExecutionResponse{}); err != nil {
 		return nil, err
 	}
@@ -176,7 +187,7 @@ func (c *Client) RunAdHocScript(projectID string, scriptData io.Reader, opts ...
 		(*qp)["filter"] = "name: .*"
 	}
 	(*qp)["scriptFile"] = string(scriptBytes)
-	res, err := c.httpPost("project/"+projectID+"/run/script",
+	res, err := c.httpPost(ctx, "project/"+projectID+"/run/script",
 		requestExpects(200),
 		accept("application/json"),
 		contentType("application/x-www-form-urlencoded"),
@@ -262,6 +273,11 @@ func ScriptURLFileExtension(e string) AdHocScriptURLOption {
 // Due to the fact that we must still provide scriptURL as a query param,
 // we do not currently run script urls via json body post
 func (c *Client) RunAdHocScriptFromURL(projectID, scriptURL string, opts ...AdHocScriptURLOption) (*AdHocExecution, error) {
+	return c.RunAdHocScriptFromURLContext(context.Background(), projectID, scriptURL, opts...)
+}
+
+// RunAdHocScriptFromURLContext runs a ScriptURL ad-hoc from a url
+func (c *Client) RunAdHocScriptFromURLContext(ctx context.Context, projectID, scriptURL string, opts ...AdHocScriptURLOption) (*AdHocExecution, error) {
 	if err := c.checkRequiredAPIVersion(responses.AdHocExecutionResponse{}); err != nil {
 		return nil, err
 	}
@@ -277,7 +293,7 @@ func (c *Client) RunAdHocScriptFromURL(projectID, scriptURL string, opts ...AdHo
 		(*qp)["filter"] = defaultNodeFilter
 	}
 	(*qp)["scriptURL"] = scriptURL
-	res, err := c.httpPost("project/"+projectID+"/run/url",
+	res, err := c.httpPost(ctx, "project/"+projectID+"/run/url",
 		requestExpects(200),
 		accept("application/json"),
 		contentType("application/x-www-form-urlencoded"),
@@ -0,0 +1,58 @@
+package archive
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// ACLPolicy is a project acl policy stored in an archive
+type ACLPolicy struct {
+	// Name is the name of the policy without the .aclpolicy extension
+	Name   string
+	Policy []byte
+}
+
+const aclPolicyExt = ".aclpolicy"
+
+// ACLPolicyIterator iterates over the acl policies in an archive
+type ACLPolicyIterator struct {
+	cursor
+	current *ACLPolicy
+}
+
+// ACLPolicies returns an iterator over the acl policies in the archive
+func (a *Archive) ACLPolicies() *ACLPolicyIterator {
+	return &ACLPolicyIterator{cursor: cursor{files: a.list(aclsDir, aclPolicyExt)}}
+}
+
+// Next advances the iterator to the next policy
+// It returns false when there are no more policies or an error occurred
+func (it *ACLPolicyIterator) Next() bool {
+	f, ok := it.next()
+	if !ok {
+		return false
+	}
+	data, err := f.read()
+	if err != nil {
+		it.err = fmt.Errorf("%s: %s", f.name, err)
+		return false
+	}
+	it.current = &ACLPolicy{Name: strings.TrimSuffix(path.Base(f.name), aclPolicyExt), Policy: data}
+	return true
+}
+
+// ACLPolicy returns the current policy
+func (it *ACLPolicyIterator) ACLPolicy() *ACLPolicy {
+	return it.current
+}
+
+// SetACLPolicy adds or replaces an acl policy in the archive
+func (a *Archive) SetACLPolicy(name string, policy []byte) {
+	a.SetFile(aclsDir+name+aclPolicyExt, policy)
+}
+
+// RemoveACLPolicy removes an acl policy from the archive
+func (a *Archive) RemoveACLPolicy(name string) {
+	a.RemoveFile(aclsDir + name + aclPolicyExt)
+}
@@ -0,0 +1,275 @@
+// Package archive reads and writes rundeck project archives without a rundeck server
+//
+// Archives are the zip files made by a project export and read by a project import.
+// Everything in the archive is kept under a rundeck-<project> directory:
+//
+//	jobs/job-<uuid>.xml                job definitions
+//	executions/execution-<id>.xml      executions
+//	executions/output-<id>.rdlog       execution logs
+//	reports/report-<id>.xml            history reports
+//	acls/<name>.aclpolicy              project acl policies
+//	files/etc/project.properties       project configuration
+//	files/readme.md and files/motd.md  project readmes
+package archive
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	manifestPath   = "META-INF/MANIFEST.MF"
+	rootPrefix     = "rundeck-"
+	jobsDir        = "jobs/"
+	executionsDir  = "executions/"
+	aclsDir        = "acls/"
+	propertiesPath = "files/etc/project.properties"
+	// ReadmePath is the path of the project readme inside an archive
+	ReadmePath = "files/readme.md"
+	// MOTDPath is the path of the project message of the day inside an archive
+	MOTDPath = "files/motd.md"
+)
+
+// ErrNotArchive is returned when a zip file isn't a rundeck project archive
+var ErrNotArchive = errors.New("not a rundeck project archive")
+
+// ErrNotFound is returned when a file isn't in the archive
+var ErrNotFound = errors.New("file not found in archive")
+
+// Archive is a rundeck project archive
+// Changes made with the Set and Remove methods are only kept in memory until the archive is written
+type Archive struct {
+	// Project is the name of the project the archive was exported from
+	Project string
+	root    string
+	files   []*file
+	closer  io.Closer
+}
+
+// file is an entry in the archive. data is only set for entries that have been changed
+type file struct {
+	name     string
+	zf       *zip.File
+	data     []byte
+	modified time.Time
+}
+
+func (f *file) read() ([]byte, error) {
+	if f.zf == nil {
+		return f.data, nil
+	}
+	rc, err := f.zf.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close() // nolint: errcheck
+	return ioutil.ReadAll(rc)
+}
+
+// Open opens the archive at path. The archive must be closed when done
+func Open(path string) (*Archive, error) {
+	zr, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, err
+	}
+	a, err := newArchive(&zr.Reader)
+	if err != nil {
+		_ = zr.Close()
+		return nil, err
+	}
+	a.closer = zr
+	return a, nil
+}
+
+// NewReader reads an archive of size bytes from r
+func NewReader(r io.ReaderAt, size int64) (*Archive, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, err
+	}
+	return newArchive(zr)
+}
+
+// New returns an empty archive for project
+func New(project string) *Archive {
+	a := &Archive{Project: project, root: rootPrefix + project + "/"}
+	a.setFile(manifestPath, []byte(manifest(project)))
+	return a
+}
+
+func manifest(project string) string {
+	return "Manifest-Version: 1.0\r\n" +
+		"Rundeck-Archive-Format-Version: 1.0\r\n" +
+		"Rundeck-Archive-Project-Name: " + project + "\r\n" +
+		"Rundeck-Archive-Export-Date: " + time.Now().UTC().Format(time.RFC3339) + "\r\n\r\n"
+}
+
+func newArchive(zr *zip.Reader) (*Archive, error) {
+	a := &Archive{}
+	for _, zf := range zr.File {
+		if a.root == "" && strings.HasPrefix(zf.Name, rootPrefix) {
+			if slash := strings.Index(zf.Name, "/"); slash > 0 {
+				a.root = zf.Name[:slash+1]
+				a.Project = strings.TrimPrefix(a.root[:slash], rootPrefix)
+			}
+		}
+		a.files = append(a.files, &file{name: zf.Name, zf: zf})
+	}
+	if a.root == "" {
+		return nil, ErrNotArchive
+	}
+	return a, nil
+}
+
+// Close closes an archive opened with Open
+func (a *Archive) Close() error {
+	if a.closer == nil {
+		return nil
+	}
+	return a.closer.Close()
+}
+
+// Files returns the paths of every file in the project directory of the archive
+func (a *Archive) Files() []string {
+	var names []string
+	for _, f := range a.files {
+		if strings.HasPrefix(f.name, a.root) && !strings.HasSuffix(f.name, "/") {
+			names = append(names, strings.TrimPrefix(f.name, a.root))
+		}
+	}
+	return names
+}
+
+// ReadFile returns the contents of a file in the project directory of the archive
+func (a *Archive) ReadFile(name string) ([]byte, error) {
+	f := a.file(a.root + name)
+	if f == nil {
+		return nil, ErrNotFound
+	}
+	return f.read()
+}
+
+// SetFile adds or replaces a file in the project directory of the archive
+func (a *Archive) SetFile(name string, data []byte) {
+	a.setFile(a.root+name, data)
+}
+
+// RemoveFile removes a file from the project directory of the archive
+func (a *Archive) RemoveFile(name string) {
+	for i, f := range a.files {
+		if f.name == a.root+name {
+			a.files = append(a.files[:i], a.files[i+1:]...)
+			return
+		}
+	}
+}
+
+func (a *Archive) file(name string) *file {
+	for _, f := range a.files {
+		if f.name == name {
+			return f
+		}
+	}
+	return nil
+}
+
+func (a *Archive) setFile(name string, data []byte) {
+	if f := a.file(name); f != nil {
+		f.zf = nil
+		f.data = data
+		f.modified = time.Now()
+		return
+	}
+	a.files = append(a.files, &file{name: name, data: data, modified: time.Now()})
+}
+
+// list returns the files in a directory of the project with the given suffix sorted by name
+func (a *Archive) list(dir, suffix string) []*file {
+	var files []*file
+	for _, f := range a.files {
+		name := strings.TrimPrefix(f.name, a.root)
+		if len(name) < len(f.name) && path.Dir(name)+"/" == dir && strings.HasSuffix(name, suffix) {
+			files = append(files, f)
+		}
+	}
+	sort.SliceStable(files, func(i, j int) bool { return files[i].name < files[j].name })
+	return files
+}
+
+// cursor walks the files of one kind in an archive
+type cursor struct {
+	files []*file
+	err   error
+}
+
+func (c *cursor) next() (*file, bool) {
+	if c.err != nil || len(c.files) == 0 {
+		return nil, false
+	}
+	f := c.files[0]
+	c.files = c.files[1:]
+	return f, true
+}
+
+// Err returns the first error encountered by the iterator
+func (c *cursor) Err() error {
+	return c.err
+}
+
+// Write writes the archive as a zip file suitable for a project import
+func (a *Archive) Write(w io.Writer) error {
+	zw := zip.NewWriter(w)
+	for _, f := range a.files {
+		if err := writeFile(zw, f); err != nil {
+			return fmt.Errorf("%s: %s", f.name, err)
+		}
+	}
+	return zw.Close()
+}
+
+// WriteFile writes the archive to a file at path
+func (a *Archive) WriteFile(path string) error {
+	buf := &bytes.Buffer{}
+	if err := a.Write(buf); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, buf.Bytes(), 0600)
+}
+
+func writeFile(zw *zip.Writer, f *file) error {
+	if f.zf != nil {
+		header := f.zf.FileHeader
+		w, err := zw.CreateHeader(&header)
+		if err != nil {
+			return err
+		}
+		if header.Mode().IsDir() || strings.HasSuffix(f.name, "/") {
+			return nil
+		}
+		rc, err := f.zf.Open()
+		if err != nil {
+			return err
+		}
+		defer rc.Close() // nolint: errcheck
+		_, err = io.Copy(w, rc)
+		return err
+	}
+	header := &zip.FileHeader{Name: f.name, Method: zip.Deflate}
+	header.Modified = f.modified
+	header.SetMode(os.FileMode(0644))
+	w, err := zw.CreateHeader(header)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(f.data)
+	return err
+}
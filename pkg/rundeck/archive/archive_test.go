@@ -0,0 +1,244 @@
+package archive
+
+import (
+	"archive/zip"
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	rundeck "github.com/lusis/go-rundeck/pkg/rundeck"
+	"github.com/stretchr/testify/require"
+)
+
+const testJobXML = `<joblist>
+  <job>
+    <name>deploy</name>
+    <group>app</group>
+    <uuid>job-uuid-1</uuid>
+    <description></description>
+    <executionEnabled>true</executionEnabled>
+    <loglevel>INFO</loglevel>
+    <sequence keepgoing='false' strategy='node-first'>
+      <command>
+        <exec>echo deploying</exec>
+      </command>
+    </sequence>
+  </job>
+</joblist>`
+
+const testExecutionXML = `<executions>
+  <execution id='12' jobId='job-uuid-1'>
+    <dateStarted>2019-03-04T19:26:28Z</dateStarted>
+    <dateCompleted>2019-03-04T19:26:29Z</dateCompleted>
+    <status>failed</status>
+    <outputfilepath />
+    <failedNodeList>web2</failedNodeList>
+    <succeededNodeList>web1,localhost</succeededNodeList>
+    <abortedby />
+    <cancelled>false</cancelled>
+    <argString>-env prod</argString>
+    <loglevel>INFO</loglevel>
+    <project>testproject</project>
+    <user>admin</user>
+    <timedOut>false</timedOut>
+    <retryAttempt>1</retryAttempt>
+    <executionType>user</executionType>
+  </execution>
+</executions>`
+
+const testLog = "^text/x-rundeck-log-v2.0^\n" +
+	"^2019-03-04T19:26:28Z|stepbegin||{node=web1|step=1|stepctx=1|user=admin}|^^\n" +
+	"^2019-03-04T19:26:28Z||NORMAL|{node=web1|step=1|stepctx=1|user=admin}|^deploying to web1^\n" +
+	"^2019-03-04T19:26:29Z||ERROR|{node=web2|step=1|stepctx=1|user=admin}|^line one\nline \\^two\\\\ \n^\n" +
+	"^END^"
+
+func testArchiveZip(t *testing.T, project string, files map[string]string) []byte {
+	buf := &bytes.Buffer{}
+	zw := zip.NewWriter(buf)
+	names := []string{"META-INF/MANIFEST.MF", "rundeck-" + project + "/"}
+	for name := range files {
+		names = append(names, name)
+	}
+	for _, name := range names {
+		w, err := zw.Create(name)
+		require.NoError(t, err)
+		_, err = w.Write([]byte(files[name]))
+		require.NoError(t, err)
+	}
+	require.NoError(t, zw.Close())
+	return buf.Bytes()
+}
+
+func testArchive(t *testing.T) *Archive {
+	data := testArchiveZip(t, "testproject", map[string]string{
+		"rundeck-testproject/jobs/job-job-uuid-1.xml":           testJobXML,
+		"rundeck-testproject/executions/execution-12.xml":       testExecutionXML,
+		"rundeck-testproject/executions/output-12.rdlog":        testLog,
+		"rundeck-testproject/executions/state-12.state.json":    "{}",
+		"rundeck-testproject/reports/report-12.xml":             "<report />",
+		"rundeck-testproject/acls/admins.aclpolicy":             "description: admins\n",
+		"rundeck-testproject/files/etc/project.properties":      "project.name=testproject\nproject.description=the test project\n",
+		"rundeck-testproject/files/readme.md":                   "# readme",
+		"rundeck-testproject/executions/execution-13.xml.extra": "ignored",
+	})
+	a, err := NewReader(bytes.NewReader(data), int64(len(data)))
+	require.NoError(t, err)
+	return a
+}
+
+func TestNewReader(t *testing.T) {
+	a := testArchive(t)
+	require.Equal(t, "testproject", a.Project)
+	require.Contains(t, a.Files(), ReadmePath)
+	readme, err := a.ReadFile(ReadmePath)
+	require.NoError(t, err)
+	require.Equal(t, "# readme", string(readme))
+	_, err = a.ReadFile(MOTDPath)
+	require.Equal(t, ErrNotFound, err)
+}
+
+func TestNewReaderNotArchive(t *testing.T) {
+	buf := &bytes.Buffer{}
+	zw := zip.NewWriter(buf)
+	_, err := zw.Create("something/else.txt")
+	require.NoError(t, err)
+	require.NoError(t, zw.Close())
+	a, err := NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.Nil(t, a)
+	require.Equal(t, ErrNotArchive, err)
+}
+
+func TestJobs(t *testing.T) {
+	a := testArchive(t)
+	it := a.Jobs()
+	var jobs []*rundeck.JobDefinition
+	for it.Next() {
+		jobs = append(jobs, it.Job())
+	}
+	require.NoError(t, it.Err())
+	require.Len(t, jobs, 1)
+	require.Equal(t, "deploy", jobs[0].Name)
+	require.Equal(t, "job-uuid-1", jobs[0].UUID)
+	require.Equal(t, "echo deploying", jobs[0].Sequence.Commands[0].Exec)
+}
+
+func TestJobsParseError(t *testing.T) {
+	a := testArchive(t)
+	a.SetFile("jobs/job-bad.xml", []byte("<joblist><job>"))
+	it := a.Jobs()
+	for it.Next() {
+	}
+	require.Error(t, it.Err())
+	require.Contains(t, it.Err().Error(), "job-bad.xml")
+}
+
+func TestExecutions(t *testing.T) {
+	a := testArchive(t)
+	it := a.Executions()
+	require.True(t, it.Next())
+	e := it.Execution()
+	require.False(t, it.Next())
+	require.NoError(t, it.Err())
+
+	require.Equal(t, "12", e.ID)
+	require.Equal(t, "job-uuid-1", e.JobID)
+	require.Equal(t, "failed", e.Status)
+	require.Equal(t, "-env prod", e.ArgString)
+	require.Equal(t, 1, e.RetryAttempt)
+	require.Equal(t, []string{"web1", "localhost"}, e.SucceededNodes)
+	require.Equal(t, []string{"web2"}, e.FailedNodes)
+	require.Equal(t, 2019, e.DateStarted.Year())
+	require.Equal(t, "2019-03-04T19:26:29Z", e.DateCompleted.Format("2006-01-02T15:04:05Z"))
+
+	log, err := e.Log()
+	require.NoError(t, err)
+	require.Len(t, log, 2)
+	require.Equal(t, "deploying to web1", log[0].Log)
+}
+
+func TestACLPolicies(t *testing.T) {
+	a := testArchive(t)
+	it := a.ACLPolicies()
+	require.True(t, it.Next())
+	require.Equal(t, "admins", it.ACLPolicy().Name)
+	require.Equal(t, "description: admins\n", string(it.ACLPolicy().Policy))
+	require.False(t, it.Next())
+	require.NoError(t, it.Err())
+}
+
+func TestProperties(t *testing.T) {
+	a := testArchive(t)
+	props, err := a.Properties()
+	require.NoError(t, err)
+	require.Equal(t, "the test project", props["project.description"])
+
+	a.RemoveFile(propertiesPath)
+	props, err = a.Properties()
+	require.NoError(t, err)
+	require.Empty(t, props)
+}
+
+func TestWriteModified(t *testing.T) {
+	a := testArchive(t)
+	job := &rundeck.JobDefinition{
+		Name:     "restart",
+		UUID:     "job-uuid-2",
+		Sequence: &rundeck.JobSequence{Commands: []*rundeck.JobStep{{Exec: "systemctl restart app"}}},
+	}
+	require.NoError(t, a.SetJob(job))
+	require.Error(t, a.SetJob(&rundeck.JobDefinition{Name: "no uuid"}))
+	a.RemoveJob("job-uuid-1")
+	a.RemoveExecution("12")
+	a.SetACLPolicy("ops", []byte("description: ops\n"))
+	a.RemoveACLPolicy("admins")
+	a.SetProperties(map[string]string{"project.name": "testproject", "project.label": "Test: Project"})
+
+	dir, err := ioutil.TempDir("", "archive")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir) // nolint: errcheck
+	out := filepath.Join(dir, "out.jar")
+	require.NoError(t, a.WriteFile(out))
+
+	b, err := Open(out)
+	require.NoError(t, err)
+	defer b.Close() // nolint: errcheck
+	require.Equal(t, "testproject", b.Project)
+
+	jobs := b.Jobs()
+	require.True(t, jobs.Next())
+	require.Equal(t, "restart", jobs.Job().Name)
+	require.False(t, jobs.Next())
+	require.NoError(t, jobs.Err())
+
+	require.False(t, b.Executions().Next())
+	_, err = b.ReadFile("reports/report-12.xml")
+	require.Equal(t, ErrNotFound, err)
+
+	acls := b.ACLPolicies()
+	require.True(t, acls.Next())
+	require.Equal(t, "ops", acls.ACLPolicy().Name)
+	require.False(t, acls.Next())
+
+	props, err := b.Properties()
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"project.name": "testproject", "project.label": "Test: Project"}, props)
+
+	readme, err := b.ReadFile(ReadmePath)
+	require.NoError(t, err)
+	require.Equal(t, "# readme", string(readme))
+}
+
+func TestNew(t *testing.T) {
+	a := New("fresh")
+	a.SetFile(ReadmePath, []byte("hello"))
+	buf := &bytes.Buffer{}
+	require.NoError(t, a.Write(buf))
+	b, err := NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+	require.Equal(t, "fresh", b.Project)
+	manifest, err := b.file(manifestPath).read()
+	require.NoError(t, err)
+	require.Contains(t, string(manifest), "Rundeck-Archive-Project-Name: fresh")
+}
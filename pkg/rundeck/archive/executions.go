@@ -0,0 +1,327 @@
+package archive
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+
+	rundeck "github.com/lusis/go-rundeck/pkg/rundeck"
+	responses "github.com/lusis/go-rundeck/pkg/rundeck/responses"
+)
+
+// Execution is an execution stored in an archive
+type Execution struct {
+	ID            string
+	JobID         string
+	Project       string
+	User          string
+	Status        string
+	ArgString     string
+	LogLevel      string
+	Filter        string
+	ExecutionType string
+	AbortedBy     string
+	Cancelled     bool
+	TimedOut      bool
+	RetryAttempt  int
+	DateStarted   time.Time
+	DateCompleted time.Time
+	// SucceededNodes and FailedNodes are the node names from the comma separated lists in the archive
+	SucceededNodes []string
+	FailedNodes    []string
+	archive        *Archive
+}
+
+// xmlExecution is how an execution is stored. Depending on the rundeck version the id
+// and job id are attributes or elements so both are read
+type xmlExecution struct {
+	IDAttr            string `xml:"id,attr"`
+	ID                string `xml:"id"`
+	JobIDAttr         string `xml:"jobId,attr"`
+	JobID             string `xml:"jobId"`
+	Project           string `xml:"project"`
+	User              string `xml:"user"`
+	Status            string `xml:"status"`
+	ArgString         string `xml:"argString"`
+	LogLevel          string `xml:"loglevel"`
+	Filter            string `xml:"filter"`
+	ExecutionType     string `xml:"executionType"`
+	AbortedBy         string `xml:"abortedby"`
+	Cancelled         bool   `xml:"cancelled"`
+	TimedOut          bool   `xml:"timedOut"`
+	RetryAttempt      int    `xml:"retryAttempt"`
+	DateStarted       string `xml:"dateStarted"`
+	DateCompleted     string `xml:"dateCompleted"`
+	SucceededNodeList string `xml:"succeededNodeList"`
+	FailedNodeList    string `xml:"failedNodeList"`
+}
+
+func splitNodeList(s string) []string {
+	var nodes []string
+	for _, n := range strings.Split(s, ",") {
+		if n = strings.TrimSpace(n); n != "" {
+			nodes = append(nodes, n)
+		}
+	}
+	return nodes
+}
+
+func parseArchiveTime(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(time.RFC3339, s)
+}
+
+// parseExecution reads the first execution in an execution file
+func parseExecution(data []byte) (*Execution, error) {
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			return nil, fmt.Errorf("no execution found")
+		}
+		if err != nil {
+			return nil, err
+		}
+		start, ok := tok.(xml.StartElement)
+		if !ok || start.Name.Local != "execution" {
+			continue
+		}
+		x := &xmlExecution{}
+		if err := dec.DecodeElement(x, &start); err != nil {
+			return nil, err
+		}
+		e := &Execution{
+			ID:             x.IDAttr,
+			JobID:          x.JobIDAttr,
+			Project:        x.Project,
+			User:           x.User,
+			Status:         x.Status,
+			ArgString:      x.ArgString,
+			LogLevel:       x.LogLevel,
+			Filter:         x.Filter,
+			ExecutionType:  x.ExecutionType,
+			AbortedBy:      x.AbortedBy,
+			Cancelled:      x.Cancelled,
+			TimedOut:       x.TimedOut,
+			RetryAttempt:   x.RetryAttempt,
+			SucceededNodes: splitNodeList(x.SucceededNodeList),
+			FailedNodes:    splitNodeList(x.FailedNodeList),
+		}
+		if e.ID == "" {
+			e.ID = x.ID
+		}
+		if e.JobID == "" {
+			e.JobID = x.JobID
+		}
+		if e.DateStarted, err = parseArchiveTime(x.DateStarted); err != nil {
+			return nil, err
+		}
+		if e.DateCompleted, err = parseArchiveTime(x.DateCompleted); err != nil {
+			return nil, err
+		}
+		return e, nil
+	}
+}
+
+// ExecutionIterator iterates over the executions in an archive
+type ExecutionIterator struct {
+	cursor
+	archive *Archive
+	current *Execution
+}
+
+// Executions returns an iterator over the executions in the archive
+func (a *Archive) Executions() *ExecutionIterator {
+	return &ExecutionIterator{cursor: cursor{files: a.list(executionsDir, ".xml")}, archive: a}
+}
+
+// Next advances the iterator to the next execution
+// It returns false when there are no more executions or an error occurred
+func (it *ExecutionIterator) Next() bool {
+	f, ok := it.next()
+	if !ok {
+		return false
+	}
+	if !strings.HasPrefix(path.Base(f.name), "execution-") {
+		return it.Next()
+	}
+	data, err := f.read()
+	if err != nil {
+		it.err = fmt.Errorf("%s: %s", f.name, err)
+		return false
+	}
+	e, err := parseExecution(data)
+	if err != nil {
+		it.err = fmt.Errorf("%s: %s", f.name, err)
+		return false
+	}
+	e.archive = it.archive
+	it.current = e
+	return true
+}
+
+// Execution returns the current execution
+func (it *ExecutionIterator) Execution() *Execution {
+	return it.current
+}
+
+func executionFiles(id string) []string {
+	return []string{
+		executionsDir + "execution-" + id + ".xml",
+		executionsDir + "output-" + id + ".rdlog",
+		executionsDir + "state-" + id + ".state.json",
+		"reports/report-" + id + ".xml",
+	}
+}
+
+// RemoveExecution removes an execution along with its log, state and report from the archive
+func (a *Archive) RemoveExecution(id string) {
+	for _, name := range executionFiles(id) {
+		a.RemoveFile(name)
+	}
+}
+
+// Log returns the log output of the execution
+// Executions exported without their output have no log and nil is returned
+func (e *Execution) Log() ([]*rundeck.ExecutionLogEntry, error) {
+	data, err := e.archive.ReadFile(executionFiles(e.ID)[1])
+	if err == ErrNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return ParseLog(data)
+}
+
+// ParseLog parses a rundeck log file (text/x-rundeck-log-v2.0) into log entries
+// Only log messages are returned. Step and node events are skipped
+func ParseLog(data []byte) ([]*rundeck.ExecutionLogEntry, error) {
+	var entries []*rundeck.ExecutionLogEntry
+	r := bufio.NewReader(bytes.NewReader(data))
+	header, err := r.ReadString('\n')
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+	if !strings.HasPrefix(header, "^text/x-rundeck-log") {
+		return nil, fmt.Errorf("unsupported log format %q", strings.TrimSpace(header))
+	}
+	for {
+		record, err := readLogRecord(r)
+		if err == io.EOF {
+			return entries, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if record == "END" {
+			return entries, nil
+		}
+		entry, err := parseLogRecord(record)
+		if err != nil {
+			return nil, err
+		}
+		if entry != nil {
+			entries = append(entries, entry)
+		}
+	}
+}
+
+// readLogRecord reads the text between a record's opening ^ and its unescaped closing ^
+// A record's message can span several lines
+func readLogRecord(r *bufio.Reader) (string, error) {
+	var b strings.Builder
+	started, inMessage := false, false
+	var last byte
+	for {
+		c, err := r.ReadByte()
+		if err != nil {
+			if err == io.EOF && started {
+				return "", io.ErrUnexpectedEOF
+			}
+			return "", err
+		}
+		switch {
+		case !started && c == '^':
+			started = true
+		case !started:
+			// text between records is ignored
+		case c == '\\':
+			next, err := r.ReadByte()
+			if err != nil {
+				return "", io.ErrUnexpectedEOF
+			}
+			b.WriteByte('\\')
+			b.WriteByte(next)
+		case c == '^' && !inMessage && last == '|':
+			// the ^ opening a message
+			inMessage = true
+			b.WriteByte(c)
+		case c == '^':
+			return b.String(), nil
+		default:
+			b.WriteByte(c)
+		}
+		last = c
+	}
+}
+
+// parseLogRecord parses time|event|level|{meta}|^message
+func parseLogRecord(record string) (*rundeck.ExecutionLogEntry, error) {
+	msgStart := strings.Index(record, "|^")
+	if msgStart < 0 {
+		return nil, fmt.Errorf("invalid log record %q", record)
+	}
+	fields := strings.SplitN(record[:msgStart], "|", 4)
+	if len(fields) != 4 {
+		return nil, fmt.Errorf("invalid log record %q", record)
+	}
+	event := fields[1]
+	if event != "" && event != "log" {
+		return nil, nil
+	}
+	ts, err := time.Parse(time.RFC3339, fields[0])
+	if err != nil {
+		return nil, err
+	}
+	meta := map[string]string{}
+	for _, kv := range strings.Split(strings.Trim(fields[3], "{}"), "|") {
+		if parts := strings.SplitN(kv, "=", 2); len(parts) == 2 {
+			meta[parts[0]] = unescapeLog(parts[1])
+		}
+	}
+	stepctx := meta["stepctx"]
+	if stepctx == "" {
+		stepctx = meta["step"]
+	}
+	entry := &rundeck.ExecutionLogEntry{}
+	entry.Time = ts.Format("15:04:05")
+	entry.AbsoluteTime = &responses.JSONTime{Time: ts}
+	entry.Level = fields[2]
+	entry.Node = meta["node"]
+	entry.User = meta["user"]
+	entry.StepCTX = stepctx
+	entry.Log = strings.TrimSuffix(unescapeLog(record[msgStart+2:]), "\n")
+	return entry, nil
+}
+
+func unescapeLog(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) {
+			i++
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
@@ -0,0 +1,45 @@
+package archive
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLog(t *testing.T) {
+	entries, err := ParseLog([]byte(testLog))
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	require.Equal(t, "web1", entries[0].Node)
+	require.Equal(t, "NORMAL", entries[0].Level)
+	require.Equal(t, "admin", entries[0].User)
+	require.Equal(t, "1", entries[0].StepCTX)
+	require.Equal(t, "19:26:28", entries[0].Time)
+	require.Equal(t, 2019, entries[0].AbsoluteTime.Year())
+	require.Equal(t, "ERROR", entries[1].Level)
+	require.Equal(t, "line one\nline ^two\\ ", entries[1].Log)
+}
+
+func TestParseLogErrors(t *testing.T) {
+	_, err := ParseLog([]byte("plain text output\n"))
+	require.Error(t, err)
+	_, err = ParseLog([]byte("^text/x-rundeck-log-v2.0^\n^2019-03-04T19:26:28Z||NORMAL|{node=web1}|^unterminated"))
+	require.Error(t, err)
+	_, err = ParseLog([]byte("^text/x-rundeck-log-v2.0^\n^not a record^"))
+	require.Error(t, err)
+	_, err = ParseLog([]byte("^text/x-rundeck-log-v2.0^\n^yesterday||NORMAL|{node=web1}|^hi^"))
+	require.Error(t, err)
+}
+
+func TestParseExecutionElements(t *testing.T) {
+	e, err := parseExecution([]byte(`<execution><id>7</id><jobId>abc</jobId><status>succeeded</status></execution>`))
+	require.NoError(t, err)
+	require.Equal(t, "7", e.ID)
+	require.Equal(t, "abc", e.JobID)
+	require.True(t, e.DateStarted.IsZero())
+
+	_, err = parseExecution([]byte(`<executions />`))
+	require.Error(t, err)
+	_, err = parseExecution([]byte(`<execution id="1"><dateStarted>yesterday</dateStarted></execution>`))
+	require.Error(t, err)
+}
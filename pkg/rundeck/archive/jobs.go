@@ -0,0 +1,77 @@
+package archive
+
+import (
+	"errors"
+	"fmt"
+
+	rundeck "github.com/lusis/go-rundeck/pkg/rundeck"
+)
+
+// JobIterator iterates over the job definitions in an archive
+type JobIterator struct {
+	cursor
+	current []*rundeck.JobDefinition
+}
+
+// Jobs returns an iterator over the job definitions in the archive
+func (a *Archive) Jobs() *JobIterator {
+	return &JobIterator{cursor: cursor{files: a.list(jobsDir, ".xml")}}
+}
+
+// Next advances the iterator to the next job definition
+// It returns false when there are no more jobs or an error occurred
+func (it *JobIterator) Next() bool {
+	if len(it.current) > 1 {
+		it.current = it.current[1:]
+		return true
+	}
+	f, ok := it.next()
+	if !ok {
+		return false
+	}
+	data, err := f.read()
+	if err != nil {
+		it.err = fmt.Errorf("%s: %s", f.name, err)
+		return false
+	}
+	jobs, err := rundeck.ParseJobDefinitions(data, rundeck.JobFormatXML)
+	if err != nil {
+		it.err = fmt.Errorf("%s: %s", f.name, err)
+		return false
+	}
+	if len(jobs) == 0 {
+		return it.Next()
+	}
+	it.current = jobs
+	return true
+}
+
+// Job returns the current job definition
+func (it *JobIterator) Job() *rundeck.JobDefinition {
+	if len(it.current) == 0 {
+		return nil
+	}
+	return it.current[0]
+}
+
+func jobFile(uuid string) string {
+	return jobsDir + "job-" + uuid + ".xml"
+}
+
+// SetJob adds a job definition to the archive or replaces the one with the same uuid
+func (a *Archive) SetJob(job *rundeck.JobDefinition) error {
+	if job.UUID == "" {
+		return errors.New("jobs need a uuid to be stored in an archive")
+	}
+	data, err := rundeck.MarshalJobDefinitions([]*rundeck.JobDefinition{job}, rundeck.JobFormatXML)
+	if err != nil {
+		return err
+	}
+	a.SetFile(jobFile(job.UUID), data)
+	return nil
+}
+
+// RemoveJob removes the job with uuid from the archive
+func (a *Archive) RemoveJob(uuid string) {
+	a.RemoveFile(jobFile(uuid))
+}
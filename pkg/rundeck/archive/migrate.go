@@ -0,0 +1,319 @@
+package archive
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	rundeck "github.com/lusis/go-rundeck/pkg/rundeck"
+	responses "github.com/lusis/go-rundeck/pkg/rundeck/responses"
+)
+
+// DefaultExportPollInterval is how often the status of an export is checked while migrating
+const DefaultExportPollInterval = 2 * time.Second
+
+// ConfigRewrite maps project configuration from one server to another
+//
+// Keys renames configuration keys. A key ending in . renames every key with that prefix,
+// which is how a node source like resources.source.1. is moved or dropped.
+// An empty new key removes the key.
+// Values replaces text in every value after the keys are renamed, i.e. old hostnames or paths.
+type ConfigRewrite struct {
+	Keys   map[string]string `yaml:"keys,omitempty"`
+	Values map[string]string `yaml:"values,omitempty"`
+}
+
+// Apply returns a rewritten copy of props
+func (r *ConfigRewrite) Apply(props map[string]string) map[string]string {
+	out := map[string]string{}
+	if r == nil {
+		for k, v := range props {
+			out[k] = v
+		}
+		return out
+	}
+	for k, v := range props {
+		key, keep := r.key(k)
+		if !keep {
+			continue
+		}
+		out[key] = r.value(v)
+	}
+	return out
+}
+
+// key returns the new name for a key. exact matches win over the longest matching prefix
+func (r *ConfigRewrite) key(k string) (string, bool) {
+	if to, ok := r.Keys[k]; ok {
+		return to, to != ""
+	}
+	best := ""
+	for from := range r.Keys {
+		if strings.HasSuffix(from, ".") && strings.HasPrefix(k, from) && len(from) > len(best) {
+			best = from
+		}
+	}
+	if best == "" {
+		return k, true
+	}
+	to := r.Keys[best]
+	if to == "" {
+		return "", false
+	}
+	return to + strings.TrimPrefix(k, best), true
+}
+
+// value replaces text in a value. longer matches are replaced first so overlapping entries are predictable
+func (r *ConfigRewrite) value(v string) string {
+	froms := make([]string, 0, len(r.Values))
+	for from := range r.Values {
+		if from != "" {
+			froms = append(froms, from)
+		}
+	}
+	sort.Slice(froms, func(i, j int) bool {
+		if len(froms[i]) != len(froms[j]) {
+			return len(froms[i]) > len(froms[j])
+		}
+		return froms[i] < froms[j]
+	})
+	pairs := make([]string, 0, len(froms)*2)
+	for _, from := range froms {
+		pairs = append(pairs, from, r.Values[from])
+	}
+	return strings.NewReplacer(pairs...).Replace(v)
+}
+
+// MigrateOption is a functional option for migrating a project
+type MigrateOption func(m *migration) error
+
+type migration struct {
+	target       string
+	rewrite      *ConfigRewrite
+	jobUUIDs     string
+	executions   bool
+	pollInterval time.Duration
+}
+
+// MigrateToProject imports into a project with a different name on the target server
+func MigrateToProject(name string) MigrateOption {
+	return func(m *migration) error {
+		if name == "" {
+			return errors.New("project name cannot be empty")
+		}
+		m.target = name
+		return nil
+	}
+}
+
+// MigrateRewrite rewrites the project configuration on the way to the target server
+func MigrateRewrite(r *ConfigRewrite) MigrateOption {
+	return func(m *migration) error {
+		m.rewrite = r
+		return nil
+	}
+}
+
+// MigrateJobUUIDs sets whether job uuids are preserved or removed so the target generates new ones
+func MigrateJobUUIDs(option string) MigrateOption {
+	return func(m *migration) error {
+		if option != "preserve" && option != "remove" {
+			return fmt.Errorf("job uuid option must be preserve or remove but was %q", option)
+		}
+		m.jobUUIDs = option
+		return nil
+	}
+}
+
+// MigrateExecutions sets whether executions and their logs are migrated. They are by default
+func MigrateExecutions(b bool) MigrateOption {
+	return func(m *migration) error {
+		m.executions = b
+		return nil
+	}
+}
+
+// MigratePollInterval sets how often the status of the export is checked
+func MigratePollInterval(d time.Duration) MigrateOption {
+	return func(m *migration) error {
+		if d <= 0 {
+			return errors.New("poll interval must be positive")
+		}
+		m.pollInterval = d
+		return nil
+	}
+}
+
+// MigrateFailure is something the target server couldn't import
+type MigrateFailure struct {
+	// Kind is job, execution or acl
+	Kind    string
+	Message string
+}
+
+// MigrateResult is the outcome of a project migration
+type MigrateResult struct {
+	Project string
+	Target  string
+	// Created is true when the target project didn't exist and was created
+	Created bool
+	// RewrittenKeys are the configuration keys that were renamed, removed or had their value changed
+	RewrittenKeys []string
+	Failures      []*MigrateFailure
+	Response      *responses.ProjectImportArchiveResponse
+}
+
+// Migrate copies a project from one rundeck server to another
+//
+// The project is exported with the async export api and downloaded to a temporary file.
+// Its configuration is rewritten and the archive is streamed to the target's project import.
+// The target project is created with the rewritten configuration if it doesn't exist.
+// Anything the target fails to import is listed in the result's Failures and an error is returned.
+func Migrate(ctx context.Context, from, to *rundeck.Client, project string, opts ...MigrateOption) (*MigrateResult, error) {
+	m := &migration{target: project, jobUUIDs: "preserve", executions: true, pollInterval: DefaultExportPollInterval}
+	for _, opt := range opts {
+		if err := opt(m); err != nil {
+			return nil, err
+		}
+	}
+	result := &MigrateResult{Project: project, Target: m.target}
+
+	tmp, err := ioutil.TempFile("", "rundeck-migrate-*.jar")
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+	}()
+	if err := m.export(ctx, from, project, tmp); err != nil {
+		return nil, err
+	}
+	info, err := tmp.Stat()
+	if err != nil {
+		return nil, err
+	}
+	a, err := NewReader(tmp, info.Size())
+	if err != nil {
+		return nil, err
+	}
+	props, err := a.Properties()
+	if err != nil {
+		return nil, err
+	}
+	rewritten := m.rewrite.Apply(props)
+	if m.target != project {
+		rewritten["project.name"] = m.target
+	}
+	result.RewrittenKeys = changedKeys(props, rewritten)
+	if len(props) > 0 {
+		a.SetProperties(rewritten)
+	}
+
+	if _, err := to.GetProjectInfoContext(ctx, m.target); err != nil {
+		if !errors.Is(err, rundeck.ErrMissingResource) {
+			return nil, err
+		}
+		if _, err := to.CreateProjectContext(ctx, m.target, rewritten); err != nil {
+			return nil, err
+		}
+		result.Created = true
+	}
+
+	pr, pw := io.Pipe()
+	go func() {
+		_ = pw.CloseWithError(a.Write(pw))
+	}()
+	res, err := to.ProjectArchiveImportContext(ctx, m.target, pr,
+		rundeck.ProjectImportJobUUIDs(m.jobUUIDs),
+		rundeck.ProjectImportConfigs(len(props) > 0),
+		rundeck.ProjectImportAcls(true),
+		rundeck.ProjectImportExecutions(m.executions))
+	_ = pr.Close()
+	if err != nil {
+		return result, err
+	}
+	result.Response = res
+	result.Failures = importFailures(res)
+	if len(result.Failures) > 0 || res.ImportStatus != "successful" {
+		return result, fmt.Errorf("import of project %s finished with status %s and %d failures", m.target, res.ImportStatus, len(result.Failures))
+	}
+	return result, nil
+}
+
+// export runs an async export of project and downloads it to w once it is ready
+func (m *migration) export(ctx context.Context, from *rundeck.Client, project string, w io.Writer) error {
+	token, err := from.GetProjectArchiveExportAsyncContext(ctx, project,
+		rundeck.ProjectExportAll(false),
+		rundeck.ProjectExportJobs(true),
+		rundeck.ProjectExportExecutions(m.executions),
+		rundeck.ProjectExportConfigs(true),
+		rundeck.ProjectExportReadmes(true),
+		rundeck.ProjectExportAcls(true))
+	if err != nil {
+		return err
+	}
+	for {
+		status, err := from.GetProjectArchiveExportAsyncStatusContext(ctx, project, token)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return err
+		}
+		if status.Ready {
+			break
+		}
+		timer := time.NewTimer(m.pollInterval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+	return from.GetProjectArchiveExportAsyncDownloadContext(ctx, project, token, w)
+}
+
+// changedKeys lists the keys removed, added or changed between two configurations
+func changedKeys(before, after map[string]string) []string {
+	var keys []string
+	for k, v := range before {
+		if nv, ok := after[k]; !ok || nv != v {
+			keys = append(keys, k)
+		}
+	}
+	for k := range after {
+		if _, ok := before[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func importFailures(res *responses.ProjectImportArchiveResponse) []*MigrateFailure {
+	var failures []*MigrateFailure
+	for _, list := range []struct {
+		kind     string
+		messages *[]string
+	}{
+		{"job", res.Errors},
+		{"execution", res.ExecutionErrors},
+		{"acl", res.ACLErrors},
+	} {
+		if list.messages == nil {
+			continue
+		}
+		for _, msg := range *list.messages {
+			failures = append(failures, &MigrateFailure{Kind: list.kind, Message: msg})
+		}
+	}
+	return failures
+}
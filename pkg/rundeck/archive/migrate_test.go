@@ -0,0 +1,186 @@
+package archive
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	rundeck "github.com/lusis/go-rundeck/pkg/rundeck"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigRewriteApply(t *testing.T) {
+	r := &ConfigRewrite{
+		Keys: map[string]string{
+			"project.ssh-keypath":  "project.ssh-key-storage-path",
+			"resources.source.1.":  "resources.source.2.",
+			"resources.source.3.":  "",
+			"project.obsolete":     "",
+			"resources.source.1.x": "resources.source.9.x",
+		},
+		Values: map[string]string{
+			"old.example.com":         "new.example.com",
+			"/var/rundeck":            "/data/rundeck",
+			"https://old.example.com": "https://new.example.com",
+		},
+	}
+	out := r.Apply(map[string]string{
+		"project.name":                     "test",
+		"project.ssh-keypath":              "/var/rundeck/.ssh/id_rsa",
+		"project.obsolete":                 "true",
+		"resources.source.1.type":          "url",
+		"resources.source.1.config.url":    "https://old.example.com/nodes",
+		"resources.source.1.x":             "exact",
+		"resources.source.3.type":          "file",
+		"resources.source.3.config.format": "resourcexml",
+	})
+	require.Equal(t, map[string]string{
+		"project.name":                  "test",
+		"project.ssh-key-storage-path":  "/data/rundeck/.ssh/id_rsa",
+		"resources.source.2.type":       "url",
+		"resources.source.2.config.url": "https://new.example.com/nodes",
+		"resources.source.9.x":          "exact",
+	}, out)
+
+	var none *ConfigRewrite
+	require.Equal(t, map[string]string{"a": "b"}, none.Apply(map[string]string{"a": "b"}))
+}
+
+func TestMigrateOptions(t *testing.T) {
+	m := &migration{}
+	require.Error(t, MigrateToProject("")(m))
+	require.Error(t, MigrateJobUUIDs("keep")(m))
+	require.NoError(t, MigrateJobUUIDs("remove")(m))
+	require.Error(t, MigratePollInterval(0)(m))
+}
+
+func testMigrateClient(t *testing.T, handler http.HandlerFunc) (*rundeck.Client, *httptest.Server) {
+	server := httptest.NewServer(handler)
+	client, err := rundeck.NewTokenAuthClient("token", server.URL)
+	require.NoError(t, err)
+	return client, server
+}
+
+func TestMigrate(t *testing.T) {
+	source := testArchiveZip(t, "old", map[string]string{
+		"rundeck-old/jobs/job-job-uuid-1.xml":      testJobXML,
+		"rundeck-old/files/etc/project.properties": "project.name=old\nresources.source.1.config.url=http://old.example.com/nodes\n",
+	})
+	var polls int32
+	from, fromServer := testMigrateClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/project/old/export/async"):
+			require.Equal(t, "true", r.URL.Query().Get("exportConfigs"))
+			require.Equal(t, "false", r.URL.Query().Get("exportExecutions"))
+			_, _ = w.Write([]byte(`{"token":"tok","ready":false,"percentage":0}`))
+		case strings.HasSuffix(r.URL.Path, "/project/old/export/status/tok"):
+			ready := atomic.AddInt32(&polls, 1) > 1
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"token": "tok", "ready": ready, "percentage": 100})
+		case strings.HasSuffix(r.URL.Path, "/project/old/export/download/tok"):
+			w.Header().Set("Content-Type", "application/zip")
+			_, _ = w.Write(source)
+		default:
+			t.Errorf("unexpected source request %s", r.URL.Path)
+		}
+	})
+	defer fromServer.Close()
+
+	var created map[string]interface{}
+	var imported *Archive
+	to, toServer := testMigrateClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/project/new"):
+			w.WriteHeader(404)
+			_, _ = w.Write([]byte(`{"error":true,"apiversion":24,"errorCode":"api.error.item.doesnotexist","message":"not found"}`))
+		case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/projects"):
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&created))
+			w.WriteHeader(201)
+			_, _ = w.Write([]byte(`{"url":"http://localhost/api/24/project/new","name":"new","description":"","config":{}}`))
+		case r.Method == http.MethodPut && strings.HasSuffix(r.URL.Path, "/project/new/import"):
+			require.Equal(t, "remove", r.URL.Query().Get("jobUuidOption"))
+			require.Equal(t, "true", r.URL.Query().Get("importConfig"))
+			data, err := ioutil.ReadAll(r.Body)
+			require.NoError(t, err)
+			imported, err = NewReader(bytes.NewReader(data), int64(len(data)))
+			require.NoError(t, err)
+			_, _ = w.Write([]byte(`{"import_status":"successful"}`))
+		default:
+			t.Errorf("unexpected target request %s %s", r.Method, r.URL.Path)
+		}
+	})
+	defer toServer.Close()
+
+	res, err := Migrate(context.Background(), from, to, "old",
+		MigrateToProject("new"),
+		MigrateJobUUIDs("remove"),
+		MigrateExecutions(false),
+		MigratePollInterval(time.Millisecond),
+		MigrateRewrite(&ConfigRewrite{Values: map[string]string{"old.example.com": "new.example.com"}}))
+	require.NoError(t, err)
+	require.True(t, res.Created)
+	require.Equal(t, "new", res.Target)
+	require.Equal(t, []string{"project.name", "resources.source.1.config.url"}, res.RewrittenKeys)
+	require.Empty(t, res.Failures)
+	require.Equal(t, "new", created["name"])
+
+	props, err := imported.Properties()
+	require.NoError(t, err)
+	require.Equal(t, "http://new.example.com/nodes", props["resources.source.1.config.url"])
+	require.Equal(t, "new", props["project.name"])
+	jobs := imported.Jobs()
+	require.True(t, jobs.Next())
+	require.Equal(t, "deploy", jobs.Job().Name)
+}
+
+func TestMigrateImportFailures(t *testing.T) {
+	source := testArchiveZip(t, "testproject", map[string]string{"rundeck-testproject/jobs/job-job-uuid-1.xml": testJobXML})
+	from, fromServer := testMigrateClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.Contains(r.URL.Path, "/export/download/"):
+			_, _ = w.Write(source)
+		default:
+			_, _ = w.Write([]byte(`{"token":"tok","ready":true,"percentage":100}`))
+		}
+	})
+	defer fromServer.Close()
+	to, toServer := testMigrateClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == http.MethodGet {
+			_, _ = w.Write([]byte(`{"url":"http://localhost/api/24/project/testproject","name":"testproject","description":"","config":{}}`))
+			return
+		}
+		_, _ = ioutil.ReadAll(r.Body)
+		_, _ = w.Write([]byte(`{"import_status":"failed","errors":["Job ABC could not be validated"],"acl_errors":["a.aclpolicy is invalid"]}`))
+	})
+	defer toServer.Close()
+
+	res, err := Migrate(context.Background(), from, to, "testproject")
+	require.Error(t, err)
+	require.False(t, res.Created)
+	require.Len(t, res.Failures, 2)
+	require.Equal(t, "job", res.Failures[0].Kind)
+	require.Equal(t, "acl", res.Failures[1].Kind)
+	require.Equal(t, "a.aclpolicy is invalid", res.Failures[1].Message)
+}
+
+func TestMigrateCancelled(t *testing.T) {
+	from, fromServer := testMigrateClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"token":"tok","ready":false,"percentage":10}`))
+	})
+	defer fromServer.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := Migrate(ctx, from, from, "old", MigratePollInterval(10*time.Millisecond))
+	require.Equal(t, context.DeadlineExceeded, err)
+}
@@ -0,0 +1,51 @@
+package archive
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseProperties(t *testing.T) {
+	data := "# comment\r\n" +
+		"! another comment\n" +
+		"project.name=test\n" +
+		"project.description : a long \\\n" +
+		"    description\n" +
+		"project.label the label\n" +
+		"key\\ with\\:colon=value\\twith tab\n" +
+		"unicode=caf\\u00e9 \\ud83d\\ude80\n" +
+		"empty=\n" +
+		"novalue\n"
+	props, err := ParseProperties([]byte(data))
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{
+		"project.name":        "test",
+		"project.description": "a long description",
+		"project.label":       "the label",
+		"key with:colon":      "value\twith tab",
+		"unicode":             "café 🚀",
+		"empty":               "",
+		"novalue":             "",
+	}, props)
+
+	_, err = ParseProperties([]byte("bad=\\uzzzz\n"))
+	require.Error(t, err)
+}
+
+func TestFormatProperties(t *testing.T) {
+	props := map[string]string{
+		"b.key":         "  leading space",
+		"a.key":         "multi\nline=ok",
+		"key with:char": "café 🚀",
+		"hash":          "#not a comment",
+	}
+	data := FormatProperties(props)
+	require.Equal(t, "a.key=multi\\nline=ok\n"+
+		"b.key=\\  leading space\n"+
+		"hash=\\#not a comment\n"+
+		"key\\ with\\:char=caf\\u00e9 \\ud83d\\ude80\n", string(data))
+	parsed, err := ParseProperties(data)
+	require.NoError(t, err)
+	require.Equal(t, props, parsed)
+}
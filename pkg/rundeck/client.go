@@ -7,7 +7,9 @@ import (
 	"net/http/cookiejar"
 	"os"
 	"strconv"
+	"time"
 
+	httpclient "github.com/lusis/go-rundeck/pkg/httpclient"
 	"golang.org/x/net/publicsuffix"
 )
 
@@ -20,40 +22,68 @@ type ClientConfig struct {
 	Password   string
 	AuthMethod string
 	APIVersion string
+	// HTTPClient is used for every request made by the client
+	// when nil one is built from the connection settings below
 	HTTPClient *http.Client
+	// RetryPolicy controls retrying failed requests. nil disables retries
+	RetryPolicy *httpclient.RetryPolicy
+	// Timeout limits the total time of a request including reading the response. 0 means no limit
+	Timeout time.Duration
+	// MaxIdleConns limits the idle connections kept open across all hosts. 0 means no limit
+	MaxIdleConns int
+	// MaxIdleConnsPerHost limits the idle connections kept open to the rundeck server
+	// defaults to DefaultMaxIdleConnsPerHost
+	MaxIdleConnsPerHost int
+	// IdleConnTimeout is how long an idle connection is kept open
+	// defaults to DefaultIdleConnTimeout
+	IdleConnTimeout time.Duration
+	// ResponseHeaderTimeout limits the time spent waiting for the server to start responding. 0 means no limit
+	ResponseHeaderTimeout time.Duration
 }
 
 // Client represents a rundeck client
 type Client struct {
 	HTTPClient *http.Client
 	Config     *ClientConfig
+	session    basicAuthSession
 }
 
 func defaultClientConfig() (*ClientConfig, error) {
-	c, err := defaultHTTPClient(true)
-	if err != nil {
-		return nil, err
-	}
 	return &ClientConfig{
 		VerifySSL:  true,
 		APIVersion: MaxRundeckVersion,
-		HTTPClient: c,
 	}, nil
 }
 
-func defaultHTTPClient(verifySSL bool) (*http.Client, error) {
-	tlsClientConfig := tls.Config{InsecureSkipVerify: !verifySSL}
-	transport := &http.Transport{TLSClientConfig: &tlsClientConfig}
-	client := &http.Client{Transport: transport}
-	options := cookiejar.Options{
-		PublicSuffixList: publicsuffix.List,
+// defaultHTTPClient builds the long-lived http client shared by all of a client's requests
+func defaultHTTPClient(config *ClientConfig) (*http.Client, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: !config.VerifySSL}
+	transport.MaxIdleConns = config.MaxIdleConns
+	transport.MaxIdleConnsPerHost = DefaultMaxIdleConnsPerHost
+	if config.MaxIdleConnsPerHost > 0 {
+		transport.MaxIdleConnsPerHost = config.MaxIdleConnsPerHost
 	}
-	jar, err := cookiejar.New(&options)
+	transport.IdleConnTimeout = DefaultIdleConnTimeout
+	if config.IdleConnTimeout > 0 {
+		transport.IdleConnTimeout = config.IdleConnTimeout
+	}
+	transport.ResponseHeaderTimeout = config.ResponseHeaderTimeout
+	jar, err := newCookieJar()
 	if err != nil {
 		return nil, err
 	}
-	client.Jar = jar
-	return client, nil
+	return &http.Client{
+		Transport: transport,
+		Timeout:   config.Timeout,
+		Jar:       jar,
+	}, nil
+}
+
+func newCookieJar() (*cookiejar.Jar, error) {
+	return cookiejar.New(&cookiejar.Options{
+		PublicSuffixList: publicsuffix.List,
+	})
 }
 
 func (c *Client) setInsecure() {
@@ -65,13 +95,27 @@ func (c *Client) setInsecure() {
 // NewClient creates a new client from the provided `ClientConfig`
 func NewClient(config *ClientConfig) (*Client, error) {
 	if config.HTTPClient == nil {
-		c, err := defaultHTTPClient(config.VerifySSL)
+		c, err := defaultHTTPClient(config)
 		if err != nil {
 			return nil, err
 		}
 
 		config.HTTPClient = c
 	}
+	if config.AuthMethod == basicAuthType {
+		// basic auth needs somewhere to keep the session cookie and a way to notice
+		// being sent to the login page. custom clients are only changed here, never per request
+		if config.HTTPClient.Jar == nil {
+			jar, err := newCookieJar()
+			if err != nil {
+				return nil, err
+			}
+			config.HTTPClient.Jar = jar
+		}
+		if config.HTTPClient.CheckRedirect == nil {
+			config.HTTPClient.CheckRedirect = redirPolicy
+		}
+	}
 	rdClient := Client{
 		HTTPClient: config.HTTPClient,
 		Config:     config,
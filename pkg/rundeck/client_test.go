@@ -7,6 +7,7 @@ import (
 	"net/url"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 )
@@ -37,6 +38,41 @@ func TestNewClientSkipVerify(t *testing.T) {
 	require.True(t, transport.TLSClientConfig.InsecureSkipVerify)
 }
 
+func TestNewClientConnectionSettings(t *testing.T) {
+	config := ClientConfig{
+		BaseURL:         "http://localhost:4440/",
+		Token:           "XXXXXXXXXXXXX",
+		AuthMethod:      "token",
+		Timeout:         30 * time.Second,
+		MaxIdleConns:    50,
+		IdleConnTimeout: time.Minute,
+		VerifySSL:       true,
+	}
+	client, err := NewClient(&config)
+	require.NoError(t, err)
+	require.Equal(t, 30*time.Second, client.HTTPClient.Timeout)
+	transport := client.HTTPClient.Transport.(*http.Transport)
+	require.Equal(t, 50, transport.MaxIdleConns)
+	require.Equal(t, DefaultMaxIdleConnsPerHost, transport.MaxIdleConnsPerHost)
+	require.Equal(t, time.Minute, transport.IdleConnTimeout)
+	require.False(t, transport.TLSClientConfig.InsecureSkipVerify)
+}
+
+func TestNewBasicAuthClientCustomHTTPClient(t *testing.T) {
+	httpClient := &http.Client{}
+	client, err := NewClient(&ClientConfig{
+		BaseURL:    "http://localhost:4440/",
+		Username:   "admin",
+		Password:   "admin",
+		AuthMethod: "basic",
+		HTTPClient: httpClient,
+	})
+	require.NoError(t, err)
+	require.Equal(t, httpClient, client.HTTPClient)
+	require.NotNil(t, httpClient.Jar)
+	require.NotNil(t, httpClient.CheckRedirect)
+}
+
 func TestNewClientCustomHTTPClient(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
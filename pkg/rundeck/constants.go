@@ -1,6 +1,9 @@
 package rundeck
 
-import "errors"
+import (
+	"errors"
+	"time"
+)
 
 // RDTime is the rundeck time format
 const RDTime = "2006-01-02T15:04:05Z"
@@ -13,6 +16,16 @@ const defaultNodeFilter = "name: .*"
 // TODO: make this a min/max option and validate
 const MaxRundeckVersion = "31"
 
+// DefaultMaxIdleConnsPerHost is the number of idle connections to the rundeck server kept for reuse
+const DefaultMaxIdleConnsPerHost = 10
+
+// DefaultIdleConnTimeout is how long idle connections to the rundeck server are kept
+const DefaultIdleConnTimeout = 90 * time.Second
+
+// maxReplayBody is the largest request body held in memory so it can be sent again after logging in
+// bodies that can be seeked, like files, are rewound instead
+const maxReplayBody = 1 << 20
+
 // minimum version of rundeck api version that supports json
 const minJSONSupportedAPIVersion = 14
 
@@ -48,6 +61,15 @@ var (
 	// ErrResourceConflict is the error type for 409 responses
 	ErrResourceConflict = errors.New("resource already exists on the rundeck server")
 
+	// ErrExecutionFailed is matched by an ExecutionError for a failed execution
+	ErrExecutionFailed = errors.New("execution failed")
+
+	// ErrExecutionAborted is matched by an ExecutionError for an aborted execution
+	ErrExecutionAborted = errors.New("execution was aborted")
+
+	// ErrExecutionTimedOut is matched by an ExecutionError for an execution that hit the job's timeout
+	ErrExecutionTimedOut = errors.New("execution timed out")
+
 	errDecoding   = errors.New("Could not parse response from the Rundeck server")
 	errEncoding   = errors.New("could not encode payload for rundeck server")
 	errOption     = errors.New("Passed option returned an error")
@@ -1,5 +1,7 @@
 package rundeck
 
+import "fmt"
+
 // UnmarshalError is a custom error type for decoding errors
 type UnmarshalError struct {
 	msg string
@@ -33,6 +35,8 @@ func (e *OptionError) Error() string {
 // PolicyValidationError is a custom error type for policy validation errors
 type PolicyValidationError struct {
 	msg string
+	// Errors are the problems found when a policy was validated locally
+	Errors []error
 }
 
 // Error returns the error message
@@ -40,6 +44,18 @@ func (e *PolicyValidationError) Error() string {
 	return e.msg
 }
 
+// JobValidationError is a custom error type for job definition validation errors
+type JobValidationError struct {
+	msg string
+	// Errors are the individual problems found
+	Errors []error
+}
+
+// Error returns the error message
+func (e *JobValidationError) Error() string {
+	return e.msg
+}
+
 // AuthError is a custom error type for decoding errors
 type AuthError struct {
 	msg string
@@ -59,3 +75,120 @@ type SCMValidationError struct {
 func (e *SCMValidationError) Error() string {
 	return e.msg
 }
+
+// APIError is returned when the rundeck api responds with an unexpected status code
+// It matches ErrMissingResource, ErrResourceConflict and ErrAuthFailed with errors.Is
+type APIError struct {
+	// StatusCode is the http status code returned by the server
+	StatusCode int
+	// ErrorCode is the rundeck error code (i.e. api.error.item.doesnotexist) if one was returned
+	ErrorCode string
+	// Message is the error message returned by the server
+	Message string
+	// APIVersion is the api version reported in the error response
+	APIVersion int
+	// Method is the http method of the failed request
+	Method string
+	// Path is the api path of the failed request
+	Path string
+	err  error
+}
+
+// Error returns the error message
+func (e *APIError) Error() string {
+	sentinel := e.sentinel()
+	switch {
+	case sentinel != nil && e.Message != "":
+		return sentinel.Error() + ": " + e.Message
+	case e.Message != "":
+		return e.Message
+	case sentinel != nil:
+		return sentinel.Error()
+	case e.err != nil:
+		return e.err.Error()
+	default:
+		return fmt.Sprintf("%s %s returned status code %d", e.Method, e.Path, e.StatusCode)
+	}
+}
+
+// Unwrap returns the underlying http error
+func (e *APIError) Unwrap() error {
+	return e.err
+}
+
+// Is reports if the error matches one of the package's sentinel errors
+func (e *APIError) Is(target error) bool {
+	sentinel := e.sentinel()
+	return sentinel != nil && target == sentinel
+}
+
+// Unauthorized reports if the request was rejected for authentication or authorization reasons
+func (e *APIError) Unauthorized() bool {
+	return e.StatusCode == 401 || e.StatusCode == 403
+}
+
+// InvalidInput reports if the request was rejected as invalid
+func (e *APIError) InvalidInput() bool {
+	return e.StatusCode == 400
+}
+
+// ServerError reports if the rundeck server failed to handle the request
+func (e *APIError) ServerError() bool {
+	return e.StatusCode >= 500
+}
+
+func (e *APIError) sentinel() error {
+	switch e.StatusCode {
+	case 401, 403:
+		return ErrAuthFailed
+	case 404:
+		return ErrMissingResource
+	case 409:
+		return ErrResourceConflict
+	default:
+		return nil
+	}
+}
+
+// ExecutionError is returned when a waited on execution finishes without succeeding
+// It matches ErrExecutionFailed, ErrExecutionAborted and ErrExecutionTimedOut with errors.Is
+type ExecutionError struct {
+	// Status is the final status of the execution
+	Status ExecutionStatus
+	// Execution is the finished execution
+	Execution *Execution
+}
+
+// Error returns the error message
+func (e *ExecutionError) Error() string {
+	id := 0
+	if e.Execution != nil {
+		id = e.Execution.ID
+	}
+	if sentinel := e.sentinel(); sentinel != nil {
+		return fmt.Sprintf("execution %d: %s", id, sentinel.Error())
+	}
+	if e.Execution != nil && e.Execution.CustomStatus != "" {
+		return fmt.Sprintf("execution %d finished with status %s", id, e.Execution.CustomStatus)
+	}
+	return fmt.Sprintf("execution %d finished with status %s", id, e.Status)
+}
+
+// Is reports if the error matches one of the package's execution sentinel errors
+func (e *ExecutionError) Is(target error) bool {
+	sentinel := e.sentinel()
+	return sentinel != nil && target == sentinel
+}
+
+func (e *ExecutionError) sentinel() error {
+	switch e.Status {
+	case ExecutionStatusFailed, ExecutionStatusFailedWithRetry:
+		return ErrExecutionFailed
+	case ExecutionStatusAborted:
+		return ErrExecutionAborted
+	case ExecutionStatusTimedOut:
+		return ErrExecutionTimedOut
+	default:
+		return nil
+	}
+}
@@ -1,8 +1,12 @@
 package rundeck
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"net/url"
 	"strconv"
 
 	multierror "github.com/hashicorp/go-multierror"
@@ -24,6 +28,11 @@ type AbortedExecution struct {
 	responses.AbortExecutionResponse
 }
 
+// ExecutionInputFiles represents the files uploaded as input for an execution
+type ExecutionInputFiles struct {
+	responses.ExecutionInputFilesResponse
+}
+
 // ExecutionOutput represents the output of an execution
 type ExecutionOutput struct {
 	responses.ExecutionOutputResponse
@@ -32,12 +41,17 @@ type ExecutionOutput struct {
 // GetExecutionInfo returns the details of a job execution
 // http://rundeck.org/docs/api/index.html#execution-info
 func (c *Client) GetExecutionInfo(executionID int) (*Execution, error) {
+	return c.GetExecutionInfoContext(context.Background(), executionID)
+}
+
+// GetExecutionInfoContext returns the details of a job execution
+func (c *Client) GetExecutionInfoContext(ctx context.Context, executionID int) (*Execution, error) {
 	if err := c.checkRequiredAPIVersion(responses.ExecutionResponse{}); err != nil {
 		return nil, err
 	}
 	exec := &Execution{}
 	u := fmt.Sprintf("execution/%d", executionID)
-	res, err := c.httpGet(u, requestJSON(), requestExpects(200))
+	res, err := c.httpGet(ctx, u, requestJSON(), requestExpects(200))
 	if err != nil {
 		return nil, err
 	}
@@ -50,12 +64,17 @@ func (c *Client) GetExecutionInfo(executionID int) (*Execution, error) {
 // GetExecutionState returns the state of an execution
 // http://rundeck.org/docs/api/index.html#execution-state
 func (c *Client) GetExecutionState(executionID int) (*ExecutionState, error) {
+	return c.GetExecutionStateContext(context.Background(), executionID)
+}
+
+// GetExecutionStateContext returns the state of an execution
+func (c *Client) GetExecutionStateContext(ctx context.Context, executionID int) (*ExecutionState, error) {
 	if err := c.checkRequiredAPIVersion(responses.ExecutionStateResponse{}); err != nil {
 		return nil, err
 	}
 	data := &ExecutionState{}
 	u := fmt.Sprintf("execution/%d/state", executionID)
-	res, err := c.httpGet(u, requestJSON(), requestExpects(200))
+	res, err := c.httpGet(ctx, u, requestJSON(), requestExpects(200))
 	if err != nil {
 		return nil, err
 	}
@@ -65,55 +84,246 @@ func (c *Client) GetExecutionState(executionID int) (*ExecutionState, error) {
 	return data, nil
 }
 
+// ExecutionOutputOption is a functional option for retrieving execution output
+type ExecutionOutputOption func(o *executionOutputOptions) error
+
+// executionOutputOptions selects which part of an execution's output to retrieve
+type executionOutputOptions struct {
+	node      string
+	stepCTX   string
+	lastLines int
+	maxLines  int
+	compacted bool
+}
+
+// ExecutionOutputNode limits output to a single node
+func ExecutionOutputNode(node string) ExecutionOutputOption {
+	return func(o *executionOutputOptions) error {
+		if node == "" {
+			return errors.New("node name cannot be empty")
+		}
+		o.node = node
+		return nil
+	}
+}
+
+// ExecutionOutputStep limits output to a single step context (i.e. 1 or 2/1 for a step of a job reference)
+func ExecutionOutputStep(stepCTX string) ExecutionOutputOption {
+	return func(o *executionOutputOptions) error {
+		if stepCTX == "" {
+			return errors.New("step context cannot be empty")
+		}
+		o.stepCTX = stepCTX
+		return nil
+	}
+}
+
+// ExecutionOutputLastLines returns only the last n lines of output
+// The offset is ignored when it is set
+func ExecutionOutputLastLines(n int) ExecutionOutputOption {
+	return func(o *executionOutputOptions) error {
+		if n < 0 {
+			return errors.New("lastlines cannot be negative")
+		}
+		o.lastLines = n
+		return nil
+	}
+}
+
+// ExecutionOutputMaxLines limits the number of lines returned by a single request
+func ExecutionOutputMaxLines(n int) ExecutionOutputOption {
+	return func(o *executionOutputOptions) error {
+		if n < 0 {
+			return errors.New("maxlines cannot be negative")
+		}
+		o.maxLines = n
+		return nil
+	}
+}
+
+// ExecutionOutputCompacted requests the compacted output format which omits values repeated from the previous entry
+// Entries are expanded back to their full form when decoded
+func ExecutionOutputCompacted(b bool) ExecutionOutputOption {
+	return func(o *executionOutputOptions) error {
+		o.compacted = b
+		return nil
+	}
+}
+
+// executionOutputRequest applies the options returning the request path and query params
+func executionOutputRequest(executionID int, opts ...ExecutionOutputOption) (string, map[string]string, error) {
+	o := &executionOutputOptions{}
+	for _, opt := range opts {
+		if err := opt(o); err != nil {
+			return "", nil, &OptionError{msg: multierror.Append(errOption, err).Error()}
+		}
+	}
+	u := fmt.Sprintf("execution/%d/output", executionID)
+	if o.node != "" {
+		u = u + "/node/" + url.PathEscape(o.node)
+	}
+	if o.stepCTX != "" {
+		u = u + "/step/" + o.stepCTX
+	}
+	params := map[string]string{}
+	if o.lastLines > 0 {
+		params["lastlines"] = strconv.Itoa(o.lastLines)
+	}
+	if o.maxLines > 0 {
+		params["maxlines"] = strconv.Itoa(o.maxLines)
+	}
+	if o.compacted {
+		params["compacted"] = "true"
+	}
+	return u, params, nil
+}
+
 // GetExecutionOutput returns the output of an execution
 // http://rundeck.org/docs/api/index.html#execution-output
-func (c *Client) GetExecutionOutput(executionID int) (*ExecutionOutput, error) {
+func (c *Client) GetExecutionOutput(executionID int, opts ...ExecutionOutputOption) (*ExecutionOutput, error) {
+	return c.GetExecutionOutputContext(context.Background(), executionID, opts...)
+}
+
+// GetExecutionOutputContext returns the output of an execution
+func (c *Client) GetExecutionOutputContext(ctx context.Context, executionID int, opts ...ExecutionOutputOption) (*ExecutionOutput, error) {
 	if err := c.checkRequiredAPIVersion(responses.GenericVersionedResponse{}); err != nil {
 		return nil, err
 	}
-	return c.GetExecutionOutputWithOffset(executionID, 0)
+	return c.GetExecutionOutputWithOffsetContext(ctx, executionID, 0, opts...)
 }
 
 // GetExecutionOutputWithOffset gets the output of an execution at the given offset
-func (c *Client) GetExecutionOutputWithOffset(executionID int, offset int) (*ExecutionOutput, error) {
+// http://rundeck.org/docs/api/index.html#execution-output
+func (c *Client) GetExecutionOutputWithOffset(executionID int, offset int, opts ...ExecutionOutputOption) (*ExecutionOutput, error) {
+	return c.GetExecutionOutputWithOffsetContext(context.Background(), executionID, offset, opts...)
+}
+
+// GetExecutionOutputWithOffsetContext gets the output of an execution at the given offset
+func (c *Client) GetExecutionOutputWithOffsetContext(ctx context.Context, executionID int, offset int, opts ...ExecutionOutputOption) (*ExecutionOutput, error) {
 	if err := c.checkRequiredAPIVersion(responses.GenericVersionedResponse{}); err != nil {
 		return nil, err
 	}
-	t := &ExecutionOutput{}
-	params := map[string]string{
-		"offset": strconv.Itoa(offset),
+	u, params, err := executionOutputRequest(executionID, opts...)
+	if err != nil {
+		return nil, err
 	}
-	u := fmt.Sprintf("execution/%d/output", executionID)
-	res, err := c.httpGet(u, requestJSON(), requestExpects(200), queryParams(params))
+	if _, ok := params["lastlines"]; !ok {
+		params["offset"] = strconv.Itoa(offset)
+	}
+	t := &ExecutionOutput{}
+	res, err := c.httpGet(ctx, u, requestJSON(), requestExpects(200), queryParams(params))
 	if err != nil {
 		return nil, err
 	}
 	if jsonErr := json.Unmarshal(res, t); jsonErr != nil {
 		return nil, &UnmarshalError{msg: multierror.Append(errDecoding, jsonErr).Error()}
 	}
+	if t.Compacted {
+		if err := t.expandCompacted(res); err != nil {
+			return nil, &UnmarshalError{msg: multierror.Append(errDecoding, err).Error()}
+		}
+	}
 	return t, nil
 }
 
+// expandCompacted fills in the values compacted output leaves out because they match the previous entry.
+// Only absent keys are inherited: an explicit empty value means the key was removed.
+func (o *ExecutionOutput) expandCompacted(data []byte) error {
+	raw := struct {
+		Entries []json.RawMessage `json:"entries"`
+	}{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	for i := 1; i < len(o.Entries) && i < len(raw.Entries); i++ {
+		keys := map[string]json.RawMessage{}
+		if len(raw.Entries[i]) > 0 && raw.Entries[i][0] == '{' {
+			if err := json.Unmarshal(raw.Entries[i], &keys); err != nil {
+				return err
+			}
+		}
+		absent := func(key string) bool {
+			_, ok := keys[key]
+			return !ok
+		}
+		prev, cur := o.Entries[i-1], &o.Entries[i]
+		if absent("time") {
+			cur.Time = prev.Time
+		}
+		if absent("absolute_time") {
+			cur.AbsoluteTime = prev.AbsoluteTime
+		}
+		if absent("level") {
+			cur.Level = prev.Level
+		}
+		if absent("user") {
+			cur.User = prev.User
+		}
+		if absent("stepctx") {
+			cur.StepCTX = prev.StepCTX
+		}
+		if absent("node") {
+			cur.Node = prev.Node
+		}
+	}
+	return nil
+}
+
+// DownloadExecutionOutput writes the plain text log of an execution to w as it is received
+// http://rundeck.org/docs/api/index.html#execution-output
+func (c *Client) DownloadExecutionOutput(executionID int, w io.Writer, opts ...ExecutionOutputOption) error {
+	return c.DownloadExecutionOutputContext(context.Background(), executionID, w, opts...)
+}
+
+// DownloadExecutionOutputContext writes the plain text log of an execution to w as it is received
+func (c *Client) DownloadExecutionOutputContext(ctx context.Context, executionID int, w io.Writer, opts ...ExecutionOutputOption) error {
+	if err := c.checkRequiredAPIVersion(responses.GenericVersionedResponse{}); err != nil {
+		return err
+	}
+	u, params, err := executionOutputRequest(executionID, opts...)
+	if err != nil {
+		return err
+	}
+	params["format"] = "text"
+	res, err := c.httpGetStream(ctx, u, accept("text/plain"), requestExpects(200), queryParams(params))
+	if err != nil {
+		return err
+	}
+	defer func() { _ = res.Close() }()
+	_, err = io.Copy(w, res)
+	return err
+}
+
 // DeleteExecution deletes an execution
 // http://rundeck.org/docs/api/index.html#delete-an-execution
 func (c *Client) DeleteExecution(executionID int) error {
+	return c.DeleteExecutionContext(context.Background(), executionID)
+}
+
+// DeleteExecutionContext deletes an execution
+func (c *Client) DeleteExecutionContext(ctx context.Context, executionID int) error {
 	if err := c.checkRequiredAPIVersion(responses.GenericVersionedResponse{}); err != nil {
 		return err
 	}
 	u := fmt.Sprintf("execution/%d", executionID)
-	_, err := c.httpDelete(u, requestJSON(), requestExpects(204))
+	_, err := c.httpDelete(ctx, u, requestJSON(), requestExpects(204))
 	return err
 }
 
 // DisableExecution disables an execution
 // http://rundeck.org/docs/api/index.html#disable-executions-for-a-job
 func (c *Client) DisableExecution(executionID int) (bool, error) {
+	return c.DisableExecutionContext(context.Background(), executionID)
+}
+
+// DisableExecutionContext disables an execution
+func (c *Client) DisableExecutionContext(ctx context.Context, executionID int) (bool, error) {
 	if err := c.checkRequiredAPIVersion(responses.ToggleResponse{}); err != nil {
 		return false, err
 	}
 	t := &responses.ToggleResponse{}
 	u := fmt.Sprintf("job/%d/execution/disable", executionID)
-	res, err := c.httpPost(u, requestJSON(), requestExpects(200))
+	res, err := c.httpPost(ctx, u, requestJSON(), requestExpects(200))
 	if err != nil {
 		return false, err
 	}
@@ -126,12 +336,17 @@ func (c *Client) DisableExecution(executionID int) (bool, error) {
 // EnableExecution enables an execution
 // http://rundeck.org/docs/api/index.html#enable-executions-for-a-job
 func (c *Client) EnableExecution(executionID int) (bool, error) {
+	return c.EnableExecutionContext(context.Background(), executionID)
+}
+
+// EnableExecutionContext enables an execution
+func (c *Client) EnableExecutionContext(ctx context.Context, executionID int) (bool, error) {
 	if err := c.checkRequiredAPIVersion(responses.ToggleResponse{}); err != nil {
 		return false, err
 	}
 	t := &responses.ToggleResponse{}
 	u := fmt.Sprintf("job/%d/execution/enable", executionID)
-	res, err := c.httpPost(u, requestExpects(200), requestJSON())
+	res, err := c.httpPost(ctx, u, requestExpects(200), requestJSON())
 	if err != nil {
 		return false, err
 	}
@@ -143,11 +358,24 @@ func (c *Client) EnableExecution(executionID int) (bool, error) {
 
 // ListInputFilesForExecution lists input files used for an execution
 // http://rundeck.org/docs/api/index.html#list-input-files-for-an-execution
-func (c *Client) ListInputFilesForExecution() error {
+func (c *Client) ListInputFilesForExecution(executionID int) (*ExecutionInputFiles, error) {
+	return c.ListInputFilesForExecutionContext(context.Background(), executionID)
+}
+
+// ListInputFilesForExecutionContext lists input files used for an execution
+func (c *Client) ListInputFilesForExecutionContext(ctx context.Context, executionID int) (*ExecutionInputFiles, error) {
 	if err := c.checkRequiredAPIVersion(responses.ExecutionInputFilesResponse{}); err != nil {
-		return err
+		return nil, err
+	}
+	res, err := c.httpGet(ctx, "execution/"+strconv.Itoa(executionID)+"/input/files", requestJSON(), requestExpects(200))
+	if err != nil {
+		return nil, err
 	}
-	return fmt.Errorf("not yet implemented")
+	data := &ExecutionInputFiles{}
+	if jsonErr := json.Unmarshal(res, data); jsonErr != nil {
+		return nil, &UnmarshalError{msg: multierror.Append(errDecoding, jsonErr).Error()}
+	}
+	return data, nil
 }
 
 // AbortExecutionOption is a function option type for AbortExection options
@@ -164,6 +392,11 @@ func AbortExecutionAsUser(runAsUser string) AbortExecutionOption {
 // AbortExecution lists input files used for an execution
 // http://rundeck.org/docs/api/index.html#aborting-executions
 func (c *Client) AbortExecution(executionID int, opts ...AbortExecutionOption) (*AbortedExecution, error) {
+	return c.AbortExecutionContext(context.Background(), executionID, opts...)
+}
+
+// AbortExecutionContext lists input files used for an execution
+func (c *Client) AbortExecutionContext(ctx context.Context, executionID int, opts ...AbortExecutionOption) (*AbortedExecution, error) {
 	if err := c.checkRequiredAPIVersion(responses.AbortExecutionResponse{}); err != nil {
 		return nil, err
 	}
@@ -179,7 +412,7 @@ func (c *Client) AbortExecution(executionID int, opts ...AbortExecutionOption) (
 	if val, ok := (*jobOpts)["runAsUser"]; ok {
 		u = fmt.Sprintf("%s?asUser=%s", u, val)
 	}
-	res, err := c.httpGet(u, requestJSON(), requestExpects(200))
+	res, err := c.httpGet(ctx, u, requestJSON(), requestExpects(200))
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,233 @@
+package rundeck
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	multierror "github.com/hashicorp/go-multierror"
+	responses "github.com/lusis/go-rundeck/pkg/rundeck/responses"
+)
+
+const (
+	// DefaultFollowMinInterval is the shortest wait between polls when following output
+	DefaultFollowMinInterval = 500 * time.Millisecond
+	// DefaultFollowMaxInterval is the longest wait between polls when following output
+	DefaultFollowMaxInterval = 10 * time.Second
+)
+
+// ExecutionLogEntry is a single entry of an execution's output
+type ExecutionLogEntry struct {
+	responses.ExecutionOutputEntryResponse
+}
+
+// FollowOption is a functional option for following execution output
+type FollowOption func(f *ExecutionOutputFollower) error
+
+// FollowFromOffset starts following the output at the given byte offset instead of the beginning
+func FollowFromOffset(offset int) FollowOption {
+	return func(f *ExecutionOutputFollower) error {
+		if offset < 0 {
+			return errors.New("offset cannot be negative")
+		}
+		f.offset = offset
+		return nil
+	}
+}
+
+// FollowPollInterval sets the range of the wait between polls
+// the wait starts at min, grows while no new output arrives and drops back to min when it does
+func FollowPollInterval(min, max time.Duration) FollowOption {
+	return func(f *ExecutionOutputFollower) error {
+		if min <= 0 || max < min {
+			return errors.New("poll intervals must be positive and max cannot be less than min")
+		}
+		f.minInterval = min
+		f.maxInterval = max
+		return nil
+	}
+}
+
+// FollowOutput selects the output to follow with the same options as GetExecutionOutput
+// ExecutionOutputLastLines only applies to the first poll so following starts from the last lines
+func FollowOutput(opts ...ExecutionOutputOption) FollowOption {
+	return func(f *ExecutionOutputFollower) error {
+		f.outputOpts = append(f.outputOpts, opts...)
+		return nil
+	}
+}
+
+// ExecutionOutputFollower follows the output of an execution until it completes
+// Entries can be consumed either from the Entries channel or as plain text lines with Read, but not both
+type ExecutionOutputFollower struct {
+	client      *Client
+	executionID int
+	offset      int
+	minInterval time.Duration
+	maxInterval time.Duration
+	outputOpts  []ExecutionOutputOption
+	entries     chan *ExecutionLogEntry
+	done        chan struct{}
+	status      ExecutionStatus
+	err         error
+	buf         []byte
+	sync.Mutex
+}
+
+// FollowExecutionOutput polls the output of an execution until the execution completes and all output is read
+// Following stops early if ctx is cancelled
+// http://rundeck.org/docs/api/index.html#execution-output
+func (c *Client) FollowExecutionOutput(ctx context.Context, executionID int, opts ...FollowOption) *ExecutionOutputFollower {
+	f := &ExecutionOutputFollower{
+		client:      c,
+		executionID: executionID,
+		minInterval: DefaultFollowMinInterval,
+		maxInterval: DefaultFollowMaxInterval,
+		entries:     make(chan *ExecutionLogEntry),
+		done:        make(chan struct{}),
+	}
+	for _, opt := range opts {
+		if err := opt(f); err != nil {
+			f.finish("", &OptionError{msg: multierror.Append(errOption, err).Error()})
+			return f
+		}
+	}
+	go f.follow(ctx)
+	return f
+}
+
+func (f *ExecutionOutputFollower) follow(ctx context.Context) {
+	wait := f.minInterval
+	opts := f.outputOpts
+	for {
+		data, err := f.client.GetExecutionOutputWithOffsetContext(ctx, f.executionID, f.Offset(), opts...)
+		if err != nil {
+			if ctx.Err() != nil {
+				err = ctx.Err()
+			}
+			f.finish("", err)
+			return
+		}
+		for i := range data.Entries {
+			select {
+			case f.entries <- &ExecutionLogEntry{data.Entries[i]}:
+			case <-ctx.Done():
+				f.finish("", ctx.Err())
+				return
+			}
+		}
+		if data.Offset != "" {
+			offset, convErr := strconv.Atoi(data.Offset)
+			if convErr != nil {
+				f.finish("", &UnmarshalError{msg: multierror.Append(errDecoding, convErr).Error()})
+				return
+			}
+			f.Lock()
+			f.offset = offset
+			f.Unlock()
+		}
+		// after the first poll we continue from the offset instead of the last lines
+		opts = append(f.outputOpts[:len(f.outputOpts):len(f.outputOpts)], ExecutionOutputLastLines(0))
+		if data.ExecCompleted && data.Completed {
+			f.finish(ExecutionStatus(strings.ToLower(data.ExecState)), nil)
+			return
+		}
+		wait = nextFollowInterval(wait, len(data.Entries) > 0, f.minInterval, f.maxInterval)
+		// rundeck asks us to back off when the log isn't available yet (i.e. on another cluster member)
+		if backoff := time.Duration(data.RetryBackoff) * time.Millisecond; backoff > wait {
+			wait = backoff
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			f.finish("", ctx.Err())
+			return
+		case <-timer.C:
+		}
+	}
+}
+
+// nextFollowInterval returns the wait before the next poll
+func nextFollowInterval(current time.Duration, gotEntries bool, min, max time.Duration) time.Duration {
+	if gotEntries {
+		return min
+	}
+	next := current * 2
+	if next > max {
+		next = max
+	}
+	return next
+}
+
+func (f *ExecutionOutputFollower) finish(status ExecutionStatus, err error) {
+	f.Lock()
+	f.status = status
+	f.err = err
+	f.Unlock()
+	close(f.entries)
+	close(f.done)
+}
+
+// Entries returns the channel log entries are sent on
+// The channel is closed when following stops
+func (f *ExecutionOutputFollower) Entries() <-chan *ExecutionLogEntry {
+	return f.entries
+}
+
+// Done returns a channel that is closed when following stops
+func (f *ExecutionOutputFollower) Done() <-chan struct{} {
+	return f.done
+}
+
+// Wait blocks until following stops and returns the final status of the execution
+// Entries must be drained (or ctx cancelled) for following to stop
+func (f *ExecutionOutputFollower) Wait() (ExecutionStatus, error) {
+	<-f.done
+	return f.Status(), f.Err()
+}
+
+// Status returns the final status of the execution once following has stopped
+func (f *ExecutionOutputFollower) Status() ExecutionStatus {
+	f.Lock()
+	defer f.Unlock()
+	return f.status
+}
+
+// Err returns the error that stopped following, if any
+func (f *ExecutionOutputFollower) Err() error {
+	f.Lock()
+	defer f.Unlock()
+	return f.err
+}
+
+// Offset returns the offset of the output fetched so far
+// It can be used with FollowFromOffset to resume following later
+func (f *ExecutionOutputFollower) Offset() int {
+	f.Lock()
+	defer f.Unlock()
+	return f.offset
+}
+
+// Read implements io.Reader returning each log entry as a line of text
+// It returns io.EOF once the execution has completed and all output was read
+func (f *ExecutionOutputFollower) Read(p []byte) (int, error) {
+	for len(f.buf) == 0 {
+		entry, ok := <-f.entries
+		if !ok {
+			if err := f.Err(); err != nil {
+				return 0, err
+			}
+			return 0, io.EOF
+		}
+		f.buf = append(f.buf, entry.Log...)
+		f.buf = append(f.buf, '\n')
+	}
+	n := copy(p, f.buf)
+	f.buf = f.buf[n:]
+	return n, nil
+}
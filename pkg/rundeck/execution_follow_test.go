@@ -0,0 +1,137 @@
+package rundeck
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// testOutputHandler serves the pages of output in order, one per poll
+func testOutputHandler(t *testing.T, pages []string) http.HandlerFunc {
+	polls := 0
+	return func(w http.ResponseWriter, r *http.Request) {
+		require.True(t, strings.HasSuffix(r.URL.Path, "/execution/1/output"))
+		require.Equal(t, fmt.Sprintf("%d", polls*10), r.URL.Query().Get("offset"))
+		page := pages[len(pages)-1]
+		if polls < len(pages) {
+			page = pages[polls]
+		}
+		polls++
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(page))
+	}
+}
+
+func testOutputPage(offset int, execCompleted bool, state string, logs ...string) string {
+	entries := []string{}
+	for _, l := range logs {
+		entries = append(entries, fmt.Sprintf(`{"log":%q,"node":"node-1","stepctx":"1"}`, l))
+	}
+	return fmt.Sprintf(`{"id":"1","offset":"%d","completed":%t,"execCompleted":%t,"execState":%q,"entries":[%s]}`,
+		offset, execCompleted, execCompleted, state, strings.Join(entries, ","))
+}
+
+func TestFollowExecutionOutput(t *testing.T) {
+	client, server, cErr := newTestRundeckClientWithHandler(testOutputHandler(t, []string{
+		testOutputPage(10, false, "running", "one", "two"),
+		testOutputPage(20, false, "running"),
+		testOutputPage(30, true, "failed", "three"),
+	}))
+	defer server.Close()
+	require.NoError(t, cErr)
+	f := client.FollowExecutionOutput(context.Background(), 1, FollowPollInterval(time.Millisecond, 5*time.Millisecond))
+	logs := []string{}
+	for entry := range f.Entries() {
+		require.Equal(t, "node-1", entry.Node)
+		logs = append(logs, entry.Log)
+	}
+	status, err := f.Wait()
+	require.NoError(t, err)
+	require.Equal(t, ExecutionStatusFailed, status)
+	require.Equal(t, []string{"one", "two", "three"}, logs)
+	require.Equal(t, 30, f.Offset())
+}
+
+func TestFollowExecutionOutputLastLines(t *testing.T) {
+	var queries []string
+	client, server, cErr := newTestRundeckClientWithHandler(func(w http.ResponseWriter, r *http.Request) {
+		require.True(t, strings.HasSuffix(r.URL.Path, "/execution/1/output/node/node-1"))
+		queries = append(queries, r.URL.RawQuery)
+		if len(queries) == 1 {
+			_, _ = w.Write([]byte(testOutputPage(500, false, "running", "tail")))
+			return
+		}
+		_, _ = w.Write([]byte(testOutputPage(510, true, "succeeded", "end")))
+	})
+	defer server.Close()
+	require.NoError(t, cErr)
+	f := client.FollowExecutionOutput(context.Background(), 1,
+		FollowPollInterval(time.Millisecond, time.Millisecond),
+		FollowOutput(ExecutionOutputNode("node-1"), ExecutionOutputLastLines(5)),
+	)
+	out, err := ioutil.ReadAll(f)
+	require.NoError(t, err)
+	require.Equal(t, "tail\nend\n", string(out))
+	require.Equal(t, []string{"lastlines=5", "offset=500"}, queries)
+}
+
+func TestFollowExecutionOutputReader(t *testing.T) {
+	client, server, cErr := newTestRundeckClientWithHandler(testOutputHandler(t, []string{
+		testOutputPage(10, false, "running", "one"),
+		testOutputPage(20, true, "succeeded", "two"),
+	}))
+	defer server.Close()
+	require.NoError(t, cErr)
+	f := client.FollowExecutionOutput(context.Background(), 1, FollowPollInterval(time.Millisecond, time.Millisecond))
+	out, err := ioutil.ReadAll(f)
+	require.NoError(t, err)
+	require.Equal(t, "one\ntwo\n", string(out))
+	require.Equal(t, ExecutionStatusSucceeded, f.Status())
+}
+
+func TestFollowExecutionOutputCancel(t *testing.T) {
+	client, server, cErr := newTestRundeckClientWithHandler(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(testOutputPage(0, false, "running")))
+	})
+	defer server.Close()
+	require.NoError(t, cErr)
+	ctx, cancel := context.WithCancel(context.Background())
+	f := client.FollowExecutionOutput(ctx, 1, FollowPollInterval(time.Hour, time.Hour))
+	cancel()
+	_, err := f.Wait()
+	require.Equal(t, context.Canceled, err)
+	_, ok := <-f.Entries()
+	require.False(t, ok)
+}
+
+func TestFollowExecutionOutputHTTPError(t *testing.T) {
+	client, server, cErr := newTestRundeckClient([]byte(""), "application/json", 404)
+	defer server.Close()
+	require.NoError(t, cErr)
+	f := client.FollowExecutionOutput(context.Background(), 1)
+	_, err := ioutil.ReadAll(f)
+	require.Error(t, err)
+	require.Error(t, f.Err())
+}
+
+func TestFollowExecutionOutputInvalidOption(t *testing.T) {
+	client, server, cErr := newTestRundeckClient([]byte(""), "application/json", 200)
+	defer server.Close()
+	require.NoError(t, cErr)
+	f := client.FollowExecutionOutput(context.Background(), 1, FollowPollInterval(time.Second, time.Millisecond))
+	_, err := f.Wait()
+	require.IsType(t, &OptionError{}, err)
+}
+
+func TestNextFollowInterval(t *testing.T) {
+	min, max := time.Second, 5*time.Second
+	require.Equal(t, 2*time.Second, nextFollowInterval(time.Second, false, min, max))
+	require.Equal(t, max, nextFollowInterval(4*time.Second, false, min, max))
+	require.Equal(t, min, nextFollowInterval(4*time.Second, true, min, max))
+}
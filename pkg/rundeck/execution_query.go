@@ -0,0 +1,259 @@
+package rundeck
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	multierror "github.com/hashicorp/go-multierror"
+)
+
+// ExecutionStatus is the status of an execution as used by the execution query api
+type ExecutionStatus string
+
+const (
+	// ExecutionStatusSucceeded matches executions that succeeded
+	ExecutionStatusSucceeded ExecutionStatus = "succeeded"
+	// ExecutionStatusFailed matches executions that failed
+	ExecutionStatusFailed ExecutionStatus = "failed"
+	// ExecutionStatusAborted matches executions that were aborted
+	ExecutionStatusAborted ExecutionStatus = "aborted"
+	// ExecutionStatusRunning matches executions that are still running
+	ExecutionStatusRunning ExecutionStatus = "running"
+	// ExecutionStatusTimedOut matches executions that timed out
+	ExecutionStatusTimedOut ExecutionStatus = "timedout"
+	// ExecutionStatusFailedWithRetry matches executions that failed and will be retried
+	ExecutionStatusFailedWithRetry ExecutionStatus = "failed-with-retry"
+	// ExecutionStatusScheduled matches executions scheduled to run in the future
+	ExecutionStatusScheduled ExecutionStatus = "scheduled"
+	// ExecutionStatusOther matches executions with a custom status
+	ExecutionStatusOther ExecutionStatus = "other"
+)
+
+// ExecutionType is the way an execution was started
+type ExecutionType string
+
+const (
+	// ExecutionTypeScheduled matches executions started by the scheduler
+	ExecutionTypeScheduled ExecutionType = "scheduled"
+	// ExecutionTypeUser matches executions started by a user
+	ExecutionTypeUser ExecutionType = "user"
+	// ExecutionTypeUserScheduled matches executions a user scheduled to run at a later time
+	ExecutionTypeUserScheduled ExecutionType = "user-scheduled"
+)
+
+var validExecutionStatuses = map[ExecutionStatus]bool{
+	ExecutionStatusSucceeded:       true,
+	ExecutionStatusFailed:          true,
+	ExecutionStatusAborted:         true,
+	ExecutionStatusRunning:         true,
+	ExecutionStatusTimedOut:        true,
+	ExecutionStatusFailedWithRetry: true,
+	ExecutionStatusScheduled:       true,
+	ExecutionStatusOther:           true,
+}
+
+var validExecutionTypes = map[ExecutionType]bool{
+	ExecutionTypeScheduled:     true,
+	ExecutionTypeUser:          true,
+	ExecutionTypeUserScheduled: true,
+}
+
+// ExecutionQuery is a typed query for a project's executions
+// Zero values are not sent to the server
+// http://rundeck.org/docs/api/index.html#execution-query
+type ExecutionQuery struct {
+	// Status limits results to executions with the given status
+	Status ExecutionStatus
+	// AbortedBy limits results to executions aborted by the given user
+	AbortedBy string
+	// User limits results to executions started by the given user
+	User string
+	// Recent limits results to executions that completed within the duration
+	Recent time.Duration
+	// Older limits results to executions that completed before the duration ago
+	Older time.Duration
+	// Begin limits results to executions that completed after the time
+	Begin time.Time
+	// End limits results to executions that completed before the time
+	End time.Time
+	// OnlyAdHoc limits results to adhoc executions
+	OnlyAdHoc bool
+	// ExcludeAdHoc excludes adhoc executions from the results
+	ExcludeAdHoc bool
+	// JobIDs limits results to executions of the given job ids
+	JobIDs []string
+	// ExcludeJobIDs excludes executions of the given job ids
+	ExcludeJobIDs []string
+	// JobNames limits results to executions of jobs with the given full names (group/name)
+	JobNames []string
+	// ExcludeJobNames excludes executions of jobs with the given full names (group/name)
+	ExcludeJobNames []string
+	// JobFilter limits results to jobs with names containing the value
+	JobFilter string
+	// ExcludeJobFilter excludes jobs with names containing the value
+	ExcludeJobFilter string
+	// GroupPath limits results to jobs in the group or any subgroup
+	// use "-" to match jobs without a group
+	GroupPath string
+	// GroupPathExact limits results to jobs in exactly the group
+	GroupPathExact string
+	// ExcludeGroupPath excludes jobs in the group or any subgroup
+	ExcludeGroupPath string
+	// ExcludeGroupPathExact excludes jobs in exactly the group
+	ExcludeGroupPathExact string
+	// ExecutionType limits results to executions started in the given way
+	ExecutionType ExecutionType
+	// Max is the maximum number of results to return
+	Max int
+	// Offset is the offset of the first result
+	Offset int
+}
+
+// Validate checks the query for invalid values
+func (q *ExecutionQuery) Validate() error {
+	if q.Status != "" && !validExecutionStatuses[q.Status] {
+		return fmt.Errorf("invalid execution status: %s", q.Status)
+	}
+	if q.ExecutionType != "" && !validExecutionTypes[q.ExecutionType] {
+		return fmt.Errorf("invalid execution type: %s", q.ExecutionType)
+	}
+	if q.Recent < 0 || q.Older < 0 {
+		return fmt.Errorf("recent and older cannot be negative")
+	}
+	if (q.Recent > 0 && q.Recent < time.Second) || (q.Older > 0 && q.Older < time.Second) {
+		return fmt.Errorf("recent and older must be at least one second")
+	}
+	if !q.Begin.IsZero() && !q.End.IsZero() && q.End.Before(q.Begin) {
+		return fmt.Errorf("end (%s) is before begin (%s)", q.End, q.Begin)
+	}
+	if q.OnlyAdHoc && q.ExcludeAdHoc {
+		return fmt.Errorf("only one of OnlyAdHoc and ExcludeAdHoc can be set")
+	}
+	if q.Max < 0 || q.Offset < 0 {
+		return fmt.Errorf("max and offset cannot be negative")
+	}
+	return nil
+}
+
+// Values validates the query and encodes it as query params
+// A nil query matches all executions
+func (q *ExecutionQuery) Values() (url.Values, error) {
+	if q == nil {
+		return url.Values{}, nil
+	}
+	if err := q.Validate(); err != nil {
+		return nil, err
+	}
+	v := url.Values{}
+	setIfNotEmpty := func(key, value string) {
+		if value != "" {
+			v.Set(key, value)
+		}
+	}
+	setIfNotEmpty("statusFilter", string(q.Status))
+	setIfNotEmpty("abortedbyFilter", q.AbortedBy)
+	setIfNotEmpty("userFilter", q.User)
+	setIfNotEmpty("jobFilter", q.JobFilter)
+	setIfNotEmpty("excludeJobFilter", q.ExcludeJobFilter)
+	setIfNotEmpty("groupPath", q.GroupPath)
+	setIfNotEmpty("groupPathExact", q.GroupPathExact)
+	setIfNotEmpty("excludeGroupPath", q.ExcludeGroupPath)
+	setIfNotEmpty("excludeGroupPathExact", q.ExcludeGroupPathExact)
+	setIfNotEmpty("executionTypeFilter", string(q.ExecutionType))
+	if q.Recent > 0 {
+		v.Set("recentFilter", relativeTimeFilter(q.Recent))
+	}
+	if q.Older > 0 {
+		v.Set("olderFilter", relativeTimeFilter(q.Older))
+	}
+	if !q.Begin.IsZero() {
+		v.Set("begin", strconv.FormatInt(q.Begin.UnixNano()/int64(time.Millisecond), 10))
+	}
+	if !q.End.IsZero() {
+		v.Set("end", strconv.FormatInt(q.End.UnixNano()/int64(time.Millisecond), 10))
+	}
+	if q.OnlyAdHoc {
+		v.Set("adhoc", "true")
+	}
+	if q.ExcludeAdHoc {
+		v.Set("adhoc", "false")
+	}
+	for _, id := range q.JobIDs {
+		v.Add("jobIdListFilter", id)
+	}
+	for _, id := range q.ExcludeJobIDs {
+		v.Add("excludeJobIdListFilter", id)
+	}
+	for _, name := range q.JobNames {
+		v.Add("jobListFilter", name)
+	}
+	for _, name := range q.ExcludeJobNames {
+		v.Add("excludeJobListFilter", name)
+	}
+	if q.Max > 0 {
+		v.Set("max", strconv.Itoa(q.Max))
+	}
+	if q.Offset > 0 {
+		v.Set("offset", strconv.Itoa(q.Offset))
+	}
+	return v, nil
+}
+
+// relativeTimeFilter converts a duration to rundeck's relative time format
+// using the largest unit that represents the duration exactly
+func relativeTimeFilter(d time.Duration) string {
+	week := 7 * 24 * time.Hour
+	day := 24 * time.Hour
+	switch {
+	case d%week == 0:
+		return fmt.Sprintf("%dw", d/week)
+	case d%day == 0:
+		return fmt.Sprintf("%dd", d/day)
+	case d%time.Hour == 0:
+		return fmt.Sprintf("%dh", d/time.Hour)
+	case d%time.Minute == 0:
+		return fmt.Sprintf("%dn", d/time.Minute)
+	default:
+		return fmt.Sprintf("%ds", d/time.Second)
+	}
+}
+
+// QueryProjectExecutions lists a project's executions matching the query
+// http://rundeck.org/docs/api/index.html#execution-query
+func (c *Client) QueryProjectExecutions(projectID string, q *ExecutionQuery) (*Executions, error) {
+	return c.QueryProjectExecutionsContext(context.Background(), projectID, q)
+}
+
+// QueryProjectExecutionsContext lists a project's executions matching the query
+func (c *Client) QueryProjectExecutionsContext(ctx context.Context, projectID string, q *ExecutionQuery) (*Executions, error) {
+	params, err := q.Values()
+	if err != nil {
+		return nil, &OptionError{msg: multierror.Append(errOption, err).Error()}
+	}
+	return c.listProjectExecutions(ctx, projectID, params)
+}
+
+// IterateExecutionQuery returns an iterator over all of a project's executions matching the query
+// The Max and Offset of the query are ignored in favor of the paging options
+// http://rundeck.org/docs/api/index.html#execution-query
+func (c *Client) IterateExecutionQuery(projectID string, q *ExecutionQuery, opts ...PagingOption) *ExecutionIterator {
+	return c.IterateExecutionQueryContext(context.Background(), projectID, q, opts...)
+}
+
+// IterateExecutionQueryContext returns an iterator over all of a project's executions matching the query
+func (c *Client) IterateExecutionQueryContext(ctx context.Context, projectID string, q *ExecutionQuery, opts ...PagingOption) *ExecutionIterator {
+	params, err := q.Values()
+	it := &ExecutionIterator{
+		pager: newPager(ctx, params, opts...),
+		fetch: func(ctx context.Context, params url.Values) (*Executions, error) {
+			return c.listProjectExecutions(ctx, projectID, params)
+		},
+	}
+	if err != nil {
+		it.err = &OptionError{msg: multierror.Append(errOption, err).Error()}
+	}
+	return it
+}
@@ -0,0 +1,139 @@
+package rundeck
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lusis/go-rundeck/pkg/rundeck/responses"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecutionQueryValues(t *testing.T) {
+	begin := time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)
+	q := &ExecutionQuery{
+		Status:        ExecutionStatusFailed,
+		User:          "admin",
+		Recent:        48 * time.Hour,
+		Older:         90 * time.Minute,
+		Begin:         begin,
+		ExcludeAdHoc:  true,
+		JobIDs:        []string{"abc", "def"},
+		ExcludeJobIDs: []string{"ghi"},
+		JobNames:      []string{"group/job"},
+		GroupPath:     "-",
+		ExecutionType: ExecutionTypeScheduled,
+		Max:           50,
+	}
+	v, err := q.Values()
+	require.NoError(t, err)
+	require.Equal(t, "failed", v.Get("statusFilter"))
+	require.Equal(t, "admin", v.Get("userFilter"))
+	require.Equal(t, "2d", v.Get("recentFilter"))
+	require.Equal(t, "90n", v.Get("olderFilter"))
+	require.Equal(t, "1514764800000", v.Get("begin"))
+	require.Empty(t, v.Get("end"))
+	require.Equal(t, "false", v.Get("adhoc"))
+	require.Equal(t, []string{"abc", "def"}, v["jobIdListFilter"])
+	require.Equal(t, []string{"ghi"}, v["excludeJobIdListFilter"])
+	require.Equal(t, []string{"group/job"}, v["jobListFilter"])
+	require.Equal(t, "-", v.Get("groupPath"))
+	require.Equal(t, "scheduled", v.Get("executionTypeFilter"))
+	require.Equal(t, "50", v.Get("max"))
+	require.Empty(t, v.Get("offset"))
+}
+
+func TestExecutionQueryNil(t *testing.T) {
+	var q *ExecutionQuery
+	v, err := q.Values()
+	require.NoError(t, err)
+	require.Len(t, v, 0)
+}
+
+func TestRelativeTimeFilter(t *testing.T) {
+	testCases := map[time.Duration]string{
+		14 * 24 * time.Hour: "2w",
+		3 * 24 * time.Hour:  "3d",
+		5 * time.Hour:       "5h",
+		30 * time.Minute:    "30n",
+		45 * time.Second:    "45s",
+	}
+	for in, expected := range testCases {
+		require.Equal(t, expected, relativeTimeFilter(in), in.String())
+	}
+}
+
+func TestExecutionQueryValidate(t *testing.T) {
+	now := time.Now()
+	testCases := map[string]*ExecutionQuery{
+		"status":   {Status: "bogus"},
+		"type":     {ExecutionType: "bogus"},
+		"negative": {Recent: -1 * time.Hour},
+		"subsec":   {Older: time.Millisecond},
+		"range":    {Begin: now, End: now.Add(-1 * time.Hour)},
+		"adhoc":    {OnlyAdHoc: true, ExcludeAdHoc: true},
+		"max":      {Max: -1},
+	}
+	for name, q := range testCases {
+		require.Error(t, q.Validate(), name)
+	}
+	require.NoError(t, (&ExecutionQuery{Status: ExecutionStatusRunning}).Validate())
+}
+
+func TestQueryProjectExecutions(t *testing.T) {
+	jsonfile, err := responses.GetTestData(responses.ListRunningExecutionsResponseTestFile)
+	require.NoError(t, err)
+	client, server, cErr := newTestRundeckClientWithHandler(func(w http.ResponseWriter, r *http.Request) {
+		require.True(t, strings.HasSuffix(r.URL.Path, "/project/testproject/executions"))
+		require.Equal(t, "succeeded", r.URL.Query().Get("statusFilter"))
+		require.Equal(t, []string{"a", "b"}, r.URL.Query()["jobIdListFilter"])
+		_, _ = w.Write(jsonfile)
+	})
+	defer server.Close()
+	require.NoError(t, cErr)
+	obj, oErr := client.QueryProjectExecutions("testproject", &ExecutionQuery{
+		Status: ExecutionStatusSucceeded,
+		JobIDs: []string{"a", "b"},
+	})
+	require.NoError(t, oErr)
+	require.NotEmpty(t, obj.Executions)
+}
+
+func TestQueryProjectExecutionsInvalid(t *testing.T) {
+	client, server, cErr := newTestRundeckClient([]byte(""), "application/json", 200)
+	defer server.Close()
+	require.NoError(t, cErr)
+	obj, oErr := client.QueryProjectExecutions("testproject", &ExecutionQuery{Status: "bogus"})
+	require.Error(t, oErr)
+	require.IsType(t, &OptionError{}, oErr)
+	require.Nil(t, obj)
+}
+
+func TestIterateExecutionQuery(t *testing.T) {
+	requests := 0
+	client, server, cErr := newTestRundeckClientWithHandler(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "admin", r.URL.Query().Get("userFilter"))
+		testPagedExecutionsHandler(12, &requests)(w, r)
+	})
+	defer server.Close()
+	require.NoError(t, cErr)
+	it := client.IterateExecutionQuery("testproject", &ExecutionQuery{User: "admin", Max: 1000}, PageSize(5))
+	count := 0
+	for it.Next() {
+		count++
+	}
+	require.NoError(t, it.Err())
+	require.Equal(t, 12, count)
+	require.Equal(t, 3, requests)
+}
+
+func TestIterateExecutionQueryInvalid(t *testing.T) {
+	client, server, cErr := newTestRundeckClient([]byte(""), "application/json", 200)
+	defer server.Close()
+	require.NoError(t, cErr)
+	it := client.IterateExecutionQuery("testproject", &ExecutionQuery{Max: -1})
+	require.False(t, it.Next())
+	require.IsType(t, &OptionError{}, it.Err())
+}
@@ -1,7 +1,10 @@
 package rundeck
 
 import (
+	"bytes"
 	"fmt"
+	"net/http"
+	"strings"
 	"testing"
 
 	"github.com/lusis/go-rundeck/pkg/rundeck/responses"
@@ -136,6 +139,71 @@ func TestGetExecutionOutput(t *testing.T) {
 	require.NotNil(t, obj)
 }
 
+func TestGetExecutionOutputNodeStep(t *testing.T) {
+	client, server, cErr := newTestRundeckClientWithHandler(func(w http.ResponseWriter, r *http.Request) {
+		require.True(t, strings.HasSuffix(r.URL.Path, "/execution/1/output/node/node-1/step/2/1"), r.URL.Path)
+		require.Equal(t, "20", r.URL.Query().Get("lastlines"))
+		require.Equal(t, "100", r.URL.Query().Get("maxlines"))
+		require.Empty(t, r.URL.Query().Get("offset"))
+		_, _ = w.Write([]byte(`{"id":"1","offset":"10","entries":[{"log":"hello","node":"node-1","stepctx":"2/1"}]}`))
+	})
+	defer server.Close()
+	require.NoError(t, cErr)
+	obj, err := client.GetExecutionOutput(1,
+		ExecutionOutputNode("node-1"),
+		ExecutionOutputStep("2/1"),
+		ExecutionOutputLastLines(20),
+		ExecutionOutputMaxLines(100),
+	)
+	require.NoError(t, err)
+	require.Len(t, obj.Entries, 1)
+}
+
+func TestGetExecutionOutputCompacted(t *testing.T) {
+	client, server, cErr := newTestRundeckClientWithHandler(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "true", r.URL.Query().Get("compacted"))
+		_, _ = w.Write([]byte(`{"id":"1","offset":"10","compacted":true,"compactedAttr":"log","entries":[
+			{"log":"one","node":"node-1","stepctx":"1","level":"NORMAL"},
+			"two",
+			{"log":"three","node":"node-2"},
+			{"log":"four","stepctx":""}
+		]}`))
+	})
+	defer server.Close()
+	require.NoError(t, cErr)
+	obj, err := client.GetExecutionOutputWithOffset(1, 0, ExecutionOutputCompacted(true))
+	require.NoError(t, err)
+	require.Len(t, obj.Entries, 4)
+	require.Equal(t, "two", obj.Entries[1].Log)
+	require.Equal(t, "node-1", obj.Entries[1].Node)
+	require.Equal(t, "node-2", obj.Entries[2].Node)
+	require.Equal(t, "1", obj.Entries[2].StepCTX)
+	require.Equal(t, "NORMAL", obj.Entries[2].Level)
+	require.Equal(t, "", obj.Entries[3].StepCTX)
+	require.Equal(t, "node-2", obj.Entries[3].Node)
+}
+
+func TestGetExecutionOutputInvalidOption(t *testing.T) {
+	client, server, cErr := newTestRundeckClient([]byte(""), "application/json", 200)
+	defer server.Close()
+	require.NoError(t, cErr)
+	_, err := client.GetExecutionOutput(1, ExecutionOutputNode(""))
+	require.IsType(t, &OptionError{}, err)
+}
+
+func TestDownloadExecutionOutput(t *testing.T) {
+	client, server, cErr := newTestRundeckClientWithHandler(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "text", r.URL.Query().Get("format"))
+		w.Header().Set("Content-Type", "text/plain")
+		_, _ = w.Write([]byte("line one\nline two\n"))
+	})
+	defer server.Close()
+	require.NoError(t, cErr)
+	var buf bytes.Buffer
+	require.NoError(t, client.DownloadExecutionOutput(1, &buf))
+	require.Equal(t, "line one\nline two\n", buf.String())
+}
+
 func TestDeleteExecution(t *testing.T) {
 	client, server, cErr := newTestRundeckClient([]byte(""), "application/json", 204)
 	defer server.Close()
@@ -289,3 +357,24 @@ func TestAbortExecutionJSONError(t *testing.T) {
 	require.Error(t, oerr)
 	require.Nil(t, obj)
 }
+
+func TestListInputFilesForExecution(t *testing.T) {
+	jsonfile, err := responses.GetTestData(responses.ExecutionInputFilesResponseTestFile)
+	require.NoError(t, err)
+	client, server, cErr := newTestRundeckClient(jsonfile, "application/json", 200)
+	defer server.Close()
+	require.NoError(t, cErr)
+	obj, oErr := client.ListInputFilesForExecution(1)
+	require.NoError(t, oErr)
+	require.NotEmpty(t, obj.Files)
+}
+
+func TestListInputFilesForExecutionJSONError(t *testing.T) {
+	client, server, cErr := newTestRundeckClient([]byte(""), "application/json", 200)
+	defer server.Close()
+	require.NoError(t, cErr)
+	obj, oErr := client.ListInputFilesForExecution(1)
+	require.Error(t, oErr)
+	require.IsType(t, &UnmarshalError{}, oErr)
+	require.Nil(t, obj)
+}
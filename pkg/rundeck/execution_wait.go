@@ -0,0 +1,210 @@
+package rundeck
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+
+	multierror "github.com/hashicorp/go-multierror"
+)
+
+// DefaultWaitInterval is the time between polls when waiting for an execution to finish
+const DefaultWaitInterval = 2 * time.Second
+
+// WaitOptions controls how the *AndWait helpers wait for an execution
+// A nil *WaitOptions waits with the defaults
+type WaitOptions struct {
+	// PollInterval is the time between polls of the execution. Defaults to DefaultWaitInterval
+	PollInterval time.Duration
+	// Timeout is the longest to wait for the execution to finish. Zero waits until the context is done
+	// The execution keeps running on the server after a timeout
+	Timeout time.Duration
+	// CollectOutput gathers the execution's output while waiting
+	CollectOutput bool
+}
+
+// FinishedExecution is an execution that ran to completion
+type FinishedExecution struct {
+	Execution
+	// Output is the output of the execution. Only set when WaitOptions.CollectOutput is true
+	Output []*ExecutionLogEntry
+}
+
+// WaitForExecution waits for an execution to finish
+// An *ExecutionError is returned along with the execution if it didn't succeed
+// If the wait times out or is cancelled the last known state of the execution is returned with the context's error
+// http://rundeck.org/docs/api/index.html#execution-info
+func (c *Client) WaitForExecution(executionID int, wait *WaitOptions) (*FinishedExecution, error) {
+	return c.WaitForExecutionContext(context.Background(), executionID, wait)
+}
+
+// WaitForExecutionContext waits for an execution to finish
+// An *ExecutionError is returned along with the execution if it didn't succeed
+func (c *Client) WaitForExecutionContext(ctx context.Context, executionID int, wait *WaitOptions) (*FinishedExecution, error) {
+	started := &Execution{}
+	started.ID = executionID
+	return c.waitForExecution(ctx, started, wait)
+}
+
+// waitForExecution waits for the started execution to finish
+// started is returned as the last known state if the wait ends before the execution is polled
+func (c *Client) waitForExecution(ctx context.Context, started *Execution, wait *WaitOptions) (*FinishedExecution, error) {
+	executionID := started.ID
+	if wait == nil {
+		wait = &WaitOptions{}
+	}
+	interval := wait.PollInterval
+	if interval < 0 {
+		return nil, &OptionError{msg: multierror.Append(errOption, errors.New("poll interval cannot be negative")).Error()}
+	}
+	if interval == 0 {
+		interval = DefaultWaitInterval
+	}
+	if wait.Timeout < 0 {
+		return nil, &OptionError{msg: multierror.Append(errOption, errors.New("timeout cannot be negative")).Error()}
+	}
+	if wait.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, wait.Timeout)
+		defer cancel()
+	}
+	finished := &FinishedExecution{Execution: *started}
+	if wait.CollectOutput {
+		output, err := c.collectExecutionOutput(ctx, executionID, interval)
+		if err != nil {
+			if ctx.Err() != nil {
+				return finished, ctx.Err()
+			}
+			return nil, err
+		}
+		finished.Output = output
+	}
+	for {
+		exec, err := c.GetExecutionInfoContext(ctx, executionID)
+		if err != nil {
+			if ctx.Err() != nil {
+				return finished, ctx.Err()
+			}
+			return nil, err
+		}
+		finished.Execution = *exec
+		if executionFinished(exec) {
+			return finished, finished.statusError()
+		}
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return finished, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// collectExecutionOutput follows the output of an execution until it has all been read
+func (c *Client) collectExecutionOutput(ctx context.Context, executionID int, interval time.Duration) ([]*ExecutionLogEntry, error) {
+	min := DefaultFollowMinInterval
+	if interval < min {
+		min = interval
+	}
+	f := c.FollowExecutionOutput(ctx, executionID, FollowPollInterval(min, interval))
+	output := []*ExecutionLogEntry{}
+	for entry := range f.Entries() {
+		output = append(output, entry)
+	}
+	if _, err := f.Wait(); err != nil {
+		return nil, err
+	}
+	return output, nil
+}
+
+// started returns the execution that was started with the little rundeck reports about it
+func (a *AdHocExecution) started() *Execution {
+	exec := &Execution{}
+	exec.ID = a.Execution.ID
+	exec.HRef = a.Execution.HRef
+	exec.Permalink = a.Execution.Permalink
+	return exec
+}
+
+// executionFinished reports if an execution has stopped running
+func executionFinished(exec *Execution) bool {
+	switch ExecutionStatus(exec.Status) {
+	case ExecutionStatusRunning, ExecutionStatusScheduled, "queued":
+		return false
+	default:
+		return true
+	}
+}
+
+func (f *FinishedExecution) statusError() error {
+	if ExecutionStatus(f.Status) == ExecutionStatusSucceeded {
+		return nil
+	}
+	return &ExecutionError{Status: ExecutionStatus(f.Status), Execution: &f.Execution}
+}
+
+// RunJobAndWait runs a job and waits for the execution to finish
+// An *ExecutionError is returned along with the execution if it didn't succeed
+// http://rundeck.org/docs/api/index.html#running-a-job
+func (c *Client) RunJobAndWait(id string, wait *WaitOptions, opts ...RunJobOption) (*FinishedExecution, error) {
+	return c.RunJobAndWaitContext(context.Background(), id, wait, opts...)
+}
+
+// RunJobAndWaitContext runs a job and waits for the execution to finish
+func (c *Client) RunJobAndWaitContext(ctx context.Context, id string, wait *WaitOptions, opts ...RunJobOption) (*FinishedExecution, error) {
+	exec, err := c.RunJobContext(ctx, id, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return c.waitForExecution(ctx, exec, wait)
+}
+
+// RunAdHocCommandAndWait runs an adhoc command and waits for the execution to finish
+// An *ExecutionError is returned along with the execution if it didn't succeed
+// http://rundeck.org/docs/api/index.html#running-adhoc-commands
+func (c *Client) RunAdHocCommandAndWait(projectID string, exec string, wait *WaitOptions, opts ...AdHocRunOption) (*FinishedExecution, error) {
+	return c.RunAdHocCommandAndWaitContext(context.Background(), projectID, exec, wait, opts...)
+}
+
+// RunAdHocCommandAndWaitContext runs an adhoc command and waits for the execution to finish
+func (c *Client) RunAdHocCommandAndWaitContext(ctx context.Context, projectID string, exec string, wait *WaitOptions, opts ...AdHocRunOption) (*FinishedExecution, error) {
+	data, err := c.RunAdHocCommandContext(ctx, projectID, exec, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return c.waitForExecution(ctx, data.started(), wait)
+}
+
+// RunAdHocScriptAndWait runs an adhoc script and waits for the execution to finish
+// An *ExecutionError is returned along with the execution if it didn't succeed
+// http://rundeck.org/docs/api/index.html#running-adhoc-scripts
+func (c *Client) RunAdHocScriptAndWait(projectID string, scriptData io.Reader, wait *WaitOptions, opts ...AdHocScriptOption) (*FinishedExecution, error) {
+	return c.RunAdHocScriptAndWaitContext(context.Background(), projectID, scriptData, wait, opts...)
+}
+
+// RunAdHocScriptAndWaitContext runs an adhoc script and waits for the execution to finish
+func (c *Client) RunAdHocScriptAndWaitContext(ctx context.Context, projectID string, scriptData io.Reader, wait *WaitOptions, opts ...AdHocScriptOption) (*FinishedExecution, error) {
+	data, err := c.RunAdHocScriptContext(ctx, projectID, scriptData, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return c.waitForExecution(ctx, data.started(), wait)
+}
+
+// RunAdHocScriptFromURLAndWait runs an adhoc script from a url and waits for the execution to finish
+// An *ExecutionError is returned along with the execution if it didn't succeed
+// http://rundeck.org/docs/api/index.html#running-adhoc-script-urls
+func (c *Client) RunAdHocScriptFromURLAndWait(projectID, scriptURL string, wait *WaitOptions, opts ...AdHocScriptURLOption) (*FinishedExecution, error) {
+	return c.RunAdHocScriptFromURLAndWaitContext(context.Background(), projectID, scriptURL, wait, opts...)
+}
+
+// RunAdHocScriptFromURLAndWaitContext runs an adhoc script from a url and waits for the execution to finish
+func (c *Client) RunAdHocScriptFromURLAndWaitContext(ctx context.Context, projectID, scriptURL string, wait *WaitOptions, opts ...AdHocScriptURLOption) (*FinishedExecution, error) {
+	data, err := c.RunAdHocScriptFromURLContext(ctx, projectID, scriptURL, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return c.waitForExecution(ctx, data.started(), wait)
+}
@@ -0,0 +1,144 @@
+package rundeck
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// testWaitHandler starts a job as execution 1 and reports each status in order on every poll of the execution
+func testWaitHandler(t *testing.T, statuses ...string) http.HandlerFunc {
+	polls := 0
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/job/abc/run"):
+			require.Equal(t, http.MethodPost, r.Method)
+			_, _ = w.Write([]byte(`{"id":1,"status":"running"}`))
+		case strings.HasSuffix(r.URL.Path, "/project/testproject/run/command"):
+			_, _ = w.Write([]byte(`{"message":"started","execution":{"id":1}}`))
+		case strings.HasSuffix(r.URL.Path, "/execution/1/output"):
+			_, _ = w.Write([]byte(testOutputPage(10, true, statuses[len(statuses)-1], "hello")))
+		case strings.HasSuffix(r.URL.Path, "/execution/1"):
+			status := statuses[len(statuses)-1]
+			if polls < len(statuses) {
+				status = statuses[polls]
+			}
+			polls++
+			_, _ = w.Write([]byte(fmt.Sprintf(`{"id":1,"status":%q}`, status)))
+		default:
+			t.Fatalf("unexpected request %s", r.URL.Path)
+		}
+	}
+}
+
+func TestRunJobAndWait(t *testing.T) {
+	client, server, cErr := newTestRundeckClientWithHandler(testWaitHandler(t, "running", "running", "succeeded"))
+	defer server.Close()
+	require.NoError(t, cErr)
+	exec, err := client.RunJobAndWait("abc", &WaitOptions{PollInterval: time.Millisecond})
+	require.NoError(t, err)
+	require.Equal(t, 1, exec.ID)
+	require.Equal(t, "succeeded", exec.Status)
+	require.Nil(t, exec.Output)
+}
+
+func TestRunJobAndWaitFailedStatuses(t *testing.T) {
+	tests := map[string]error{
+		"failed":            ErrExecutionFailed,
+		"failed-with-retry": ErrExecutionFailed,
+		"aborted":           ErrExecutionAborted,
+		"timedout":          ErrExecutionTimedOut,
+	}
+	for status, sentinel := range tests {
+		t.Run(status, func(t *testing.T) {
+			client, server, cErr := newTestRundeckClientWithHandler(testWaitHandler(t, "running", status))
+			defer server.Close()
+			require.NoError(t, cErr)
+			exec, err := client.RunJobAndWait("abc", &WaitOptions{PollInterval: time.Millisecond})
+			require.Error(t, err)
+			require.True(t, errors.Is(err, sentinel))
+			var execErr *ExecutionError
+			require.True(t, errors.As(err, &execErr))
+			require.Equal(t, ExecutionStatus(status), execErr.Status)
+			require.NotNil(t, exec)
+			require.Equal(t, status, exec.Status)
+		})
+	}
+}
+
+func TestRunJobAndWaitCustomStatus(t *testing.T) {
+	client, server, cErr := newTestRundeckClientWithHandler(testWaitHandler(t, "other"))
+	defer server.Close()
+	require.NoError(t, cErr)
+	_, err := client.RunJobAndWait("abc", nil)
+	var execErr *ExecutionError
+	require.True(t, errors.As(err, &execErr))
+	require.False(t, errors.Is(err, ErrExecutionFailed))
+	require.Equal(t, ExecutionStatusOther, execErr.Status)
+}
+
+func TestRunJobAndWaitTimeout(t *testing.T) {
+	client, server, cErr := newTestRundeckClientWithHandler(testWaitHandler(t, "running"))
+	defer server.Close()
+	require.NoError(t, cErr)
+	exec, err := client.RunJobAndWait("abc", &WaitOptions{PollInterval: time.Millisecond, Timeout: 20 * time.Millisecond})
+	require.Equal(t, context.DeadlineExceeded, err)
+	require.NotNil(t, exec)
+	require.Equal(t, 1, exec.ID)
+	require.Equal(t, "running", exec.Status)
+}
+
+func TestRunJobAndWaitContextCancelled(t *testing.T) {
+	client, server, cErr := newTestRundeckClientWithHandler(testWaitHandler(t, "running"))
+	defer server.Close()
+	require.NoError(t, cErr)
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+	exec, err := client.RunJobAndWaitContext(ctx, "abc", &WaitOptions{PollInterval: time.Millisecond})
+	require.Equal(t, context.Canceled, err)
+	require.Equal(t, 1, exec.ID)
+}
+
+func TestRunAdHocCommandAndWaitCancelledBeforePoll(t *testing.T) {
+	client, server, cErr := newTestRundeckClientWithHandler(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if strings.HasSuffix(r.URL.Path, "/run/command") {
+			_, _ = w.Write([]byte(`{"message":"started","execution":{"id":7,"permalink":"http://rundeck/execution/show/7"}}`))
+			return
+		}
+		<-r.Context().Done()
+	})
+	defer server.Close()
+	require.NoError(t, cErr)
+	exec, err := client.RunAdHocCommandAndWait("testproject", "uptime", &WaitOptions{Timeout: 20 * time.Millisecond})
+	require.Equal(t, context.DeadlineExceeded, err)
+	require.Equal(t, 7, exec.ID)
+	require.Equal(t, "http://rundeck/execution/show/7", exec.Permalink)
+}
+
+func TestRunJobAndWaitInvalidOptions(t *testing.T) {
+	client, server, cErr := newTestRundeckClientWithHandler(testWaitHandler(t, "succeeded"))
+	defer server.Close()
+	require.NoError(t, cErr)
+	_, err := client.RunJobAndWait("abc", &WaitOptions{Timeout: -time.Second})
+	require.Error(t, err)
+	_, ok := err.(*OptionError)
+	require.True(t, ok)
+}
+
+func TestRunAdHocCommandAndWaitCollectOutput(t *testing.T) {
+	client, server, cErr := newTestRundeckClientWithHandler(testWaitHandler(t, "failed"))
+	defer server.Close()
+	require.NoError(t, cErr)
+	exec, err := client.RunAdHocCommandAndWait("testproject", "uptime", &WaitOptions{PollInterval: time.Millisecond, CollectOutput: true})
+	require.True(t, errors.Is(err, ErrExecutionFailed))
+	require.Len(t, exec.Output, 1)
+	require.Equal(t, "hello", exec.Output[0].Log)
+}
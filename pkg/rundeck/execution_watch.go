@@ -0,0 +1,290 @@
+package rundeck
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	multierror "github.com/hashicorp/go-multierror"
+	responses "github.com/lusis/go-rundeck/pkg/rundeck/responses"
+)
+
+// DefaultWatchInterval is the time between polls when watching execution state
+const DefaultWatchInterval = 2 * time.Second
+
+// ExecutionEventType is the kind of change seen while watching an execution
+type ExecutionEventType string
+
+const (
+	// ExecutionEventStateChanged is sent when the overall state of the execution changes
+	ExecutionEventStateChanged ExecutionEventType = "execution-state-changed"
+	// ExecutionEventStepStarted is sent when a step starts running on a node
+	ExecutionEventStepStarted ExecutionEventType = "step-started"
+	// ExecutionEventStepSucceeded is sent when a step succeeds on a node
+	ExecutionEventStepSucceeded ExecutionEventType = "step-succeeded"
+	// ExecutionEventStepFailed is sent when a step fails on a node
+	ExecutionEventStepFailed ExecutionEventType = "step-failed"
+	// ExecutionEventStepStateChanged is sent for any other change of a step's state on a node
+	ExecutionEventStepStateChanged ExecutionEventType = "step-state-changed"
+	// ExecutionEventNodeFailed is sent the first time a step fails on a node
+	ExecutionEventNodeFailed ExecutionEventType = "node-failed"
+	// ExecutionEventCompleted is sent once when the execution completes
+	ExecutionEventCompleted ExecutionEventType = "execution-completed"
+)
+
+// rundeck execution states as reported by the execution state api
+const (
+	stateRunning   = "RUNNING"
+	stateSucceeded = "SUCCEEDED"
+	stateFailed    = "FAILED"
+)
+
+// ExecutionEvent is a single change between two snapshots of an execution's state
+type ExecutionEvent struct {
+	Type        ExecutionEventType
+	ExecutionID int
+	// Node is the node the event applies to. Empty for execution level events
+	Node string
+	// StepCTX is the step context the event applies to (i.e. 2 or 2/1). Empty for execution and node level events
+	StepCTX string
+	// State is the new state
+	State string
+	// PreviousState is the state before the change. Empty if it wasn't known
+	PreviousState string
+	// Time is the update time reported by rundeck for the snapshot the change was seen in
+	Time time.Time
+}
+
+// String describes the event
+func (e *ExecutionEvent) String() string {
+	switch e.Type {
+	case ExecutionEventStepStarted:
+		return fmt.Sprintf("step %s started on node %s", e.StepCTX, e.Node)
+	case ExecutionEventStepSucceeded:
+		return fmt.Sprintf("step %s succeeded on node %s", e.StepCTX, e.Node)
+	case ExecutionEventStepFailed:
+		return fmt.Sprintf("step %s failed on node %s", e.StepCTX, e.Node)
+	case ExecutionEventStepStateChanged:
+		return fmt.Sprintf("step %s is %s on node %s", e.StepCTX, strings.ToLower(e.State), e.Node)
+	case ExecutionEventNodeFailed:
+		return fmt.Sprintf("node %s failed", e.Node)
+	case ExecutionEventCompleted:
+		return fmt.Sprintf("execution %d completed: %s", e.ExecutionID, strings.ToLower(e.State))
+	default:
+		return fmt.Sprintf("execution %d is %s", e.ExecutionID, strings.ToLower(e.State))
+	}
+}
+
+// WatchOption is a functional option for watching execution state
+type WatchOption func(w *ExecutionStateWatcher) error
+
+// WatchPollInterval sets the time between polls of the execution state
+func WatchPollInterval(d time.Duration) WatchOption {
+	return func(w *ExecutionStateWatcher) error {
+		if d <= 0 {
+			return errors.New("poll interval must be positive")
+		}
+		w.interval = d
+		return nil
+	}
+}
+
+// WatchCallback delivers events to f instead of the Events channel
+// f is called from the watcher's goroutine and polling waits for it to return
+func WatchCallback(f func(*ExecutionEvent)) WatchOption {
+	return func(w *ExecutionStateWatcher) error {
+		if f == nil {
+			return errors.New("callback cannot be nil")
+		}
+		w.callback = f
+		return nil
+	}
+}
+
+// ExecutionStateWatcher polls the state of an execution and reports what changed between polls
+type ExecutionStateWatcher struct {
+	client      *Client
+	executionID int
+	interval    time.Duration
+	callback    func(*ExecutionEvent)
+	events      chan *ExecutionEvent
+	done        chan struct{}
+	state       *ExecutionState
+	err         error
+	sync.Mutex
+}
+
+// WatchExecutionState polls the state of an execution until it completes, sending an event for every change
+// Watching stops early if ctx is cancelled
+// http://rundeck.org/docs/api/index.html#execution-state
+func (c *Client) WatchExecutionState(ctx context.Context, executionID int, opts ...WatchOption) *ExecutionStateWatcher {
+	w := &ExecutionStateWatcher{
+		client:      c,
+		executionID: executionID,
+		interval:    DefaultWatchInterval,
+		events:      make(chan *ExecutionEvent),
+		done:        make(chan struct{}),
+	}
+	for _, opt := range opts {
+		if err := opt(w); err != nil {
+			w.finish(nil, &OptionError{msg: multierror.Append(errOption, err).Error()})
+			return w
+		}
+	}
+	go w.watch(ctx)
+	return w
+}
+
+func (w *ExecutionStateWatcher) watch(ctx context.Context) {
+	var prev *responses.ExecutionStateResponse
+	for {
+		state, err := w.client.GetExecutionStateContext(ctx, w.executionID)
+		if err != nil {
+			if ctx.Err() != nil {
+				err = ctx.Err()
+			}
+			w.finish(nil, err)
+			return
+		}
+		for _, event := range diffExecutionStates(prev, &state.ExecutionStateResponse) {
+			if !w.send(ctx, event) {
+				w.finish(state, ctx.Err())
+				return
+			}
+		}
+		if state.Completed {
+			w.finish(state, nil)
+			return
+		}
+		prev = &state.ExecutionStateResponse
+		timer := time.NewTimer(w.interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			w.finish(state, ctx.Err())
+			return
+		case <-timer.C:
+		}
+	}
+}
+
+// send delivers an event returning false if ctx was cancelled first
+func (w *ExecutionStateWatcher) send(ctx context.Context, event *ExecutionEvent) bool {
+	if w.callback != nil {
+		w.callback(event)
+		return ctx.Err() == nil
+	}
+	select {
+	case w.events <- event:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+func (w *ExecutionStateWatcher) finish(state *ExecutionState, err error) {
+	w.Lock()
+	w.state = state
+	w.err = err
+	w.Unlock()
+	close(w.events)
+	close(w.done)
+}
+
+// Events returns the channel events are sent on
+// The channel is closed when watching stops. Nothing is sent on it when WatchCallback is used
+func (w *ExecutionStateWatcher) Events() <-chan *ExecutionEvent {
+	return w.events
+}
+
+// Done returns a channel that is closed when watching stops
+func (w *ExecutionStateWatcher) Done() <-chan struct{} {
+	return w.done
+}
+
+// Wait blocks until watching stops and returns the last state seen
+// Events must be drained (or ctx cancelled) for watching to stop
+func (w *ExecutionStateWatcher) Wait() (*ExecutionState, error) {
+	<-w.done
+	w.Lock()
+	defer w.Unlock()
+	return w.state, w.err
+}
+
+// Err returns the error that stopped watching, if any
+func (w *ExecutionStateWatcher) Err() error {
+	w.Lock()
+	defer w.Unlock()
+	return w.err
+}
+
+// diffExecutionStates returns the events that turn prev into cur
+// a nil prev is treated as an execution where nothing has happened yet
+func diffExecutionStates(prev, cur *responses.ExecutionStateResponse) []*ExecutionEvent {
+	if prev == nil {
+		prev = &responses.ExecutionStateResponse{}
+	}
+	var updated time.Time
+	if cur.UpdateTime != nil {
+		updated = cur.UpdateTime.Time
+	}
+	newEvent := func(t ExecutionEventType, node, step, state, previous string) *ExecutionEvent {
+		return &ExecutionEvent{
+			Type:          t,
+			ExecutionID:   cur.ExecutionID,
+			Node:          node,
+			StepCTX:       step,
+			State:         state,
+			PreviousState: previous,
+			Time:          updated,
+		}
+	}
+	events := []*ExecutionEvent{}
+	if cur.ExecutionState != prev.ExecutionState && !cur.Completed {
+		events = append(events, newEvent(ExecutionEventStateChanged, "", "", cur.ExecutionState, prev.ExecutionState))
+	}
+
+	nodes := make([]string, 0, len(cur.Nodes))
+	for node := range cur.Nodes {
+		nodes = append(nodes, node)
+	}
+	sort.Strings(nodes)
+	for _, node := range nodes {
+		previous := map[string]string{}
+		previouslyFailed := false
+		for _, step := range prev.Nodes[node] {
+			previous[step.StepCtx] = step.ExecutionState
+			previouslyFailed = previouslyFailed || step.ExecutionState == stateFailed
+		}
+		nodeFailed := false
+		for _, step := range cur.Nodes[node] {
+			was := previous[step.StepCtx]
+			if step.ExecutionState == was {
+				continue
+			}
+			t := ExecutionEventStepStateChanged
+			switch step.ExecutionState {
+			case stateRunning:
+				t = ExecutionEventStepStarted
+			case stateSucceeded:
+				t = ExecutionEventStepSucceeded
+			case stateFailed:
+				t = ExecutionEventStepFailed
+				nodeFailed = true
+			}
+			events = append(events, newEvent(t, node, step.StepCtx, step.ExecutionState, was))
+		}
+		if nodeFailed && !previouslyFailed {
+			events = append(events, newEvent(ExecutionEventNodeFailed, node, "", stateFailed, ""))
+		}
+	}
+
+	if cur.Completed && !prev.Completed {
+		events = append(events, newEvent(ExecutionEventCompleted, "", "", cur.ExecutionState, prev.ExecutionState))
+	}
+	return events
+}
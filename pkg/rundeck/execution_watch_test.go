@@ -0,0 +1,137 @@
+package rundeck
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	responses "github.com/lusis/go-rundeck/pkg/rundeck/responses"
+	"github.com/stretchr/testify/require"
+)
+
+func testExecutionStateSnapshot(t *testing.T, completed bool, state string, nodes string) string {
+	snapshot := fmt.Sprintf(`{"executionId":1,"completed":%t,"executionState":%q,"updateTime":"2018-01-05T18:40:34Z","nodes":%s}`,
+		completed, state, nodes)
+	require.True(t, json.Valid([]byte(snapshot)))
+	return snapshot
+}
+
+func decodeTestSnapshot(t *testing.T, s string) *responses.ExecutionStateResponse {
+	r := &responses.ExecutionStateResponse{}
+	require.NoError(t, json.Unmarshal([]byte(s), r))
+	return r
+}
+
+func TestDiffExecutionStates(t *testing.T) {
+	first := decodeTestSnapshot(t, testExecutionStateSnapshot(t, false, "RUNNING",
+		`{"web01":[{"stepctx":"1","executionState":"RUNNING"}],"db03":[{"stepctx":"1","executionState":"WAITING"}]}`))
+	second := decodeTestSnapshot(t, testExecutionStateSnapshot(t, false, "RUNNING",
+		`{"web01":[{"stepctx":"1","executionState":"SUCCEEDED"},{"stepctx":"2","executionState":"RUNNING"}],"db03":[{"stepctx":"1","executionState":"FAILED"}]}`))
+	third := decodeTestSnapshot(t, testExecutionStateSnapshot(t, true, "FAILED",
+		`{"web01":[{"stepctx":"1","executionState":"SUCCEEDED"},{"stepctx":"2","executionState":"SUCCEEDED"}],"db03":[{"stepctx":"1","executionState":"FAILED"}]}`))
+
+	describe := func(events []*ExecutionEvent) []string {
+		s := []string{}
+		for _, e := range events {
+			s = append(s, e.String())
+		}
+		return s
+	}
+	require.Equal(t, []string{
+		"execution 1 is running",
+		"step 1 is waiting on node db03",
+		"step 1 started on node web01",
+	}, describe(diffExecutionStates(nil, first)))
+	require.Equal(t, []string{
+		"step 1 failed on node db03",
+		"node db03 failed",
+		"step 1 succeeded on node web01",
+		"step 2 started on node web01",
+	}, describe(diffExecutionStates(first, second)))
+	events := diffExecutionStates(second, third)
+	require.Equal(t, []string{
+		"step 2 succeeded on node web01",
+		"execution 1 completed: failed",
+	}, describe(events))
+	require.Equal(t, "RUNNING", events[0].PreviousState)
+	require.Equal(t, ExecutionEventCompleted, events[1].Type)
+	require.Empty(t, diffExecutionStates(third, third))
+}
+
+func testWatchHandler(t *testing.T, snapshots []string) http.HandlerFunc {
+	polls := 0
+	return func(w http.ResponseWriter, r *http.Request) {
+		s := snapshots[len(snapshots)-1]
+		if polls < len(snapshots) {
+			s = snapshots[polls]
+		}
+		polls++
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(s))
+	}
+}
+
+func TestWatchExecutionState(t *testing.T) {
+	client, server, cErr := newTestRundeckClientWithHandler(testWatchHandler(t, []string{
+		testExecutionStateSnapshot(t, false, "RUNNING", `{"web01":[{"stepctx":"1","executionState":"RUNNING"}]}`),
+		testExecutionStateSnapshot(t, false, "RUNNING", `{"web01":[{"stepctx":"1","executionState":"RUNNING"}]}`),
+		testExecutionStateSnapshot(t, true, "SUCCEEDED", `{"web01":[{"stepctx":"1","executionState":"SUCCEEDED"}]}`),
+	}))
+	defer server.Close()
+	require.NoError(t, cErr)
+	w := client.WatchExecutionState(context.Background(), 1, WatchPollInterval(time.Millisecond))
+	types := []ExecutionEventType{}
+	for e := range w.Events() {
+		types = append(types, e.Type)
+	}
+	state, err := w.Wait()
+	require.NoError(t, err)
+	require.True(t, state.Completed)
+	require.Equal(t, []ExecutionEventType{
+		ExecutionEventStateChanged,
+		ExecutionEventStepStarted,
+		ExecutionEventStepSucceeded,
+		ExecutionEventCompleted,
+	}, types)
+}
+
+func TestWatchExecutionStateCallback(t *testing.T) {
+	client, server, cErr := newTestRundeckClientWithHandler(testWatchHandler(t, []string{
+		testExecutionStateSnapshot(t, true, "SUCCEEDED", `{"web01":[{"stepctx":"1","executionState":"SUCCEEDED"}]}`),
+	}))
+	defer server.Close()
+	require.NoError(t, cErr)
+	events := []string{}
+	w := client.WatchExecutionState(context.Background(), 1, WatchCallback(func(e *ExecutionEvent) {
+		events = append(events, e.String())
+	}))
+	_, err := w.Wait()
+	require.NoError(t, err)
+	require.Equal(t, []string{"step 1 succeeded on node web01", "execution 1 completed: succeeded"}, events)
+}
+
+func TestWatchExecutionStateCancel(t *testing.T) {
+	client, server, cErr := newTestRundeckClientWithHandler(testWatchHandler(t, []string{
+		testExecutionStateSnapshot(t, false, "RUNNING", `{}`),
+	}))
+	defer server.Close()
+	require.NoError(t, cErr)
+	ctx, cancel := context.WithCancel(context.Background())
+	w := client.WatchExecutionState(ctx, 1, WatchPollInterval(time.Hour))
+	<-w.Events()
+	cancel()
+	_, err := w.Wait()
+	require.Equal(t, context.Canceled, err)
+}
+
+func TestWatchExecutionStateHTTPError(t *testing.T) {
+	client, server, cErr := newTestRundeckClient([]byte(""), "application/json", 500)
+	defer server.Close()
+	require.NoError(t, cErr)
+	w := client.WatchExecutionState(context.Background(), 1)
+	_, err := w.Wait()
+	require.Error(t, err)
+}
@@ -2,7 +2,9 @@ package rundeck
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"net/url"
 	"strconv"
 	"strings"
 
@@ -21,6 +23,11 @@ type DeletedExecutions struct {
 	responses.BulkDeleteExecutionsResponse
 }
 
+// ExecutionMetrics represents aggregate metrics for executions
+type ExecutionMetrics struct {
+	responses.ExecutionsMetricsResponse
+}
+
 // BulkToggleResponse represents the results of a bulk toggle request
 type BulkToggleResponse struct {
 	responses.BulkToggleResponse
@@ -29,13 +36,22 @@ type BulkToggleResponse struct {
 // ListProjectExecutions lists a projects executions
 // http://rundeck.org/docs/api/index.html#execution-query
 func (c *Client) ListProjectExecutions(projectID string, options map[string]string) (*Executions, error) {
+	return c.ListProjectExecutionsContext(context.Background(), projectID, options)
+}
+
+// ListProjectExecutionsContext lists a projects executions
+func (c *Client) ListProjectExecutionsContext(ctx context.Context, projectID string, options map[string]string) (*Executions, error) {
+	return c.listProjectExecutions(ctx, projectID, mapToValues(options))
+}
+
+func (c *Client) listProjectExecutions(ctx context.Context, projectID string, options url.Values) (*Executions, error) {
 	if err := c.checkRequiredAPIVersion(responses.ListRunningExecutionsResponse{}); err != nil {
 		return nil, err
 	}
 	data := &Executions{}
-	res, err := c.httpGet("project/"+projectID+"/executions",
+	res, err := c.httpGet(ctx, "project/"+projectID+"/executions",
 		requestJSON(),
-		queryParams(options),
+		queryValues(options),
 		requestExpects(200))
 	if err != nil {
 		return nil, err
@@ -46,15 +62,37 @@ func (c *Client) ListProjectExecutions(projectID string, options map[string]stri
 	return data, nil
 }
 
+// IterateProjectExecutions returns an iterator over all of a project's executions matching the options
+// Pages are fetched from the server as the iterator is advanced
+// http://rundeck.org/docs/api/index.html#execution-query
+func (c *Client) IterateProjectExecutions(projectID string, options map[string]string, opts ...PagingOption) *ExecutionIterator {
+	return c.IterateProjectExecutionsContext(context.Background(), projectID, options, opts...)
+}
+
+// IterateProjectExecutionsContext returns an iterator over all of a project's executions matching the options
+func (c *Client) IterateProjectExecutionsContext(ctx context.Context, projectID string, options map[string]string, opts ...PagingOption) *ExecutionIterator {
+	return &ExecutionIterator{
+		pager: newPager(ctx, mapToValues(options), opts...),
+		fetch: func(ctx context.Context, params url.Values) (*Executions, error) {
+			return c.listProjectExecutions(ctx, projectID, params)
+		},
+	}
+}
+
 // ListRunningExecutions lists running executions
 // http://rundeck.org/docs/api/index.html#listing-running-executions
 func (c *Client) ListRunningExecutions(projectID string) (*Executions, error) {
+	return c.ListRunningExecutionsContext(context.Background(), projectID)
+}
+
+// ListRunningExecutionsContext lists running executions
+func (c *Client) ListRunningExecutionsContext(ctx context.Context, projectID string) (*Executions, error) {
 	if err := c.checkRequiredAPIVersion(responses.ListRunningExecutionsResponse{}); err != nil {
 		return nil, err
 	}
 	options := make(map[string]string)
 	data := &Executions{}
-	res, err := c.httpGet("project/"+projectID+"/executions/running",
+	res, err := c.httpGet(ctx, "project/"+projectID+"/executions/running",
 		requestJSON(),
 		queryParams(options),
 		requestExpects(200))
@@ -70,6 +108,11 @@ func (c *Client) ListRunningExecutions(projectID string) (*Executions, error) {
 // BulkDeleteExecutions deletes a list of executions by id
 // http://rundeck.org/docs/api/index.html#bulk-delete-executions
 func (c *Client) BulkDeleteExecutions(ids ...int) (*DeletedExecutions, error) {
+	return c.BulkDeleteExecutionsContext(context.Background(), ids...)
+}
+
+// BulkDeleteExecutionsContext deletes a list of executions by id
+func (c *Client) BulkDeleteExecutionsContext(ctx context.Context, ids ...int) (*DeletedExecutions, error) {
 	if err := c.checkRequiredAPIVersion(responses.BulkDeleteExecutionsResponse{}); err != nil {
 		return nil, err
 	}
@@ -82,7 +125,7 @@ func (c *Client) BulkDeleteExecutions(ids ...int) (*DeletedExecutions, error) {
 	}
 	opts["ids"] = strings.Join(toDelete, ",")
 
-	res, err := c.httpPost("executions/delete",
+	res, err := c.httpPost(ctx, "executions/delete",
 		accept("application/json"),
 		queryParams(opts),
 		requestExpects(200))
@@ -98,6 +141,11 @@ func (c *Client) BulkDeleteExecutions(ids ...int) (*DeletedExecutions, error) {
 // BulkEnableExecution enables job execution in bulk
 // http://rundeck.org/docs/api/index.html#bulk-toggle-job-execution
 func (c *Client) BulkEnableExecution(ids ...string) (*BulkToggleResponse, error) {
+	return c.BulkEnableExecutionContext(context.Background(), ids...)
+}
+
+// BulkEnableExecutionContext enables job execution in bulk
+func (c *Client) BulkEnableExecutionContext(ctx context.Context, ids ...string) (*BulkToggleResponse, error) {
 	if err := c.checkRequiredAPIVersion(responses.BulkToggleResponse{}); err != nil {
 		return nil, err
 	}
@@ -106,7 +154,7 @@ func (c *Client) BulkEnableExecution(ids ...string) (*BulkToggleResponse, error)
 	}
 	results := &BulkToggleResponse{}
 	data, _ := json.Marshal(req)
-	res, err := c.httpPost("jobs/execution/enable",
+	res, err := c.httpPost(ctx, "jobs/execution/enable",
 		withBody(bytes.NewReader(data)),
 		requestJSON(),
 		requestExpects(200))
@@ -122,6 +170,11 @@ func (c *Client) BulkEnableExecution(ids ...string) (*BulkToggleResponse, error)
 // BulkDisableExecution disables job execution in bulk
 // http://rundeck.org/docs/api/index.html#bulk-toggle-job-execution
 func (c *Client) BulkDisableExecution(ids ...string) (*BulkToggleResponse, error) {
+	return c.BulkDisableExecutionContext(context.Background(), ids...)
+}
+
+// BulkDisableExecutionContext disables job execution in bulk
+func (c *Client) BulkDisableExecutionContext(ctx context.Context, ids ...string) (*BulkToggleResponse, error) {
 	if err := c.checkRequiredAPIVersion(responses.BulkToggleResponse{}); err != nil {
 		return nil, err
 	}
@@ -130,7 +183,7 @@ func (c *Client) BulkDisableExecution(ids ...string) (*BulkToggleResponse, error
 	}
 	results := &BulkToggleResponse{}
 	data, _ := json.Marshal(req)
-	res, err := c.httpPost("jobs/execution/disable",
+	res, err := c.httpPost(ctx, "jobs/execution/disable",
 		withBody(bytes.NewReader(data)),
 		requestJSON(),
 		requestExpects(200))
@@ -142,3 +195,47 @@ func (c *Client) BulkDisableExecution(ids ...string) (*BulkToggleResponse, error
 	}
 	return results, nil
 }
+
+// GetExecutionMetrics gets metrics for all executions matching the query across all projects
+// http://rundeck.org/docs/api/index.html#execution-query-metrics
+func (c *Client) GetExecutionMetrics(q *ExecutionQuery) (*ExecutionMetrics, error) {
+	return c.GetExecutionMetricsContext(context.Background(), q)
+}
+
+// GetExecutionMetricsContext gets metrics for all executions matching the query across all projects
+func (c *Client) GetExecutionMetricsContext(ctx context.Context, q *ExecutionQuery) (*ExecutionMetrics, error) {
+	if err := c.checkRequiredAPIVersion(responses.ExecutionsMetricsResponse{}); err != nil {
+		return nil, err
+	}
+	return c.getExecutionMetrics(ctx, "executions/metrics", q)
+}
+
+// GetProjectExecutionMetrics gets metrics for a project's executions matching the query
+// http://rundeck.org/docs/api/index.html#execution-query-metrics
+func (c *Client) GetProjectExecutionMetrics(projectID string, q *ExecutionQuery) (*ExecutionMetrics, error) {
+	return c.GetProjectExecutionMetricsContext(context.Background(), projectID, q)
+}
+
+// GetProjectExecutionMetricsContext gets metrics for a project's executions matching the query
+func (c *Client) GetProjectExecutionMetricsContext(ctx context.Context, projectID string, q *ExecutionQuery) (*ExecutionMetrics, error) {
+	if err := c.checkRequiredAPIVersion(responses.ProjectExecutionsMetricsResponse{}); err != nil {
+		return nil, err
+	}
+	return c.getExecutionMetrics(ctx, "project/"+projectID+"/executions/metrics", q)
+}
+
+func (c *Client) getExecutionMetrics(ctx context.Context, path string, q *ExecutionQuery) (*ExecutionMetrics, error) {
+	params, err := q.Values()
+	if err != nil {
+		return nil, &OptionError{msg: multierror.Append(errOption, err).Error()}
+	}
+	res, err := c.httpGet(ctx, path, requestJSON(), queryValues(params), requestExpects(200))
+	if err != nil {
+		return nil, err
+	}
+	data := &ExecutionMetrics{}
+	if jsonErr := json.Unmarshal(res, data); jsonErr != nil {
+		return nil, &UnmarshalError{msg: multierror.Append(errDecoding, jsonErr).Error()}
+	}
+	return data, nil
+}
@@ -1,7 +1,10 @@
 package rundeck
 
 import (
+	"net/http"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/lusis/go-rundeck/pkg/rundeck/responses"
 
@@ -185,3 +188,52 @@ func TestBulkDisableExecutionJSONError(t *testing.T) {
 	require.Error(t, cErr)
 	require.Nil(t, obj)
 }
+
+func TestGetExecutionMetrics(t *testing.T) {
+	jsonfile, err := responses.GetTestData(responses.ExecutionsMetricsResponseTestFile)
+	require.NoError(t, err)
+	client, server, cErr := newTestRundeckClientWithHandler(func(w http.ResponseWriter, r *http.Request) {
+		require.True(t, strings.HasSuffix(r.URL.Path, "/executions/metrics"))
+		require.Equal(t, "1w", r.URL.Query().Get("recentFilter"))
+		_, _ = w.Write(jsonfile)
+	})
+	defer server.Close()
+	require.NoError(t, cErr)
+	obj, oErr := client.GetExecutionMetrics(&ExecutionQuery{Recent: 7 * 24 * time.Hour})
+	require.NoError(t, oErr)
+	require.Equal(t, 2, obj.Total)
+	require.Equal(t, 2, obj.Status.Failed)
+	require.Equal(t, 10*time.Second, obj.Duration.Average.Duration)
+}
+
+func TestGetProjectExecutionMetrics(t *testing.T) {
+	jsonfile, err := responses.GetTestData(responses.ProjectExecutionsMetricsResponseTestFile)
+	require.NoError(t, err)
+	client, server, cErr := newTestRundeckClientWithHandler(func(w http.ResponseWriter, r *http.Request) {
+		require.True(t, strings.HasSuffix(r.URL.Path, "/project/testproject/executions/metrics"))
+		_, _ = w.Write(jsonfile)
+	})
+	defer server.Close()
+	require.NoError(t, cErr)
+	obj, oErr := client.GetProjectExecutionMetrics("testproject", nil)
+	require.NoError(t, oErr)
+	require.Equal(t, 1, obj.Total)
+}
+
+func TestGetExecutionMetricsInvalidQuery(t *testing.T) {
+	client, server, cErr := newTestRundeckClient([]byte(""), "application/json", 200)
+	defer server.Close()
+	require.NoError(t, cErr)
+	obj, oErr := client.GetExecutionMetrics(&ExecutionQuery{Status: "bogus"})
+	require.IsType(t, &OptionError{}, oErr)
+	require.Nil(t, obj)
+}
+
+func TestGetExecutionMetricsJSONError(t *testing.T) {
+	client, server, cErr := newTestRundeckClient([]byte(""), "application/json", 200)
+	defer server.Close()
+	require.NoError(t, cErr)
+	obj, oErr := client.GetProjectExecutionMetrics("testproject", nil)
+	require.IsType(t, &UnmarshalError{}, oErr)
+	require.Nil(t, obj)
+}
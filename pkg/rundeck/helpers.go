@@ -5,6 +5,7 @@ Functions in this package are not part of the standard rundeck API but are usefu
 
 */
 import (
+	"context"
 	"errors"
 	"fmt"
 	"time"
@@ -16,20 +17,25 @@ import (
 
 // FindJobByName runs a job by name
 func (c *Client) FindJobByName(name string) ([]JobMetaData, error) {
-	projects, pErr := c.ListProjects()
+	return c.FindJobByNameContext(context.Background(), name)
+}
+
+// FindJobByNameContext runs a job by name
+func (c *Client) FindJobByNameContext(ctx context.Context, name string) ([]JobMetaData, error) {
+	projects, pErr := c.ListProjectsContext(ctx)
 	if pErr != nil {
 		return nil, pErr
 	}
 
 	var results []JobMetaData
 	for _, project := range projects {
-		jobs, err := c.ListJobs(project.Name)
+		jobs, err := c.ListJobsContext(ctx, project.Name)
 		if err != nil {
 			return nil, err
 		}
 		for _, d := range jobs {
 			if d.Name == name {
-				job, joblistErr := c.GetJobInfo(d.ID)
+				job, joblistErr := c.GetJobInfoContext(ctx, d.ID)
 				if joblistErr != nil {
 					return nil, joblistErr
 				}
@@ -45,9 +51,14 @@ func (c *Client) FindJobByName(name string) ([]JobMetaData, error) {
 
 // GetJobOpts returns the required options for a job
 func (c *Client) GetJobOpts(j string) ([]*JobOption, error) {
+	return c.GetJobOptsContext(context.Background(), j)
+}
+
+// GetJobOptsContext returns the required options for a job
+func (c *Client) GetJobOptsContext(ctx context.Context, j string) ([]*JobOption, error) {
 	options := make([]*JobOption, 0)
 	data := &responses.JobYAMLResponse{}
-	res, err := c.httpGet("job/"+j, accept("application/yaml"), requestExpects(200))
+	res, err := c.httpGet(ctx, "job/"+j, accept("application/yaml"), requestExpects(200))
 	if err != nil {
 		return nil, err
 	}
@@ -72,9 +83,14 @@ func (c *Client) GetJobOpts(j string) ([]*JobOption, error) {
 
 // GetRequiredOpts returns the required options for a job
 func (c *Client) GetRequiredOpts(j string) (map[string]string, error) {
+	return c.GetRequiredOptsContext(context.Background(), j)
+}
+
+// GetRequiredOptsContext returns the required options for a job
+func (c *Client) GetRequiredOptsContext(ctx context.Context, j string) (map[string]string, error) {
 	u := make(map[string]string)
 	data := &responses.JobYAMLResponse{}
-	res, err := c.httpGet("job/"+j, accept("application/yaml"), requestExpects(200))
+	res, err := c.httpGet(ctx, "job/"+j, accept("application/yaml"), requestExpects(200))
 
 	if err != nil {
 		return nil, err
@@ -98,37 +114,75 @@ func (c *Client) GetRequiredOpts(j string) (map[string]string, error) {
 	return u, nil
 }
 
+// GetProjectForecast gets the upcoming scheduled executions for all scheduled jobs in a project
+// Jobs that are not scheduled or have their schedule disabled are skipped
+func (c *Client) GetProjectForecast(projectID string, within time.Duration, max int) ([]*JobForecast, error) {
+	return c.GetProjectForecastContext(context.Background(), projectID, within, max)
+}
+
+// GetProjectForecastContext gets the upcoming scheduled executions for all scheduled jobs in a project
+func (c *Client) GetProjectForecastContext(ctx context.Context, projectID string, within time.Duration, max int) ([]*JobForecast, error) {
+	jobs, err := c.ListJobsContext(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	results := []*JobForecast{}
+	for _, job := range jobs {
+		if !job.Scheduled || !job.ScheduleEnabled || !job.Enabled {
+			continue
+		}
+		forecast, forecastErr := c.GetJobForecastContext(ctx, job.ID, within, max)
+		if forecastErr != nil {
+			return nil, forecastErr
+		}
+		results = append(results, forecast)
+	}
+	return results, nil
+}
+
 // WaitingJob is a type for determining if work is done
 type WaitingJob struct {
 	Done  bool
 	Error error
 }
 
+// waitForInterval is the pause between calls to the func passed to WaitFor
+const waitForInterval = 100 * time.Millisecond
+
 // WaitFor runs the provided func up to max wait time until it is done
 func (c *Client) WaitFor(f func() (bool, error), max time.Duration) (bool, error) {
-	waitChan := make(chan (WaitingJob))
+	return c.WaitForContext(context.Background(), func(context.Context) (bool, error) { return f() }, max)
+}
+
+// WaitForContext runs the provided func up to max wait time until it is done or ctx is done
+// The context passed to f is cancelled once WaitForContext returns and f is not called again
+func (c *Client) WaitForContext(ctx context.Context, f func(context.Context) (bool, error), max time.Duration) (bool, error) {
+	waitCtx, cancel := context.WithTimeout(ctx, max)
+	defer cancel()
+	waitChan := make(chan (WaitingJob), 1)
 	go func() {
 		for {
-			isDone, doneErr := f()
-			if doneErr != nil {
-				waitChan <- WaitingJob{Done: false, Error: doneErr}
+			isDone, doneErr := f(waitCtx)
+			if doneErr != nil || isDone {
+				waitChan <- WaitingJob{Done: isDone, Error: doneErr}
+				return
 			}
-			if isDone {
-				waitChan <- WaitingJob{Done: true, Error: doneErr}
+			timer := time.NewTimer(waitForInterval)
+			select {
+			case <-waitCtx.Done():
+				timer.Stop()
+				return
+			case <-timer.C:
 			}
 		}
 	}()
-	done := false
-	var err error
 	select {
 	case m := <-waitChan:
-		done = m.Done
-		err = m.Error
-		break
-	case <-time.After(max):
-		done = false
-		err = fmt.Errorf("timeout waiting for job to be done")
-		break
+		return m.Done, m.Error
+	case <-waitCtx.Done():
+		if ctx.Err() != nil {
+			return false, ctx.Err()
+		}
+		return false, fmt.Errorf("timeout waiting for job to be done")
 	}
-	return done, err
 }
@@ -1,19 +1,29 @@
 package rundeck
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
 )
 
 func newTestRundeckClient(content []byte, contentType string, statusCode int) (*Client, *httptest.Server, error) {
-
-	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	return newTestRundeckClientWithHandler(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(statusCode)
 		w.Header().Set("Content-Type", contentType)
 		fmt.Fprintf(w, string(content)) // nolint: errcheck
-	}))
+	})
+}
+
+func newTestRundeckClientWithHandler(handler http.HandlerFunc) (*Client, *httptest.Server, error) {
+	server := httptest.NewServer(handler)
 
 	transport := http.Transport{
 		Proxy: func(req *http.Request) (*url.URL, error) {
@@ -37,3 +47,90 @@ func newTestRundeckClient(content []byte, contentType string, statusCode int) (*
 	}
 	return client, server, nil
 }
+
+func TestWaitForDone(t *testing.T) {
+	client, server, err := newTestRundeckClient([]byte(""), "application/json", 200)
+	require.NoError(t, err)
+	defer server.Close()
+	calls := 0
+	done, doneErr := client.WaitFor(func() (bool, error) {
+		calls++
+		return calls == 3, nil
+	}, 5*time.Second)
+	require.NoError(t, doneErr)
+	require.True(t, done)
+	require.Equal(t, 3, calls)
+}
+
+func TestWaitForError(t *testing.T) {
+	client, server, err := newTestRundeckClient([]byte(""), "application/json", 200)
+	require.NoError(t, err)
+	defer server.Close()
+	done, doneErr := client.WaitFor(func() (bool, error) {
+		return false, errors.New("blew up")
+	}, 5*time.Second)
+	require.Error(t, doneErr)
+	require.False(t, done)
+}
+
+func TestWaitForTimeout(t *testing.T) {
+	client, server, err := newTestRundeckClient([]byte(""), "application/json", 200)
+	require.NoError(t, err)
+	defer server.Close()
+	done, doneErr := client.WaitFor(func() (bool, error) {
+		time.Sleep(10 * time.Millisecond)
+		return false, nil
+	}, 50*time.Millisecond)
+	require.Error(t, doneErr)
+	require.False(t, done)
+}
+
+func TestWaitForContextCancelled(t *testing.T) {
+	client, server, err := newTestRundeckClient([]byte(""), "application/json", 200)
+	require.NoError(t, err)
+	defer server.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	done, doneErr := client.WaitForContext(ctx, func(ctx context.Context) (bool, error) {
+		<-ctx.Done()
+		return false, nil
+	}, 5*time.Second)
+	require.Equal(t, context.Canceled, doneErr)
+	require.False(t, done)
+}
+
+func TestGetProjectForecast(t *testing.T) {
+	jobs := `[{"id":"sched","name":"sched","scheduled":true,"scheduleEnabled":true,"enabled":true},{"id":"manual","name":"manual","scheduled":false,"scheduleEnabled":true,"enabled":true},{"id":"paused","name":"paused","scheduled":true,"scheduleEnabled":false,"enabled":true}]`
+	forecasts := 0
+	client, server, cErr := newTestRundeckClientWithHandler(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/project/testproject/jobs") {
+			_, _ = w.Write([]byte(jobs))
+			return
+		}
+		require.True(t, strings.HasSuffix(r.URL.Path, "/job/sched/forecast"))
+		forecasts++
+		_, _ = w.Write([]byte(`{"id":"sched","name":"sched","futureScheduledExecutions":["2019-05-01T03:00:00Z"]}`))
+	})
+	defer server.Close()
+	require.NoError(t, cErr)
+	res, err := client.GetProjectForecast("testproject", time.Hour, 0)
+	require.NoError(t, err)
+	require.Len(t, res, 1)
+	require.Equal(t, 1, forecasts)
+	require.Len(t, res[0].FireTimes(), 1)
+}
+
+func TestWaitForStopsCallingAfterTimeout(t *testing.T) {
+	client, server, err := newTestRundeckClient([]byte(""), "application/json", 200)
+	require.NoError(t, err)
+	defer server.Close()
+	calls := make(chan struct{}, 100)
+	_, doneErr := client.WaitFor(func() (bool, error) {
+		calls <- struct{}{}
+		return false, nil
+	}, 50*time.Millisecond)
+	require.Error(t, doneErr)
+	seen := len(calls)
+	time.Sleep(3 * waitForInterval)
+	require.Equal(t, seen, len(calls))
+}
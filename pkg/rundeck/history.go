@@ -1,7 +1,9 @@
 package rundeck
 
 import (
+	"context"
 	"encoding/json"
+	"net/url"
 
 	multierror "github.com/hashicorp/go-multierror"
 	httpclient "github.com/lusis/go-rundeck/pkg/httpclient"
@@ -16,23 +18,26 @@ type History struct {
 // ListHistory returns the history for a project
 // http://rundeck.org/docs/api/index.html#listing-history
 func (c *Client) ListHistory(project string, opts ...map[string]string) (*History, error) {
+	return c.ListHistoryContext(context.Background(), project, opts...)
+}
+
+// ListHistoryContext returns the history for a project
+func (c *Client) ListHistoryContext(ctx context.Context, project string, opts ...map[string]string) (*History, error) {
+	return c.listHistory(ctx, project, mapToValues(opts...))
+}
+
+func (c *Client) listHistory(ctx context.Context, project string, u url.Values) (*History, error) {
 	if err := c.checkRequiredAPIVersion(responses.HistoryResponse{}); err != nil {
 		return nil, err
 	}
-	u := make(map[string]string)
-	for _, opt := range opts {
-		for k, v := range opt {
-			u[k] = v
-		}
-	}
 	data := &History{}
 	options := []httpclient.RequestOption{
 		accept("application/json"),
 		contentType("application/x-www-form-urlencoded"),
-		queryParams(u),
+		queryValues(u),
 		requestExpects(200),
 	}
-	res, err := c.httpGet("project/"+project+"/history", options...)
+	res, err := c.httpGet(ctx, "project/"+project+"/history", options...)
 	if err != nil {
 		return nil, err
 	}
@@ -41,3 +46,20 @@ func (c *Client) ListHistory(project string, opts ...map[string]string) (*Histor
 	}
 	return data, nil
 }
+
+// IterateHistory returns an iterator over a project's history matching the options
+// Pages are fetched from the server as the iterator is advanced
+// http://rundeck.org/docs/api/index.html#listing-history
+func (c *Client) IterateHistory(project string, options map[string]string, opts ...PagingOption) *HistoryIterator {
+	return c.IterateHistoryContext(context.Background(), project, options, opts...)
+}
+
+// IterateHistoryContext returns an iterator over a project's history matching the options
+func (c *Client) IterateHistoryContext(ctx context.Context, project string, options map[string]string, opts ...PagingOption) *HistoryIterator {
+	return &HistoryIterator{
+		pager: newPager(ctx, mapToValues(options), opts...),
+		fetch: func(ctx context.Context, params url.Values) (*History, error) {
+			return c.listHistory(ctx, project, params)
+		},
+	}
+}
@@ -1,11 +1,11 @@
 package rundeck
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"io"
 	"net/http"
-	"net/http/cookiejar"
 	"net/url"
 	"strings"
 
@@ -24,6 +24,31 @@ func queryParams(m map[string]string) httpclient.RequestOption {
 	return httpclient.QueryParams(m)
 }
 
+// queryValues sets query params that may be repeated for a request
+func queryValues(v url.Values) httpclient.RequestOption {
+	return httpclient.QueryValues(v)
+}
+
+// mapToValues converts single valued params to url.Values
+func mapToValues(maps ...map[string]string) url.Values {
+	v := url.Values{}
+	for _, m := range maps {
+		for key, value := range m {
+			v.Set(key, value)
+		}
+	}
+	return v
+}
+
+// copyValues returns a copy of v that can be modified without changing v
+func copyValues(v url.Values) url.Values {
+	c := url.Values{}
+	for key, values := range v {
+		c[key] = append([]string(nil), values...)
+	}
+	return c
+}
+
 // requestJSON sets a request to accept and respond with json
 func requestJSON() httpclient.RequestOption {
 	return httpclient.JSON()
@@ -60,142 +85,138 @@ func redirPolicy(req *http.Request, via []*http.Request) error {
 
 // Get performs an http get
 func (rc *Client) Get(path string, opts ...httpclient.RequestOption) ([]byte, error) {
-	return rc.httpGet(path, opts...)
+	return rc.GetContext(context.Background(), path, opts...)
 }
 
-func (rc *Client) httpGet(path string, opts ...httpclient.RequestOption) ([]byte, error) {
-	authOpt, authErr := rc.authWrap()
-	if authErr != nil {
-		return nil, authErr
-	}
-	authOpt = append(authOpt, opts...)
-	resp, err := httpclient.Get(rc.makeAPIPath(path), authOpt...)
+// GetContext performs an http get using the provided context
+func (rc *Client) GetContext(ctx context.Context, path string, opts ...httpclient.RequestOption) ([]byte, error) {
+	return rc.httpGet(ctx, path, opts...)
+}
+
+func (rc *Client) httpGet(ctx context.Context, path string, opts ...httpclient.RequestOption) ([]byte, error) {
+	resp, err := rc.doHTTP(ctx, http.MethodGet, path, httpclient.Get, opts...)
 	if err != nil {
-		if resp != nil {
-			if resp.Status == 404 {
-				return nil, ErrMissingResource
-			}
-			if resp.Body != nil {
-				e := &responses.ErrorResponse{}
-				je := json.Unmarshal(resp.Body, e)
-				if je != nil {
-					return nil, err
-				}
-				return nil, errors.New(e.Message)
-			}
-		}
 		return nil, err
 	}
 	return resp.Body, nil
 }
 
-func (rc *Client) httpPost(path string, opts ...httpclient.RequestOption) ([]byte, error) {
-	authOpt, authErr := rc.authWrap()
-	if authErr != nil {
-		return nil, authErr
+// httpGetStream performs an http get returning the unread response body
+// the caller must close it
+func (rc *Client) httpGetStream(ctx context.Context, path string, opts ...httpclient.RequestOption) (io.ReadCloser, error) {
+	opts = append(opts, httpclient.StreamBody())
+	resp, err := rc.doHTTP(ctx, http.MethodGet, path, httpclient.Get, opts...)
+	if err != nil {
+		return nil, err
 	}
-	opts = append(opts, authOpt...)
-	resp, err := httpclient.Post(rc.makeAPIPath(path), opts...)
+	return resp.Stream, nil
+}
+
+func (rc *Client) httpPost(ctx context.Context, path string, opts ...httpclient.RequestOption) ([]byte, error) {
+	resp, err := rc.doHTTP(ctx, http.MethodPost, path, httpclient.Post, opts...)
 	if err != nil {
-		if resp != nil {
-			if resp.Status == 409 {
-				return nil, ErrResourceConflict
-			}
-			if resp.Status == 404 {
-				return nil, ErrMissingResource
-			}
-			if resp.Body != nil {
-				e := &responses.ErrorResponse{}
-				je := json.Unmarshal(resp.Body, e)
-				if je != nil {
-					return nil, err
-				}
-				return nil, errors.New(e.Message)
-			}
-		}
 		return nil, err
 	}
 	return resp.Body, nil
 }
 
-func (rc *Client) httpPut(path string, opts ...httpclient.RequestOption) ([]byte, error) {
-	authOpt, authErr := rc.authWrap()
-	if authErr != nil {
-		return nil, authErr
+func (rc *Client) httpPut(ctx context.Context, path string, opts ...httpclient.RequestOption) ([]byte, error) {
+	resp, err := rc.doHTTP(ctx, http.MethodPut, path, httpclient.Put, opts...)
+	if err != nil {
+		return nil, err
 	}
-	opts = append(opts, authOpt...)
-	p := rc.makeAPIPath(path)
-	resp, err := httpclient.Put(p, opts...)
+	return resp.Body, nil
+}
+
+func (rc *Client) httpDelete(ctx context.Context, path string, opts ...httpclient.RequestOption) ([]byte, error) {
+	opts = append(opts, httpclient.ExpectStatus(204))
+	resp, err := rc.doHTTP(ctx, http.MethodDelete, path, httpclient.Delete, opts...)
 	if err != nil {
-		if resp != nil {
-			if resp.Status == 409 {
-				return nil, ErrResourceConflict
-			}
-			if resp.Status == 404 {
-				return nil, ErrMissingResource
-			}
-			if resp.Body != nil {
-				e := &responses.ErrorResponse{}
-				je := json.Unmarshal(resp.Body, e)
-				if je != nil {
-					return nil, err
-				}
-				return nil, errors.New(e.Message)
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// doHTTP sends an authenticated request to the api
+// with basic auth a request rejected because the session expired is sent once more after logging in again
+func (rc *Client) doHTTP(ctx context.Context, method, path string, send func(string, ...httpclient.RequestOption) (*httpclient.Response, error), opts ...httpclient.RequestOption) (*httpclient.Response, error) {
+	if rc.Config.AuthMethod == basicAuthType {
+		// the body may need to be sent twice. authWrap logs in before the first send so large
+		// uploads that can't be rewound are only lost to a session that expires mid request
+		opts = append(opts, httpclient.ReplayableBody(maxReplayBody))
+	}
+	var lastErr error
+	for attempt := 1; ; attempt++ {
+		authOpt, generation, authErr := rc.authWrap(ctx)
+		if authErr != nil {
+			return nil, authErr
+		}
+		resp, err := send(rc.makeAPIPath(path), append(authOpt, opts...)...)
+		if err == nil {
+			return resp, nil
+		}
+		if lastErr != nil && errors.Is(err, httpclient.ErrBodyNotReplayable) {
+			return nil, lastErr
+		}
+		lastErr = rc.apiError(method, path, resp, err)
+		if rc.Config.AuthMethod == basicAuthType && sessionExpired(resp, err) {
+			rc.invalidateSession(generation)
+			if attempt == 1 && ctx.Err() == nil {
+				continue
 			}
 		}
+		return nil, lastErr
 	}
-
-	return resp.Body, err
 }
 
-func (rc *Client) httpDelete(path string, opts ...httpclient.RequestOption) ([]byte, error) {
-	authOpt, authErr := rc.authWrap()
-	if authErr != nil {
-		return nil, authErr
+// apiError converts a failed request into an *APIError
+// errors that happened before a response was received are returned unchanged
+func (rc *Client) apiError(method, path string, resp *httpclient.Response, err error) error {
+	if resp == nil {
+		return err
 	}
-	opts = append(opts, authOpt...)
-	opts = append(opts, httpclient.ExpectStatus(204))
-	resp, err := httpclient.Delete(rc.makeAPIPath(path), opts...)
-	if err != nil {
-		if resp != nil {
-			if resp.Status == 404 {
-				return nil, ErrMissingResource
-			}
-			if resp.Body != nil {
-				e := &responses.ErrorResponse{}
-				je := json.Unmarshal(resp.Body, e)
-				if je != nil {
-					return nil, err
-				}
-				return nil, errors.New(e.Message)
-			}
+	e := &APIError{
+		StatusCode: resp.Status,
+		Method:     method,
+		Path:       "/api/" + rc.Config.APIVersion + "/" + path,
+		err:        err,
+	}
+	if len(resp.Body) > 0 {
+		rdErr := &responses.ErrorResponse{}
+		if json.Unmarshal(resp.Body, rdErr) == nil {
+			e.ErrorCode = rdErr.ErrorCode
+			e.Message = rdErr.Message
+			e.APIVersion = rdErr.APIVersion
 		}
-		return nil, err
 	}
-	return resp.Body, nil
+	return e
 }
 
-func (rc *Client) authWrap() ([]httpclient.RequestOption, error) {
+// authWrap returns the options that authenticate a request along with the session generation they use
+func (rc *Client) authWrap(ctx context.Context) ([]httpclient.RequestOption, uint64, error) {
 	if rc.Config.AuthMethod == basicAuthType {
-		authErr := rc.basicAuth()
+		generation, authErr := rc.ensureSession(ctx)
 		return []httpclient.RequestOption{
+			httpclient.WithContext(ctx),
 			httpclient.AddHeaders(map[string]string{
 				"User-Agent": "rundeck-go.v" + rc.Config.APIVersion,
 			}),
-			httpclient.SetCookieJar(rc.HTTPClient.Jar.(*cookiejar.Jar))}, authErr
+			httpclient.SetClient(rc.HTTPClient),
+			httpclient.WithRetry(rc.Config.RetryPolicy)}, generation, authErr
 	}
 	headers := make(map[string]string, 2)
 	headers["X-Rundeck-Auth-Token"] = rc.Config.Token
 	headers["User-Agent"] = "rundeck-go.v" + rc.Config.APIVersion
 
 	return []httpclient.RequestOption{
+		httpclient.WithContext(ctx),
 		httpclient.AddHeaders(headers),
 		httpclient.SetClient(rc.HTTPClient),
-	}, nil
+		httpclient.WithRetry(rc.Config.RetryPolicy),
+	}, 0, nil
 }
 
-func (rc *Client) basicAuth() error {
-	rc.HTTPClient.CheckRedirect = redirPolicy
+func (rc *Client) basicAuth(ctx context.Context) error {
 	baseAuthURL, baseAuthURLErr := url.Parse(rc.Config.BaseURL)
 	if baseAuthURLErr != nil {
 		return ErrInvalidRundeckURL
@@ -213,12 +234,13 @@ func (rc *Client) basicAuth() error {
 	data.Add("j_username", rc.Config.Username)
 	authData := strings.NewReader(data.Encode())
 	opts := []httpclient.RequestOption{
+		httpclient.WithContext(ctx),
 		httpclient.AddHeaders(headers),
 		httpclient.ContentType("application/x-www-form-urlencoded"),
 		httpclient.Accept("*/*"),
 		httpclient.WithBody(authData),
 		httpclient.SetClient(rc.HTTPClient),
-		httpclient.SetCookieJar(rc.HTTPClient.Jar.(*cookiejar.Jar)),
+		httpclient.WithRetry(rc.Config.RetryPolicy),
 	}
 	authReq, authReqErr := httpclient.Post(authURL, opts...)
 	if authReqErr != nil {
@@ -1,7 +1,11 @@
 package rundeck
 
 import (
+	"context"
+	"errors"
+	"net/http"
 	"testing"
+	"time"
 
 	httpclient "github.com/lusis/go-rundeck/pkg/httpclient"
 	"github.com/lusis/go-rundeck/pkg/rundeck/responses"
@@ -11,20 +15,21 @@ import (
 func TestHTTP404(t *testing.T) {
 	client, server, _ := newTestRundeckClient([]byte("hello"), "application/json", 404)
 	defer server.Close()
-	funcs := map[string]func(string, ...httpclient.RequestOption) ([]byte, error){
+	funcs := map[string]func(context.Context, string, ...httpclient.RequestOption) ([]byte, error){
 		"get":  client.httpGet,
 		"put":  client.httpPut,
 		"post": client.httpPost,
 	}
 	for n, f := range funcs {
-		res, err := f("/", requestExpects(200))
+		res, err := f(context.Background(), "/", requestExpects(200))
 		require.Nil(t, res, n+" body should be nil")
 		require.Error(t, err, n+" should return an error")
-		require.IsType(t, ErrMissingResource, err, n+" should return ErrMissingResource")
+		require.IsType(t, &APIError{}, err, n+" should return an APIError")
+		require.True(t, errors.Is(err, ErrMissingResource), n+" should return ErrMissingResource")
 	}
-	_, err := client.httpDelete("/f", requestExpects(204))
+	_, err := client.httpDelete(context.Background(), "/f", requestExpects(204))
 	require.Error(t, err, "delete should return an error")
-	require.IsType(t, ErrMissingResource, err, "delete should return ErrMissingResource")
+	require.True(t, errors.Is(err, ErrMissingResource), "delete should return ErrMissingResource")
 }
 
 func TestRDErrorResponse(t *testing.T) {
@@ -35,18 +40,124 @@ func TestRDErrorResponse(t *testing.T) {
 
 	client, server, _ := newTestRundeckClient(jsonfile, "application/json", 500)
 	defer server.Close()
-	funcs := map[string]func(string, ...httpclient.RequestOption) ([]byte, error){
+	funcs := map[string]func(context.Context, string, ...httpclient.RequestOption) ([]byte, error){
 		"get":  client.httpGet,
 		"put":  client.httpPut,
 		"post": client.httpPost,
 	}
 	for n, f := range funcs {
-		res, reserr := f("/", requestExpects(200))
+		res, reserr := f(context.Background(), "/", requestExpects(200))
 		require.Nil(t, res, n+" body should be nil")
 		require.Error(t, reserr, n+" should return an error")
 		require.Equal(t, "something blew up", reserr.Error())
 	}
-	_, reserr := client.httpDelete("/f", requestExpects(204))
+	_, reserr := client.httpDelete(context.Background(), "/f", requestExpects(204))
 	require.Error(t, reserr, "delete should return an error")
 	require.Equal(t, "something blew up", reserr.Error())
 }
+
+func TestHTTPContextCancelled(t *testing.T) {
+	client, server, _ := newTestRundeckClient([]byte("{}"), "application/json", 200)
+	defer server.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	funcs := map[string]func(context.Context, string, ...httpclient.RequestOption) ([]byte, error){
+		"get":    client.httpGet,
+		"put":    client.httpPut,
+		"post":   client.httpPost,
+		"delete": client.httpDelete,
+	}
+	for n, f := range funcs {
+		res, err := f(ctx, "/", requestExpects(200))
+		require.Nil(t, res, n+" body should be nil")
+		require.Error(t, err, n+" should return an error")
+		require.Contains(t, err.Error(), context.Canceled.Error(), n+" should return the context error")
+	}
+}
+
+func TestClientMethodContextDeadline(t *testing.T) {
+	client, server, _ := newTestRundeckClient([]byte("{}"), "application/json", 200)
+	defer server.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Nanosecond)
+	defer cancel()
+	<-ctx.Done()
+	obj, err := client.GetExecutionInfoContext(ctx, 1)
+	require.Error(t, err)
+	require.Nil(t, obj)
+	require.Contains(t, err.Error(), context.DeadlineExceeded.Error())
+}
+
+func TestAPIError(t *testing.T) {
+	jsonfile, err := responses.GetTestData(responses.ErrorResponseTestFile)
+	require.NoError(t, err)
+	client, server, _ := newTestRundeckClient(jsonfile, "application/json", 400)
+	defer server.Close()
+	_, reserr := client.httpPost(context.Background(), "job/abc123/run", requestExpects(200))
+	var apiErr *APIError
+	require.True(t, errors.As(reserr, &apiErr))
+	require.Equal(t, 400, apiErr.StatusCode)
+	require.Equal(t, "api.error.api-version.unsupported", apiErr.ErrorCode)
+	require.Equal(t, "something blew up", apiErr.Message)
+	require.Equal(t, 14, apiErr.APIVersion)
+	require.Equal(t, http.MethodPost, apiErr.Method)
+	require.Equal(t, "/api/31/job/abc123/run", apiErr.Path)
+	require.True(t, apiErr.InvalidInput())
+	require.False(t, apiErr.Unauthorized())
+	require.False(t, apiErr.ServerError())
+	require.False(t, errors.Is(reserr, ErrMissingResource))
+	require.NotNil(t, errors.Unwrap(reserr))
+}
+
+func TestAPIErrorSentinels(t *testing.T) {
+	testCases := map[int]error{
+		401: ErrAuthFailed,
+		403: ErrAuthFailed,
+		404: ErrMissingResource,
+		409: ErrResourceConflict,
+	}
+	for code, sentinel := range testCases {
+		client, server, _ := newTestRundeckClient([]byte(""), "application/json", code)
+		_, reserr := client.httpGet(context.Background(), "system/info", requestExpects(200))
+		server.Close()
+		require.True(t, errors.Is(reserr, sentinel), "status %d", code)
+		require.Equal(t, sentinel.Error(), reserr.Error(), "status %d", code)
+	}
+}
+
+func TestAPIErrorSentinelMessage(t *testing.T) {
+	body := []byte(`{"error":true,"apiversion":31,"errorCode":"api.error.item.doesnotexist","message":"Job ID does not exist: abc123"}`)
+	client, server, _ := newTestRundeckClient(body, "application/json", 404)
+	defer server.Close()
+	_, reserr := client.httpGet(context.Background(), "job/abc123", requestExpects(200))
+	require.True(t, errors.Is(reserr, ErrMissingResource))
+	require.EqualError(t, reserr, ErrMissingResource.Error()+": Job ID does not exist: abc123")
+}
+
+func TestAPIErrorServerError(t *testing.T) {
+	client, server, _ := newTestRundeckClient([]byte("<html>bad gateway</html>"), "text/html", 502)
+	defer server.Close()
+	_, reserr := client.httpGet(context.Background(), "system/info", requestExpects(200))
+	var apiErr *APIError
+	require.True(t, errors.As(reserr, &apiErr))
+	require.True(t, apiErr.ServerError())
+	require.Empty(t, apiErr.Message)
+	require.NotEmpty(t, reserr.Error())
+}
+
+func TestRetryPolicy(t *testing.T) {
+	attempts := 0
+	client, server, cErr := newTestRundeckClientWithHandler(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+	defer server.Close()
+	require.NoError(t, cErr)
+	client.Config.RetryPolicy = &httpclient.RetryPolicy{MaxAttempts: 2, RetryableStatusCodes: []int{502}}
+	_, err := client.httpGet(context.Background(), "system/info", requestExpects(200))
+	require.NoError(t, err)
+	require.Equal(t, 2, attempts)
+}
@@ -2,9 +2,14 @@ package rundeck
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"mime/multipart"
+	"net/url"
+	"strconv"
 	"time"
 
 	multierror "github.com/hashicorp/go-multierror"
@@ -26,6 +31,39 @@ type JobMetaData struct {
 	responses.JobMetaDataResponse
 }
 
+// BulkDeleteJobResponse is the result of deleting jobs in bulk
+type BulkDeleteJobResponse struct {
+	responses.BulkDeleteJobResponse
+}
+
+// JobForecast represents the upcoming scheduled executions of a job
+type JobForecast struct {
+	responses.JobForecastResponse
+}
+
+// FireTimes returns the times the job is scheduled to run
+func (f *JobForecast) FireTimes() []time.Time {
+	times := []time.Time{}
+	for _, t := range f.FutureScheduledExecutions {
+		if t != nil {
+			times = append(times, t.Time)
+		}
+	}
+	return times
+}
+
+// FireTimesBetween returns the times the job is scheduled to run within the window
+// A zero start or end leaves that side of the window open
+func (f *JobForecast) FireTimesBetween(start, end time.Time) []time.Time {
+	times := []time.Time{}
+	for _, t := range f.FireTimes() {
+		if (start.IsZero() || !t.Before(start)) && (end.IsZero() || !t.After(end)) {
+			times = append(times, t)
+		}
+	}
+	return times
+}
+
 // JobOption represents a job option
 type JobOption struct {
 	Description string
@@ -81,8 +119,7 @@ func RunJobLogLevel(l string) RunJobOption {
 // RunJobRunAt runs the specified job at the specified time
 func RunJobRunAt(t time.Time) RunJobOption {
 	return func(r *requests.RunJobRequest) error {
-		//nolint:govet
-		r.RunAtTime = &requests.JSONTime{t}
+		r.RunAtTime = &requests.JSONTime{Time: t}
 		return nil
 	}
 }
@@ -90,11 +127,16 @@ func RunJobRunAt(t time.Time) RunJobOption {
 // GetJobMetaData gets a job's metadata
 // http://rundeck.org/docs/api/index.html#get-job-metadata
 func (c *Client) GetJobMetaData(id string) (*JobMetaData, error) {
+	return c.GetJobMetaDataContext(context.Background(), id)
+}
+
+// GetJobMetaDataContext gets a job's metadata
+func (c *Client) GetJobMetaDataContext(ctx context.Context, id string) (*JobMetaData, error) {
 	if err := c.checkRequiredAPIVersion(responses.JobMetaDataResponse{}); err != nil {
 		return nil, err
 	}
 	data := &JobMetaData{}
-	res, err := c.httpGet("job/"+id+"/info", requestJSON(), requestExpects(200))
+	res, err := c.httpGet(ctx, "job/"+id+"/info", requestJSON(), requestExpects(200))
 	if err != nil {
 		return nil, err
 	}
@@ -107,6 +149,11 @@ func (c *Client) GetJobMetaData(id string) (*JobMetaData, error) {
 // GetJobDefinition gets a job definition
 // http://rundeck.org/docs/api/index.html#getting-a-job-definition
 func (c *Client) GetJobDefinition(id string, format string) ([]byte, error) {
+	return c.GetJobDefinitionContext(context.Background(), id, format)
+}
+
+// GetJobDefinitionContext gets a job definition
+func (c *Client) GetJobDefinitionContext(ctx context.Context, id string, format string) ([]byte, error) {
 	if err := c.checkRequiredAPIVersion(responses.JobYAMLResponse{}); err != nil {
 		return nil, err
 	}
@@ -116,7 +163,7 @@ func (c *Client) GetJobDefinition(id string, format string) ([]byte, error) {
 		queryParams(map[string]string{"format": format}),
 		requestExpects(200),
 	}
-	res, err := c.httpGet("job/"+id, options...)
+	res, err := c.httpGet(ctx, "job/"+id, options...)
 	if err != nil {
 		return nil, err
 	}
@@ -127,16 +174,26 @@ func (c *Client) GetJobDefinition(id string, format string) ([]byte, error) {
 // GetJobInfo gets a job's details
 // http://rundeck.org/docs/api/index.html#get-job-metadata
 func (c *Client) GetJobInfo(id string) (*JobMetaData, error) {
-	return c.GetJobMetaData(id)
+	return c.GetJobInfoContext(context.Background(), id)
+}
+
+// GetJobInfoContext gets a job's details
+func (c *Client) GetJobInfoContext(ctx context.Context, id string) (*JobMetaData, error) {
+	return c.GetJobMetaDataContext(ctx, id)
 }
 
 // DeleteJob deletes a job
 // http://rundeck.org/docs/api/index.html#deleting-a-job-definition
 func (c *Client) DeleteJob(id string) error {
+	return c.DeleteJobContext(context.Background(), id)
+}
+
+// DeleteJobContext deletes a job
+func (c *Client) DeleteJobContext(ctx context.Context, id string) error {
 	if err := c.checkRequiredAPIVersion(responses.GenericVersionedResponse{}); err != nil {
 		return err
 	}
-	_, err := c.httpDelete("job/"+id, httpclient.ExpectStatus(204))
+	_, err := c.httpDelete(ctx, "job/"+id, httpclient.ExpectStatus(204))
 	return err
 
 }
@@ -144,6 +201,11 @@ func (c *Client) DeleteJob(id string) error {
 // ExportJob exports a job
 // http://rundeck.org/docs/api/index.html#exporting-jobs
 func (c *Client) ExportJob(id string, format string) ([]byte, error) {
+	return c.ExportJobContext(context.Background(), id, format)
+}
+
+// ExportJobContext exports a job
+func (c *Client) ExportJobContext(ctx context.Context, id string, format string) ([]byte, error) {
 	if err := c.checkRequiredAPIVersion(responses.JobYAMLResponse{}); err != nil {
 		return nil, err
 	}
@@ -153,16 +215,44 @@ func (c *Client) ExportJob(id string, format string) ([]byte, error) {
 	}
 	opts := make(map[string]string)
 	opts["format"] = format
-	res, err := c.httpGet("job/"+id, queryParams(opts), requestExpects(200))
+	res, err := c.httpGet(ctx, "job/"+id, queryParams(opts), requestExpects(200))
 	if err != nil {
 		return nil, err
 	}
 	return res, nil
 }
 
+// ExportJobTo exports a job writing the definition to w as it is received
+// http://rundeck.org/docs/api/index.html#exporting-jobs
+func (c *Client) ExportJobTo(id string, format string, w io.Writer) error {
+	return c.ExportJobToContext(context.Background(), id, format, w)
+}
+
+// ExportJobToContext exports a job writing the definition to w as it is received
+func (c *Client) ExportJobToContext(ctx context.Context, id string, format string, w io.Writer) error {
+	if err := c.checkRequiredAPIVersion(responses.JobYAMLResponse{}); err != nil {
+		return err
+	}
+	if format != "xml" && format != "yaml" {
+		return fmt.Errorf("Unknown/unsupported format \"%s\"", format)
+	}
+	res, err := c.httpGetStream(ctx, "job/"+id, queryParams(map[string]string{"format": format}), requestExpects(200))
+	if err != nil {
+		return err
+	}
+	defer func() { _ = res.Close() }()
+	_, err = io.Copy(w, res)
+	return err
+}
+
 // RunJob runs a job
 // http://rundeck.org/docs/api/index.html#running-a-job
 func (c *Client) RunJob(id string, opts ...RunJobOption) (*Execution, error) {
+	return c.RunJobContext(context.Background(), id, opts...)
+}
+
+// RunJobContext runs a job
+func (c *Client) RunJobContext(ctx context.Context, id string, opts ...RunJobOption) (*Execution, error) {
 	if err := c.checkRequiredAPIVersion(responses.ExecutionResponse{}); err != nil {
 		return nil, err
 	}
@@ -178,7 +268,7 @@ func (c *Client) RunJob(id string, opts ...RunJobOption) (*Execution, error) {
 		return nil, &MarshalError{msg: multierror.Append(errEncoding, err).Error()}
 	}
 	body := bytes.NewReader(req)
-	res, pErr := c.httpPost("job/"+id+"/run", withBody(body), requestJSON(), requestExpects(200))
+	res, pErr := c.httpPost(ctx, "job/"+id+"/run", withBody(body), requestJSON(), requestExpects(200))
 	if pErr != nil {
 		return nil, pErr
 	}
@@ -188,15 +278,57 @@ func (c *Client) RunJob(id string, opts ...RunJobOption) (*Execution, error) {
 	return data, nil
 }
 
+// GetJobForecast gets the upcoming scheduled executions for a job
+// within is how far in the future to look. If within is 0 only the next scheduled execution is returned
+// max limits the number of results. A max of 0 uses the server default
+// http://rundeck.org/docs/api/index.html#get-job-forecast
+func (c *Client) GetJobForecast(jobID string, within time.Duration, max int) (*JobForecast, error) {
+	return c.GetJobForecastContext(context.Background(), jobID, within, max)
+}
+
+// GetJobForecastContext gets the upcoming scheduled executions for a job
+func (c *Client) GetJobForecastContext(ctx context.Context, jobID string, within time.Duration, max int) (*JobForecast, error) {
+	if err := c.checkRequiredAPIVersion(responses.JobForecastResponse{}); err != nil {
+		return nil, err
+	}
+	if within < 0 || max < 0 {
+		return nil, errors.New("forecast time and max cannot be negative")
+	}
+	params := make(map[string]string)
+	if within > 0 {
+		if within < time.Second {
+			within = time.Second
+		}
+		params["time"] = relativeTimeFilter(within)
+	}
+	if max > 0 {
+		params["max"] = strconv.Itoa(max)
+	}
+	res, err := c.httpGet(ctx, "job/"+jobID+"/forecast", requestJSON(), queryParams(params), requestExpects(200))
+	if err != nil {
+		return nil, err
+	}
+	data := &JobForecast{}
+	if jsonErr := json.Unmarshal(res, data); jsonErr != nil {
+		return nil, &UnmarshalError{msg: multierror.Append(errDecoding, jsonErr).Error()}
+	}
+	return data, nil
+}
+
 // ListJobs lists the jobs for a project
 // http://rundeck.org/docs/api/index.html#listing-jobs
 func (c *Client) ListJobs(projectID string) (JobList, error) {
+	return c.ListJobsContext(context.Background(), projectID)
+}
+
+// ListJobsContext lists the jobs for a project
+func (c *Client) ListJobsContext(ctx context.Context, projectID string) (JobList, error) {
 	data := JobList{}
 	if err := c.checkRequiredAPIVersion(responses.JobsResponse{}); err != nil {
 		return data, err
 	}
 	url := fmt.Sprintf("project/%s/jobs", projectID)
-	res, err := c.httpGet(url, requestJSON(), requestExpects(200))
+	res, err := c.httpGet(ctx, url, requestJSON(), requestExpects(200))
 	if err != nil {
 		return data, err
 	}
@@ -208,32 +340,94 @@ func (c *Client) ListJobs(projectID string) (JobList, error) {
 
 // BulkJobDelete deletes jobs in bulk
 // http://rundeck.org/docs/api/index.html#bulk-job-delete
-func (c *Client) BulkJobDelete(ids ...string) error {
+func (c *Client) BulkJobDelete(ids ...string) (*BulkDeleteJobResponse, error) {
+	return c.BulkJobDeleteContext(context.Background(), ids...)
+}
+
+// BulkJobDeleteContext deletes jobs in bulk
+func (c *Client) BulkJobDeleteContext(ctx context.Context, ids ...string) (*BulkDeleteJobResponse, error) {
 	if err := c.checkRequiredAPIVersion(responses.BulkDeleteJobResponse{}); err != nil {
-		return err
+		return nil, err
+	}
+	req := &requests.BulkJobDeleteRequest{
+		IDs: ids,
+	}
+	results := &BulkDeleteJobResponse{}
+	data, _ := json.Marshal(req)
+	res, err := c.httpPost(ctx, "jobs/delete",
+		withBody(bytes.NewReader(data)),
+		requestJSON(),
+		requestExpects(200))
+	if err != nil {
+		return nil, err
 	}
-	return fmt.Errorf("not yet implemented")
+	if err := json.Unmarshal(res, results); err != nil {
+		return nil, &UnmarshalError{msg: multierror.Append(errDecoding, err).Error()}
+	}
+	return results, nil
 }
 
 // GetExecutionsForJob gets executions for a job
 // http://rundeck.org/docs/api/index.html#getting-executions-for-a-job
-func (c *Client) GetExecutionsForJob(jobid string) error {
+func (c *Client) GetExecutionsForJob(jobid string, opts ...map[string]string) (*Executions, error) {
+	return c.GetExecutionsForJobContext(context.Background(), jobid, opts...)
+}
+
+// GetExecutionsForJobContext gets executions for a job
+func (c *Client) GetExecutionsForJobContext(ctx context.Context, jobid string, opts ...map[string]string) (*Executions, error) {
+	return c.getExecutionsForJob(ctx, jobid, mapToValues(opts...))
+}
+
+func (c *Client) getExecutionsForJob(ctx context.Context, jobid string, params url.Values) (*Executions, error) {
 	if err := c.checkRequiredAPIVersion(responses.JobExecutionsResponse{}); err != nil {
-		return err
+		return nil, err
+	}
+	data := &Executions{}
+	res, err := c.httpGet(ctx, "job/"+jobid+"/executions",
+		requestJSON(),
+		queryValues(params),
+		requestExpects(200))
+	if err != nil {
+		return nil, err
+	}
+	if jsonErr := json.Unmarshal(res, data); jsonErr != nil {
+		return nil, &UnmarshalError{msg: multierror.Append(errDecoding, jsonErr).Error()}
+	}
+	return data, nil
+}
+
+// IterateJobExecutions returns an iterator over all executions for a job matching the options
+// Pages are fetched from the server as the iterator is advanced
+// http://rundeck.org/docs/api/index.html#getting-executions-for-a-job
+func (c *Client) IterateJobExecutions(jobid string, options map[string]string, opts ...PagingOption) *ExecutionIterator {
+	return c.IterateJobExecutionsContext(context.Background(), jobid, options, opts...)
+}
+
+// IterateJobExecutionsContext returns an iterator over all executions for a job matching the options
+func (c *Client) IterateJobExecutionsContext(ctx context.Context, jobid string, options map[string]string, opts ...PagingOption) *ExecutionIterator {
+	return &ExecutionIterator{
+		pager: newPager(ctx, mapToValues(options), opts...),
+		fetch: func(ctx context.Context, params url.Values) (*Executions, error) {
+			return c.getExecutionsForJob(ctx, jobid, params)
+		},
 	}
-	return fmt.Errorf("not yet implemented")
 }
 
 // DeleteAllExecutionsForJob deletes all executions for a job
 // http://rundeck.org/docs/api/index.html#delete-all-executions-for-a-job
 func (c *Client) DeleteAllExecutionsForJob(jobid string) (*DeletedExecutions, error) {
+	return c.DeleteAllExecutionsForJobContext(context.Background(), jobid)
+}
+
+// DeleteAllExecutionsForJobContext deletes all executions for a job
+func (c *Client) DeleteAllExecutionsForJobContext(ctx context.Context, jobid string) (*DeletedExecutions, error) {
 	if err := c.checkRequiredAPIVersion(responses.BulkDeleteExecutionsResponse{}); err != nil {
 		return nil, err
 	}
 	data := &DeletedExecutions{}
 
 	u := fmt.Sprintf("job/%s/executions", jobid)
-	res, err := c.httpDelete(u,
+	res, err := c.httpDelete(ctx, u,
 		accept("application/json"),
 		requestExpects(200))
 	if err != nil {
@@ -245,29 +439,197 @@ func (c *Client) DeleteAllExecutionsForJob(jobid string) (*DeletedExecutions, er
 	return data, nil
 }
 
+// JobOptionFile is a file to be uploaded for a job 'file' option type
+type JobOptionFile struct {
+	OptionName string
+	FileName   string
+	Contents   io.Reader
+}
+
+// JobOptionFileUpload is the result of uploading files for job options
+type JobOptionFileUpload struct {
+	responses.JobOptionFileUploadResponse
+}
+
+// UploadedJobInputFile represents a file uploaded for a job option
+type UploadedJobInputFile struct {
+	responses.UploadedJobInputFileResponse
+}
+
+// UploadedJobInputFiles is a list of files uploaded for a job
+type UploadedJobInputFiles struct {
+	responses.UploadedJobInputFilesResponse
+}
+
+// ListUploadedFilesOption is a functional option for listing files uploaded for a job
+type ListUploadedFilesOption func(p *map[string]string) error
+
+// ListUploadedFilesState limits the results to files in the specified state
+// valid states are temp, deleted, expired and retained
+func ListUploadedFilesState(state string) ListUploadedFilesOption {
+	return func(p *map[string]string) error {
+		switch state {
+		case "temp", "deleted", "expired", "retained":
+			(*p)["fileState"] = state
+			return nil
+		default:
+			return fmt.Errorf("invalid file state: %s", state)
+		}
+	}
+}
+
+// ListUploadedFilesMax sets the max number of results to return
+func ListUploadedFilesMax(max int) ListUploadedFilesOption {
+	return func(p *map[string]string) error {
+		(*p)["max"] = strconv.Itoa(max)
+		return nil
+	}
+}
+
+// ListUploadedFilesOffset sets the offset of the first result
+func ListUploadedFilesOffset(offset int) ListUploadedFilesOption {
+	return func(p *map[string]string) error {
+		(*p)["offset"] = strconv.Itoa(offset)
+		return nil
+	}
+}
+
 // UploadFileForJobOption uploads a file for a job 'file' option type
+// The returned file reference should be passed as the value of the option when running the job
 // http://rundeck.org/docs/api/index.html#upload-a-file-for-a-job-option
-func (c *Client) UploadFileForJobOption(ids ...string) error {
+func (c *Client) UploadFileForJobOption(jobID, optionName, fileName string, contents io.Reader) (string, error) {
+	return c.UploadFileForJobOptionContext(context.Background(), jobID, optionName, fileName, contents)
+}
+
+// UploadFileForJobOptionContext uploads a file for a job 'file' option type
+func (c *Client) UploadFileForJobOptionContext(ctx context.Context, jobID, optionName, fileName string, contents io.Reader) (string, error) {
 	if err := c.checkRequiredAPIVersion(responses.JobOptionFileUploadResponse{}); err != nil {
-		return err
+		return "", err
+	}
+	if optionName == "" {
+		return "", errors.New("an option name is required")
+	}
+	params := map[string]string{"optionName": optionName}
+	if fileName != "" {
+		params["fileName"] = fileName
+	}
+	res, err := c.httpPost(ctx, "job/"+jobID+"/input/file",
+		withBody(contents),
+		queryParams(params),
+		contentType("application/octet-stream"),
+		accept("application/json"),
+		requestExpects(200))
+	if err != nil {
+		return "", err
 	}
-	return fmt.Errorf("not yet implemented")
+	data := &JobOptionFileUpload{}
+	if jsonErr := json.Unmarshal(res, data); jsonErr != nil {
+		return "", &UnmarshalError{msg: multierror.Append(errDecoding, jsonErr).Error()}
+	}
+	ref, ok := data.Options[optionName]
+	if !ok {
+		return "", fmt.Errorf("no file reference returned for option %s", optionName)
+	}
+	return ref, nil
+}
+
+// UploadFilesForJobOptions uploads multiple files for job 'file' option types in a single multipart request
+// The returned options map can be passed directly to RunJobOpts
+// http://rundeck.org/docs/api/index.html#upload-a-file-for-a-job-option
+func (c *Client) UploadFilesForJobOptions(jobID string, files ...*JobOptionFile) (*JobOptionFileUpload, error) {
+	return c.UploadFilesForJobOptionsContext(context.Background(), jobID, files...)
+}
+
+// UploadFilesForJobOptionsContext uploads multiple files for job 'file' option types in a single multipart request
+func (c *Client) UploadFilesForJobOptionsContext(ctx context.Context, jobID string, files ...*JobOptionFile) (*JobOptionFileUpload, error) {
+	if err := c.checkRequiredAPIVersion(responses.JobOptionFileUploadResponse{}); err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, errors.New("at least one file is required")
+	}
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	for _, f := range files {
+		if f.OptionName == "" {
+			return nil, errors.New("an option name is required")
+		}
+		fileName := f.FileName
+		if fileName == "" {
+			fileName = f.OptionName
+		}
+		part, partErr := writer.CreateFormFile("option."+f.OptionName, fileName)
+		if partErr != nil {
+			return nil, partErr
+		}
+		if _, copyErr := io.Copy(part, f.Contents); copyErr != nil {
+			return nil, copyErr
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	res, err := c.httpPost(ctx, "job/"+jobID+"/input/file",
+		withBody(body),
+		contentType(writer.FormDataContentType()),
+		accept("application/json"),
+		requestExpects(200))
+	if err != nil {
+		return nil, err
+	}
+	data := &JobOptionFileUpload{}
+	if jsonErr := json.Unmarshal(res, data); jsonErr != nil {
+		return nil, &UnmarshalError{msg: multierror.Append(errDecoding, jsonErr).Error()}
+	}
+	return data, nil
 }
 
 // ListFilesUploadedForJob lists files that have been uploaded for a job
 // http://rundeck.org/docs/api/index.html#list-files-uploaded-for-a-job
-func (c *Client) ListFilesUploadedForJob(ids ...string) error {
+func (c *Client) ListFilesUploadedForJob(jobID string, opts ...ListUploadedFilesOption) (*UploadedJobInputFiles, error) {
+	return c.ListFilesUploadedForJobContext(context.Background(), jobID, opts...)
+}
+
+// ListFilesUploadedForJobContext lists files that have been uploaded for a job
+func (c *Client) ListFilesUploadedForJobContext(ctx context.Context, jobID string, opts ...ListUploadedFilesOption) (*UploadedJobInputFiles, error) {
 	if err := c.checkRequiredAPIVersion(responses.UploadedJobInputFilesResponse{}); err != nil {
-		return err
+		return nil, err
 	}
-	return fmt.Errorf("not yet implemented")
+	params := make(map[string]string)
+	for _, opt := range opts {
+		if err := opt(&params); err != nil {
+			return nil, &OptionError{msg: multierror.Append(errOption, err).Error()}
+		}
+	}
+	res, err := c.httpGet(ctx, "job/"+jobID+"/input/files", queryParams(params), requestJSON(), requestExpects(200))
+	if err != nil {
+		return nil, err
+	}
+	data := &UploadedJobInputFiles{}
+	if jsonErr := json.Unmarshal(res, data); jsonErr != nil {
+		return nil, &UnmarshalError{msg: multierror.Append(errDecoding, jsonErr).Error()}
+	}
+	return data, nil
 }
 
 // GetUploadedFileInfo gets info about an uploaded file
 // http://rundeck.org/docs/api/index.html#get-info-about-an-uploaded-file
-func (c *Client) GetUploadedFileInfo(ids ...string) error {
+func (c *Client) GetUploadedFileInfo(fileID string) (*UploadedJobInputFile, error) {
+	return c.GetUploadedFileInfoContext(context.Background(), fileID)
+}
+
+// GetUploadedFileInfoContext gets info about an uploaded file
+func (c *Client) GetUploadedFileInfoContext(ctx context.Context, fileID string) (*UploadedJobInputFile, error) {
 	if err := c.checkRequiredAPIVersion(responses.UploadedJobInputFileResponse{}); err != nil {
-		return err
+		return nil, err
+	}
+	res, err := c.httpGet(ctx, "jobs/file/"+fileID, requestJSON(), requestExpects(200))
+	if err != nil {
+		return nil, err
 	}
-	return fmt.Errorf("not yet implemented")
+	data := &UploadedJobInputFile{}
+	if jsonErr := json.Unmarshal(res, data); jsonErr != nil {
+		return nil, &UnmarshalError{msg: multierror.Append(errDecoding, jsonErr).Error()}
+	}
+	return data, nil
 }
@@ -0,0 +1,543 @@
+package rundeck
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	multierror "github.com/hashicorp/go-multierror"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// JobNotificationEvent is a job event notifications can be sent for
+type JobNotificationEvent string
+
+const (
+	// NotifyOnSuccess notifies when the job succeeds
+	NotifyOnSuccess JobNotificationEvent = "onsuccess"
+	// NotifyOnFailure notifies when the job fails
+	NotifyOnFailure JobNotificationEvent = "onfailure"
+	// NotifyOnStart notifies when the job starts
+	NotifyOnStart JobNotificationEvent = "onstart"
+	// NotifyOnAvgDuration notifies when the job runs longer than its average duration
+	NotifyOnAvgDuration JobNotificationEvent = "onavgduration"
+	// NotifyOnRetryableFailure notifies when the job fails and will be retried
+	NotifyOnRetryableFailure JobNotificationEvent = "onretryablefailure"
+)
+
+// JobOptionSetting is a functional option for configuring a job option with JobBuilder.Option
+type JobOptionSetting func(o *JobOptionDefinition) error
+
+// JobOptionLabel sets the label shown for the option
+func JobOptionLabel(label string) JobOptionSetting {
+	return func(o *JobOptionDefinition) error {
+		o.Label = label
+		return nil
+	}
+}
+
+// JobOptionDescription sets the description of the option
+func JobOptionDescription(d string) JobOptionSetting {
+	return func(o *JobOptionDefinition) error {
+		o.Description = d
+		return nil
+	}
+}
+
+// JobOptionDefault sets the default value of the option
+func JobOptionDefault(v string) JobOptionSetting {
+	return func(o *JobOptionDefinition) error {
+		o.Value = v
+		return nil
+	}
+}
+
+// JobOptionRequired makes the option required
+func JobOptionRequired() JobOptionSetting {
+	return func(o *JobOptionDefinition) error {
+		o.Required = true
+		return nil
+	}
+}
+
+// JobOptionValues sets the list of values to choose from
+// If enforced only these values are allowed
+func JobOptionValues(enforced bool, values ...string) JobOptionSetting {
+	return func(o *JobOptionDefinition) error {
+		if len(values) == 0 {
+			return errors.New("at least one value is required")
+		}
+		o.Values = values
+		o.Enforced = enforced
+		return nil
+	}
+}
+
+// JobOptionValuesURL sets a url to load the values to choose from
+func JobOptionValuesURL(u string) JobOptionSetting {
+	return func(o *JobOptionDefinition) error {
+		o.ValuesURL = u
+		return nil
+	}
+}
+
+// JobOptionRegex sets a regular expression values must match
+func JobOptionRegex(re string) JobOptionSetting {
+	return func(o *JobOptionDefinition) error {
+		o.Regex = re
+		return nil
+	}
+}
+
+// JobOptionMultiValued allows more than one value joined by delimiter
+func JobOptionMultiValued(delimiter string) JobOptionSetting {
+	return func(o *JobOptionDefinition) error {
+		if delimiter == "" {
+			return errors.New("delimiter cannot be empty")
+		}
+		o.MultiValued = true
+		o.Delimiter = delimiter
+		return nil
+	}
+}
+
+// JobOptionSecure makes the option a secure option
+// storagePath optionally sets the key storage path of the default value
+// exposed makes the value available to scripts and commands
+func JobOptionSecure(storagePath string, exposed bool) JobOptionSetting {
+	return func(o *JobOptionDefinition) error {
+		o.Secure = true
+		o.StoragePath = storagePath
+		o.ValueExposed = exposed
+		return nil
+	}
+}
+
+// JobOptionFileType makes the option a file option
+func JobOptionFileType() JobOptionSetting {
+	return func(o *JobOptionDefinition) error {
+		o.Type = "file"
+		return nil
+	}
+}
+
+// JobStepSetting is a functional option for configuring a step added with JobBuilder
+type JobStepSetting func(s *JobStep) error
+
+// StepDescription sets the description of the step
+func StepDescription(d string) JobStepSetting {
+	return func(s *JobStep) error {
+		s.Description = d
+		return nil
+	}
+}
+
+// StepArgs sets the arguments passed to a script step
+func StepArgs(args string) JobStepSetting {
+	return func(s *JobStep) error {
+		s.Args = args
+		return nil
+	}
+}
+
+// StepInterpreter sets the interpreter a script step is run with
+// quoted passes the script and its arguments to the interpreter as a single quoted argument
+func StepInterpreter(interpreter string, quoted bool) JobStepSetting {
+	return func(s *JobStep) error {
+		s.ScriptInterpreter = interpreter
+		s.InterpreterArgsQuoted = quoted
+		return nil
+	}
+}
+
+// StepFileExtension sets the file extension of the temporary file a script step is written to
+func StepFileExtension(ext string) JobStepSetting {
+	return func(s *JobStep) error {
+		s.FileExtension = strings.TrimPrefix(ext, ".")
+		return nil
+	}
+}
+
+// StepErrorHandler sets the step run when this step fails
+// keepGoingOnSuccess continues the workflow if the error handler succeeds
+func StepErrorHandler(handler *JobStep, keepGoingOnSuccess bool) JobStepSetting {
+	return func(s *JobStep) error {
+		if handler == nil {
+			return errors.New("error handler cannot be nil")
+		}
+		h := *handler
+		h.KeepGoingOnSuccess = keepGoingOnSuccess
+		s.ErrorHandler = &h
+		return nil
+	}
+}
+
+// StepLogFilter adds a log filter plugin to the step
+func StepLogFilter(filterType string, config map[string]string) JobStepSetting {
+	return func(s *JobStep) error {
+		if filterType == "" {
+			return errors.New("log filter type is required")
+		}
+		if s.Plugins == nil {
+			s.Plugins = &JobStepPlugins{}
+		}
+		s.Plugins.LogFilter = append(s.Plugins.LogFilter, &JobLogFilter{Type: filterType, Config: config})
+		return nil
+	}
+}
+
+// JobBuilder builds job definitions
+// Problems found while building are reported by Build
+type JobBuilder struct {
+	job  *JobDefinition
+	errs *multierror.Error
+}
+
+// NewJobBuilder starts a job with the given name
+// The job is enabled, uses the node-first strategy and logs at INFO
+func NewJobBuilder(name string) *JobBuilder {
+	execution, schedule := true, true
+	return &JobBuilder{
+		job: &JobDefinition{
+			Name:             name,
+			LogLevel:         "INFO",
+			ExecutionEnabled: &execution,
+			ScheduleEnabled:  &schedule,
+			Sequence:         &JobSequence{Strategy: "node-first", Commands: []*JobStep{}},
+		},
+	}
+}
+
+// NewJobBuilderFrom starts a builder from an existing job definition
+// The definition is copied and not changed by the builder
+func NewJobBuilderFrom(job *JobDefinition) *JobBuilder {
+	b := &JobBuilder{}
+	copied, err := copyJobDefinition(job)
+	if err != nil {
+		b.fail(err)
+		copied = &JobDefinition{}
+	}
+	if copied.Sequence == nil {
+		copied.Sequence = &JobSequence{Commands: []*JobStep{}}
+	}
+	b.job = copied
+	return b
+}
+
+// Clone returns a copy of the builder so near-identical jobs can be built from a template
+func (b *JobBuilder) Clone() *JobBuilder {
+	c := NewJobBuilderFrom(b.job)
+	if b.errs != nil {
+		c.errs = multierror.Append(c.errs, b.errs.Errors...)
+	}
+	return c
+}
+
+func (b *JobBuilder) fail(err error) {
+	b.errs = multierror.Append(b.errs, err)
+}
+
+// Name sets the name of the job
+func (b *JobBuilder) Name(name string) *JobBuilder {
+	b.job.Name = name
+	return b
+}
+
+// Group sets the group of the job (i.e. apps/web)
+func (b *JobBuilder) Group(group string) *JobBuilder {
+	b.job.Group = strings.Trim(group, "/")
+	return b
+}
+
+// Description sets the description of the job
+func (b *JobBuilder) Description(d string) *JobBuilder {
+	b.job.Description = d
+	return b
+}
+
+// UUID sets the uuid of the job
+func (b *JobBuilder) UUID(uuid string) *JobBuilder {
+	b.job.UUID = uuid
+	b.job.ID = uuid
+	return b
+}
+
+// Project sets the project of the job
+func (b *JobBuilder) Project(project string) *JobBuilder {
+	b.job.Project = project
+	return b
+}
+
+// LogLevel sets the log level of the job
+func (b *JobBuilder) LogLevel(level string) *JobBuilder {
+	b.job.LogLevel = strings.ToUpper(level)
+	return b
+}
+
+// Enabled sets if the job can be run and if its schedule is enabled
+func (b *JobBuilder) Enabled(execution, schedule bool) *JobBuilder {
+	b.job.ExecutionEnabled = &execution
+	b.job.ScheduleEnabled = &schedule
+	return b
+}
+
+// MultipleExecutions allows the job to run more than once at the same time
+// max limits the number of concurrent executions. 0 is unlimited
+func (b *JobBuilder) MultipleExecutions(max int) *JobBuilder {
+	if max < 0 {
+		b.fail(errors.New("max multiple executions cannot be negative"))
+		return b
+	}
+	b.job.MultipleExecutions = true
+	b.job.MaxMultipleExecutions = ""
+	if max > 0 {
+		b.job.MaxMultipleExecutions = strconv.Itoa(max)
+	}
+	return b
+}
+
+// Timeout sets the max run time of the job (i.e. 1h30m)
+func (b *JobBuilder) Timeout(timeout string) *JobBuilder {
+	b.job.Timeout = timeout
+	return b
+}
+
+// Retry sets the number of times a failed job is retried and the delay between attempts
+func (b *JobBuilder) Retry(count int, delay string) *JobBuilder {
+	if count < 0 {
+		b.fail(errors.New("retry count cannot be negative"))
+		return b
+	}
+	b.job.Retry = &JobRetry{Retry: strconv.Itoa(count), Delay: delay}
+	return b
+}
+
+// Option adds an option to the job
+func (b *JobBuilder) Option(name string, settings ...JobOptionSetting) *JobBuilder {
+	o := &JobOptionDefinition{Name: name}
+	for _, s := range settings {
+		if err := s(o); err != nil {
+			b.fail(fmt.Errorf("option %q: %s", name, err))
+			return b
+		}
+	}
+	b.job.Options = append(b.job.Options, o)
+	return b
+}
+
+// Step adds a step to the job's workflow
+func (b *JobBuilder) Step(step *JobStep, settings ...JobStepSetting) *JobBuilder {
+	if step == nil {
+		b.fail(errors.New("step cannot be nil"))
+		return b
+	}
+	s := *step
+	for _, setting := range settings {
+		if err := setting(&s); err != nil {
+			b.fail(fmt.Errorf("step %d: %s", len(b.job.Sequence.Commands)+1, err))
+			return b
+		}
+	}
+	b.job.Sequence.Commands = append(b.job.Sequence.Commands, &s)
+	return b
+}
+
+// Command adds a command step to the job's workflow
+func (b *JobBuilder) Command(command string, settings ...JobStepSetting) *JobBuilder {
+	return b.Step(&JobStep{Exec: command}, settings...)
+}
+
+// Script adds an inline script step to the job's workflow
+func (b *JobBuilder) Script(script string, settings ...JobStepSetting) *JobBuilder {
+	return b.Step(&JobStep{Script: script}, settings...)
+}
+
+// ScriptFile adds a step running a script file on the server to the job's workflow
+func (b *JobBuilder) ScriptFile(path string, settings ...JobStepSetting) *JobBuilder {
+	return b.Step(&JobStep{ScriptFile: path}, settings...)
+}
+
+// ScriptURL adds a step running a script downloaded from a url to the job's workflow
+func (b *JobBuilder) ScriptURL(u string, settings ...JobStepSetting) *JobBuilder {
+	return b.Step(&JobStep{ScriptURL: u}, settings...)
+}
+
+// JobRef adds a step running another job to the job's workflow
+func (b *JobBuilder) JobRef(ref *JobReference, settings ...JobStepSetting) *JobBuilder {
+	if ref == nil {
+		b.fail(errors.New("job reference cannot be nil"))
+		return b
+	}
+	r := *ref
+	return b.Step(&JobStep{JobRef: &r}, settings...)
+}
+
+// PluginStep adds a plugin step to the job's workflow
+// nodeStep runs the plugin on each node instead of once for the workflow
+func (b *JobBuilder) PluginStep(pluginType string, nodeStep bool, config map[string]interface{}, settings ...JobStepSetting) *JobBuilder {
+	return b.Step(&JobStep{Type: pluginType, NodeStep: nodeStep, Configuration: config}, settings...)
+}
+
+// KeepGoing continues the workflow when a step fails
+func (b *JobBuilder) KeepGoing(keepGoing bool) *JobBuilder {
+	b.job.Sequence.KeepGoing = keepGoing
+	return b
+}
+
+// Strategy sets the workflow strategy (i.e. node-first, step-first or parallel)
+func (b *JobBuilder) Strategy(strategy string) *JobBuilder {
+	b.job.Sequence.Strategy = strategy
+	return b
+}
+
+// NodeFilter dispatches the job to the nodes matching filter
+func (b *JobBuilder) NodeFilter(filter string) *JobBuilder {
+	b.nodeFilters().Filter = filter
+	return b
+}
+
+// Dispatch sets how the job is dispatched to its nodes
+func (b *JobBuilder) Dispatch(threadCount int, keepGoing bool) *JobBuilder {
+	if threadCount < 1 {
+		b.fail(errors.New("thread count must be at least 1"))
+		return b
+	}
+	f := b.nodeFilters()
+	if f.Dispatch == nil {
+		f.Dispatch = &JobDispatch{ExcludePrecedence: true}
+	}
+	f.Dispatch.ThreadCount = strconv.Itoa(threadCount)
+	f.Dispatch.KeepGoing = keepGoing
+	return b
+}
+
+// Rank sets the attribute and order nodes are run in
+func (b *JobBuilder) Rank(attribute string, descending bool) *JobBuilder {
+	f := b.nodeFilters()
+	if f.Dispatch == nil {
+		f.Dispatch = &JobDispatch{ThreadCount: "1", ExcludePrecedence: true}
+	}
+	f.Dispatch.RankAttribute = attribute
+	f.Dispatch.RankOrder = "ascending"
+	if descending {
+		f.Dispatch.RankOrder = "descending"
+	}
+	return b
+}
+
+func (b *JobBuilder) nodeFilters() *JobNodeFilters {
+	if b.job.NodeFilters == nil {
+		b.job.NodeFilters = &JobNodeFilters{}
+	}
+	return b.job.NodeFilters
+}
+
+// Cron schedules the job with a quartz cron expression (i.e. 0 30 9 ? * MON-FRI *)
+func (b *JobBuilder) Cron(expr string) *JobBuilder {
+	b.job.Schedule = &JobSchedule{Crontab: expr}
+	return b
+}
+
+// TimeZone sets the time zone the schedule is in
+func (b *JobBuilder) TimeZone(tz string) *JobBuilder {
+	b.job.TimeZone = tz
+	return b
+}
+
+// NotifyEmail sends an email to a comma separated list of recipients on event
+func (b *JobBuilder) NotifyEmail(event JobNotificationEvent, recipients, subject string, attachLog bool) *JobBuilder {
+	n := b.notification(event)
+	if n != nil {
+		n.Email = &JobEmailNotification{Recipients: recipients, Subject: subject, AttachLog: attachLog}
+	}
+	return b
+}
+
+// NotifyWebhook posts to urls on event
+func (b *JobBuilder) NotifyWebhook(event JobNotificationEvent, urls ...string) *JobBuilder {
+	n := b.notification(event)
+	if n != nil {
+		n.URLs = strings.Join(urls, ",")
+	}
+	return b
+}
+
+// NotifyPlugin sends a notification with a plugin on event
+func (b *JobBuilder) NotifyPlugin(event JobNotificationEvent, pluginType string, config map[string]interface{}) *JobBuilder {
+	n := b.notification(event)
+	if n != nil {
+		n.Plugins = append(n.Plugins, &JobPlugin{Type: pluginType, Configuration: config})
+	}
+	return b
+}
+
+func (b *JobBuilder) notification(event JobNotificationEvent) *JobNotification {
+	if b.job.Notification == nil {
+		b.job.Notification = &JobNotifications{}
+	}
+	var n **JobNotification
+	switch event {
+	case NotifyOnSuccess:
+		n = &b.job.Notification.OnSuccess
+	case NotifyOnFailure:
+		n = &b.job.Notification.OnFailure
+	case NotifyOnStart:
+		n = &b.job.Notification.OnStart
+	case NotifyOnAvgDuration:
+		n = &b.job.Notification.OnAvgDuration
+	case NotifyOnRetryableFailure:
+		n = &b.job.Notification.OnRetryableFailure
+	default:
+		b.fail(fmt.Errorf("unknown notification event %q", event))
+		return nil
+	}
+	if *n == nil {
+		*n = &JobNotification{}
+	}
+	return *n
+}
+
+// Build returns the validated job definition
+// The builder can keep being used after Build without changing the returned definition
+func (b *JobBuilder) Build() (*JobDefinition, error) {
+	if b.errs != nil {
+		return nil, &JobValidationError{msg: multierror.Append(errValidation, b.errs.Errors...).Error(), Errors: b.errs.Errors}
+	}
+	job, err := copyJobDefinition(b.job)
+	if err != nil {
+		return nil, err
+	}
+	if err := job.Validate(); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+// Import builds the job and imports it into project
+func (b *JobBuilder) Import(client *Client, project string, opts ...JobImportOption) (*JobImportResult, error) {
+	return b.ImportContext(context.Background(), client, project, opts...)
+}
+
+// ImportContext builds the job and imports it into project
+func (b *JobBuilder) ImportContext(ctx context.Context, client *Client, project string, opts ...JobImportOption) (*JobImportResult, error) {
+	job, err := b.Build()
+	if err != nil {
+		return nil, err
+	}
+	return client.ImportJobDefinitionsContext(ctx, project, []*JobDefinition{job}, opts...)
+}
+
+// copyJobDefinition deep copies a job definition through its yaml form
+func copyJobDefinition(job *JobDefinition) (*JobDefinition, error) {
+	data, err := yaml.Marshal(job)
+	if err != nil {
+		return nil, &MarshalError{msg: multierror.Append(errEncoding, err).Error()}
+	}
+	copied := &JobDefinition{}
+	if err := yaml.Unmarshal(data, copied); err != nil {
+		return nil, &UnmarshalError{msg: multierror.Append(errDecoding, err).Error()}
+	}
+	return copied, nil
+}
@@ -0,0 +1,142 @@
+package rundeck
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/lusis/go-rundeck/pkg/rundeck/responses"
+	"github.com/stretchr/testify/require"
+)
+
+func testJobBuilder() *JobBuilder {
+	return NewJobBuilder("deploy").
+		Group("/apps/web/").
+		Description("deploys the web app").
+		Option("version", JobOptionRequired(), JobOptionValues(true, "1.0", "1.1"), JobOptionDefault("1.1")).
+		Option("password", JobOptionSecure("keys/web/password", false)).
+		Command("service web stop", StepDescription("stop"), StepErrorHandler(&JobStep{Exec: "echo failed"}, true)).
+		Script("#!/bin/sh\necho ${option.version}", StepArgs("-v"), StepFileExtension(".sh"), StepLogFilter("mask-passwords", nil)).
+		JobRef(&JobReference{Group: "common", Name: "notify", NodeStep: true}).
+		PluginStep("export-var", false, map[string]interface{}{"export": "v", "group": "g", "value": "x"}).
+		NodeFilter("tags: web").
+		Dispatch(2, true).
+		Rank("nodename", true).
+		Cron("0 30 9 ? * MON-FRI *").
+		TimeZone("UTC").
+		NotifyEmail(NotifyOnFailure, "ops@example.com", "deploy failed", true).
+		NotifyWebhook(NotifyOnSuccess, "https://hooks.example.com/a", "https://hooks.example.com/b").
+		NotifyPlugin(NotifyOnStart, "SlackNotification", map[string]interface{}{"channel": "#deploys"}).
+		Retry(2, "30s").
+		Timeout("1h").
+		MultipleExecutions(3)
+}
+
+func TestJobBuilder(t *testing.T) {
+	job, err := testJobBuilder().Build()
+	require.NoError(t, err)
+	require.Equal(t, "deploy", job.Name)
+	require.Equal(t, "apps/web", job.Group)
+	require.True(t, *job.ExecutionEnabled)
+	require.Equal(t, "INFO", job.LogLevel)
+	require.Len(t, job.Options, 2)
+	require.Equal(t, []string{"1.0", "1.1"}, job.Options[0].Values)
+	require.True(t, job.Options[0].Enforced)
+	require.True(t, job.Options[1].Secure)
+	require.Len(t, job.Sequence.Commands, 4)
+	require.Equal(t, "stop", job.Sequence.Commands[0].Description)
+	require.True(t, job.Sequence.Commands[0].ErrorHandler.KeepGoingOnSuccess)
+	require.Equal(t, "sh", job.Sequence.Commands[1].FileExtension)
+	require.Equal(t, "mask-passwords", job.Sequence.Commands[1].Plugins.LogFilter[0].Type)
+	require.Equal(t, "notify", job.Sequence.Commands[2].JobRef.Name)
+	require.Equal(t, "export-var", job.Sequence.Commands[3].Type)
+	require.Equal(t, &JobNodeFilters{Filter: "tags: web", Dispatch: &JobDispatch{
+		ThreadCount: "2", KeepGoing: true, ExcludePrecedence: true, RankAttribute: "nodename", RankOrder: "descending",
+	}}, job.NodeFilters)
+	require.Equal(t, "0 30 9 ? * MON-FRI *", job.Schedule.Crontab)
+	require.Equal(t, "ops@example.com", job.Notification.OnFailure.Email.Recipients)
+	require.Equal(t, "https://hooks.example.com/a,https://hooks.example.com/b", job.Notification.OnSuccess.URLs)
+	require.Equal(t, "SlackNotification", job.Notification.OnStart.Plugins[0].Type)
+	require.Equal(t, &JobRetry{Retry: "2", Delay: "30s"}, job.Retry)
+	require.Equal(t, "3", job.MaxMultipleExecutions)
+}
+
+func TestJobBuilderTemplate(t *testing.T) {
+	template := NewJobBuilder("template").Command("uptime").NodeFilter("tags: web")
+	jobs := []*JobDefinition{}
+	for _, env := range []string{"dev", "prod"} {
+		job, err := template.Clone().Name("uptime-" + env).Group(env).Build()
+		require.NoError(t, err)
+		jobs = append(jobs, job)
+	}
+	require.Equal(t, "uptime-dev", jobs[0].Name)
+	require.Equal(t, "prod", jobs[1].Group)
+	// building from a clone leaves the template alone
+	job, err := template.Build()
+	require.NoError(t, err)
+	require.Equal(t, "template", job.Name)
+	require.Equal(t, "", job.Group)
+
+	// changes to the builder after Build don't leak into the built job
+	template.Command("hostname")
+	require.Len(t, job.Sequence.Commands, 1)
+}
+
+func TestJobBuilderFrom(t *testing.T) {
+	existing := testFullJobDefinition()
+	job, err := NewJobBuilderFrom(existing).Name("copy").Build()
+	require.NoError(t, err)
+	require.Equal(t, "copy", job.Name)
+	require.Equal(t, "deploy", existing.Name)
+	require.Equal(t, existing.Sequence, job.Sequence)
+}
+
+func TestJobBuilderErrors(t *testing.T) {
+	_, err := NewJobBuilder("bad").
+		Option("dupe").
+		Option("dupe").
+		Option("choice", JobOptionValues(true)).
+		Dispatch(0, false).
+		Cron("0 0 12 * * * *").
+		NotifyEmail("onsometimes", "x", "", false).
+		Build()
+	require.Error(t, err)
+	var validationErr *JobValidationError
+	require.True(t, errors.As(err, &validationErr))
+	// setting errors are reported before the job is validated
+	require.Len(t, validationErr.Errors, 3)
+
+	_, err = NewJobBuilder("bad").Option("dupe").Option("dupe").Cron("0 0 12 * * * *").Build()
+	require.True(t, errors.As(err, &validationErr))
+	require.Len(t, validationErr.Errors, 3)
+	require.Contains(t, err.Error(), `option "dupe" is defined more than once`)
+	require.Contains(t, err.Error(), "schedule")
+	require.Contains(t, err.Error(), "at least one step")
+}
+
+func TestJobBuilderImport(t *testing.T) {
+	imported, err := responses.GetTestData(responses.ImportedJobResponseTestFile)
+	require.NoError(t, err)
+	client, server, cErr := newTestRundeckClientWithHandler(func(w http.ResponseWriter, r *http.Request) {
+		require.True(t, strings.HasSuffix(r.URL.Path, "/project/testproject/jobs/import"))
+		require.Equal(t, "yaml", r.URL.Query().Get("format"))
+		body, readErr := ioutil.ReadAll(r.Body)
+		require.NoError(t, readErr)
+		jobs, parseErr := ParseJobDefinitions(body, JobFormatYAML)
+		require.NoError(t, parseErr)
+		require.Equal(t, "deploy", jobs[0].Name)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(imported)
+	})
+	defer server.Close()
+	require.NoError(t, cErr)
+	res, err := testJobBuilder().Import(client, "testproject")
+	require.NoError(t, err)
+	require.NotNil(t, res)
+
+	// invalid jobs are never sent
+	_, err = NewJobBuilder("").Import(client, "testproject")
+	require.Error(t, err)
+}
@@ -0,0 +1,520 @@
+package rundeck
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	multierror "github.com/hashicorp/go-multierror"
+	responses "github.com/lusis/go-rundeck/pkg/rundeck/responses"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// Job definition formats supported by ParseJobDefinitions and MarshalJobDefinitions
+const (
+	JobFormatYAML = "yaml"
+	JobFormatXML  = "xml"
+	JobFormatJSON = "json"
+)
+
+// JobDefinition is a complete rundeck job definition
+// It is the model behind rundeck's yaml, xml and json job formats
+// The yaml field names are shared with the json format
+type JobDefinition struct {
+	ID                         string                 `yaml:"id,omitempty"`
+	UUID                       string                 `yaml:"uuid,omitempty"`
+	Name                       string                 `yaml:"name"`
+	Group                      string                 `yaml:"group,omitempty"`
+	Project                    string                 `yaml:"project,omitempty"`
+	Description                string                 `yaml:"description"`
+	LogLevel                   string                 `yaml:"loglevel,omitempty"`
+	LogLimit                   string                 `yaml:"loglimit,omitempty"`
+	LogLimitAction             string                 `yaml:"loglimitAction,omitempty"`
+	LogLimitStatus             string                 `yaml:"loglimitStatus,omitempty"`
+	ExecutionEnabled           *bool                  `yaml:"executionEnabled,omitempty"`
+	ScheduleEnabled            *bool                  `yaml:"scheduleEnabled,omitempty"`
+	MultipleExecutions         bool                   `yaml:"multipleExecutions,omitempty"`
+	MaxMultipleExecutions      string                 `yaml:"maxMultipleExecutions,omitempty"`
+	Timeout                    string                 `yaml:"timeout,omitempty"`
+	Retry                      *JobRetry              `yaml:"retry,omitempty"`
+	NodeFilterEditable         bool                   `yaml:"nodeFilterEditable"`
+	NodesSelectedByDefault     *bool                  `yaml:"nodesSelectedByDefault,omitempty"`
+	NodeFilters                *JobNodeFilters        `yaml:"nodefilters,omitempty"`
+	Schedule                   *JobSchedule           `yaml:"schedule,omitempty"`
+	TimeZone                   string                 `yaml:"timeZone,omitempty"`
+	Notification               *JobNotifications      `yaml:"notification,omitempty"`
+	NotifyAvgDurationThreshold string                 `yaml:"notifyAvgDurationThreshold,omitempty"`
+	DefaultTab                 string                 `yaml:"defaultTab,omitempty"`
+	Options                    JobOptionDefinitions   `yaml:"options,omitempty"`
+	Orchestrator               *JobOrchestrator       `yaml:"orchestrator,omitempty"`
+	Sequence                   *JobSequence           `yaml:"sequence,omitempty"`
+	Plugins                    map[string]interface{} `yaml:"plugins,omitempty"`
+	// Extra holds the settings this model doesn't know about so they are written back unchanged
+	Extra map[string]interface{} `yaml:",inline"`
+}
+
+// IsExecutionEnabled reports if the job can be run
+// rundeck enables execution when executionEnabled isn't set
+func (j *JobDefinition) IsExecutionEnabled() bool {
+	return j.ExecutionEnabled == nil || *j.ExecutionEnabled
+}
+
+// IsScheduleEnabled reports if the job's schedule is enabled
+// rundeck enables the schedule when scheduleEnabled isn't set
+func (j *JobDefinition) IsScheduleEnabled() bool {
+	return j.ScheduleEnabled == nil || *j.ScheduleEnabled
+}
+
+// JobRetry is the retry setting of a job
+// Retry and Delay may reference options (i.e. ${option.retries})
+type JobRetry struct {
+	Retry string `yaml:"retry"`
+	Delay string `yaml:"delay,omitempty"`
+}
+
+// MarshalYAML writes the retry as a plain value unless a delay is set
+func (r *JobRetry) MarshalYAML() (interface{}, error) {
+	if r.Delay == "" {
+		return r.Retry, nil
+	}
+	type plain JobRetry
+	return (*plain)(r), nil
+}
+
+// UnmarshalYAML reads the retry as either a plain value or a retry and delay
+func (r *JobRetry) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var retry string
+	if err := unmarshal(&retry); err == nil {
+		r.Retry = retry
+		return nil
+	}
+	type plain JobRetry
+	return unmarshal((*plain)(r))
+}
+
+// JobNodeFilters selects the nodes a job or job reference runs on
+type JobNodeFilters struct {
+	Filter        string       `yaml:"filter,omitempty"`
+	FilterExclude string       `yaml:"filterExclude,omitempty"`
+	Dispatch      *JobDispatch `yaml:"dispatch,omitempty"`
+}
+
+// JobDispatch controls how a job is dispatched to its nodes
+type JobDispatch struct {
+	// ThreadCount is a string as it may reference an option
+	ThreadCount              string `yaml:"threadcount,omitempty"`
+	KeepGoing                bool   `yaml:"keepgoing"`
+	ExcludePrecedence        bool   `yaml:"excludePrecedence"`
+	RankAttribute            string `yaml:"rankAttribute,omitempty"`
+	RankOrder                string `yaml:"rankOrder,omitempty"`
+	SuccessOnEmptyNodeFilter bool   `yaml:"successOnEmptyNodeFilter,omitempty"`
+}
+
+// JobSchedule is when a job runs. Either Crontab or the individual fields are set
+type JobSchedule struct {
+	Crontab    string           `yaml:"crontab,omitempty"`
+	Month      string           `yaml:"month,omitempty"`
+	DayOfMonth *JobScheduleDay  `yaml:"dayofmonth,omitempty"`
+	Weekday    *JobScheduleDay  `yaml:"weekday,omitempty"`
+	Time       *JobScheduleTime `yaml:"time,omitempty"`
+	Year       string           `yaml:"year,omitempty"`
+}
+
+// JobScheduleDay is the day of the week or month a job runs on
+type JobScheduleDay struct {
+	Day string `yaml:"day"`
+}
+
+// JobScheduleTime is the time of day a job runs at
+type JobScheduleTime struct {
+	Hour    string `yaml:"hour"`
+	Minute  string `yaml:"minute"`
+	Seconds string `yaml:"seconds,omitempty"`
+}
+
+// JobNotifications are the notifications for each job event
+type JobNotifications struct {
+	OnSuccess          *JobNotification `yaml:"onsuccess,omitempty"`
+	OnFailure          *JobNotification `yaml:"onfailure,omitempty"`
+	OnStart            *JobNotification `yaml:"onstart,omitempty"`
+	OnAvgDuration      *JobNotification `yaml:"onavgduration,omitempty"`
+	OnRetryableFailure *JobNotification `yaml:"onretryablefailure,omitempty"`
+}
+
+// JobNotification is the set of notifications sent for a job event
+type JobNotification struct {
+	Email *JobEmailNotification `yaml:"email,omitempty"`
+	// URLs is a comma separated list of webhook urls
+	URLs       string     `yaml:"urls,omitempty"`
+	Format     string     `yaml:"format,omitempty"`
+	HTTPMethod string     `yaml:"httpMethod,omitempty"`
+	Plugins    JobPlugins `yaml:"plugin,omitempty"`
+}
+
+// JobEmailNotification is an email notification
+type JobEmailNotification struct {
+	// Recipients is a comma separated list of email addresses
+	Recipients      string `yaml:"recipients"`
+	Subject         string `yaml:"subject,omitempty"`
+	AttachLog       bool   `yaml:"attachLog,omitempty"`
+	AttachLogInFile bool   `yaml:"attachLogInFile,omitempty"`
+	AttachLogInline bool   `yaml:"attachLogInline,omitempty"`
+}
+
+// JobPlugin is a configured plugin
+// Configuration values are usually strings but some plugins take lists or maps
+type JobPlugin struct {
+	Type          string                 `yaml:"type"`
+	Configuration map[string]interface{} `yaml:"configuration,omitempty"`
+}
+
+// JobPlugins is a list of plugins
+// rundeck writes a single plugin as a map instead of a list
+type JobPlugins []*JobPlugin
+
+// MarshalYAML writes a single plugin as a map
+func (p JobPlugins) MarshalYAML() (interface{}, error) {
+	if len(p) == 1 {
+		return p[0], nil
+	}
+	return []*JobPlugin(p), nil
+}
+
+// UnmarshalYAML reads either a single plugin or a list of plugins
+func (p *JobPlugins) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	single := &JobPlugin{}
+	if err := unmarshal(single); err == nil {
+		*p = JobPlugins{single}
+		return nil
+	}
+	var list []*JobPlugin
+	if err := unmarshal(&list); err != nil {
+		return err
+	}
+	*p = list
+	return nil
+}
+
+// JobOptionDefinition is an option a job can be run with
+type JobOptionDefinition struct {
+	Name                  string   `yaml:"name"`
+	Label                 string   `yaml:"label,omitempty"`
+	Description           string   `yaml:"description,omitempty"`
+	Type                  string   `yaml:"type,omitempty"`
+	Value                 string   `yaml:"value,omitempty"`
+	Values                []string `yaml:"values,omitempty"`
+	ValuesURL             string   `yaml:"valuesUrl,omitempty"`
+	ValuesListDelimiter   string   `yaml:"valuesListDelimiter,omitempty"`
+	SortValues            bool     `yaml:"sortValues,omitempty"`
+	Enforced              bool     `yaml:"enforced,omitempty"`
+	Regex                 string   `yaml:"regex,omitempty"`
+	Required              bool     `yaml:"required,omitempty"`
+	Hidden                bool     `yaml:"hidden,omitempty"`
+	Secure                bool     `yaml:"secure,omitempty"`
+	ValueExposed          bool     `yaml:"valueExposed,omitempty"`
+	StoragePath           string   `yaml:"storagePath,omitempty"`
+	MultiValued           bool     `yaml:"multivalued,omitempty"`
+	Delimiter             string   `yaml:"delimiter,omitempty"`
+	MultiValueAllSelected bool     `yaml:"multivalueAllSelected,omitempty"`
+	IsDate                bool     `yaml:"isDate,omitempty"`
+	DateFormat            string   `yaml:"dateFormat,omitempty"`
+}
+
+// JobOptionDefinitions is the ordered list of a job's options
+// Older definitions that map option names to options are read in the order they are written
+type JobOptionDefinitions []*JobOptionDefinition
+
+// UnmarshalYAML reads either a list of options or a map of option names to options
+func (o *JobOptionDefinitions) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var list []*JobOptionDefinition
+	if err := unmarshal(&list); err == nil {
+		*o = list
+		return nil
+	}
+	var named yaml.MapSlice
+	if err := unmarshal(&named); err != nil {
+		return err
+	}
+	options := make(JobOptionDefinitions, 0, len(named))
+	for _, item := range named {
+		data, err := yaml.Marshal(item.Value)
+		if err != nil {
+			return err
+		}
+		option := &JobOptionDefinition{}
+		if err := yaml.Unmarshal(data, option); err != nil {
+			return err
+		}
+		if option.Name == "" {
+			option.Name = fmt.Sprint(item.Key)
+		}
+		options = append(options, option)
+	}
+	*o = options
+	return nil
+}
+
+// JobOrchestrator is the orchestrator plugin used to pick the order nodes are run on
+type JobOrchestrator struct {
+	Type          string            `yaml:"type"`
+	Configuration map[string]string `yaml:"configuration,omitempty"`
+}
+
+// JobSequence is the workflow of a job
+type JobSequence struct {
+	KeepGoing    bool                   `yaml:"keepgoing"`
+	Strategy     string                 `yaml:"strategy,omitempty"`
+	PluginConfig map[string]interface{} `yaml:"pluginConfig,omitempty"`
+	Commands     []*JobStep             `yaml:"commands"`
+}
+
+// JobStep is a single step of a job's workflow
+// Exactly one of Exec, Script, ScriptFile, ScriptURL, JobRef or Type is set
+type JobStep struct {
+	Description             string                 `yaml:"description,omitempty"`
+	Exec                    string                 `yaml:"exec,omitempty"`
+	Script                  string                 `yaml:"script,omitempty"`
+	ScriptFile              string                 `yaml:"scriptfile,omitempty"`
+	ScriptURL               string                 `yaml:"scripturl,omitempty"`
+	Args                    string                 `yaml:"args,omitempty"`
+	FileExtension           string                 `yaml:"fileExtension,omitempty"`
+	ScriptInterpreter       string                 `yaml:"scriptInterpreter,omitempty"`
+	InterpreterArgsQuoted   bool                   `yaml:"interpreterArgsQuoted,omitempty"`
+	ExpandTokenInScriptFile bool                   `yaml:"expandTokenInScriptFile,omitempty"`
+	JobRef                  *JobReference          `yaml:"jobref,omitempty"`
+	Type                    string                 `yaml:"type,omitempty"`
+	NodeStep                bool                   `yaml:"nodeStep,omitempty"`
+	Configuration           map[string]interface{} `yaml:"configuration,omitempty"`
+	ErrorHandler            *JobStep               `yaml:"errorhandler,omitempty"`
+	KeepGoingOnSuccess      bool                   `yaml:"keepgoingOnSuccess,omitempty"`
+	Plugins                 *JobStepPlugins        `yaml:"plugins,omitempty"`
+}
+
+// JobStepPlugins are the plugins applied to a single step
+type JobStepPlugins struct {
+	LogFilter []*JobLogFilter `yaml:"LogFilter,omitempty"`
+}
+
+// JobLogFilter is a log filter plugin applied to a step's output
+type JobLogFilter struct {
+	Type   string            `yaml:"type"`
+	Config map[string]string `yaml:"config,omitempty"`
+}
+
+// JobReference is a step that runs another job
+// The job is found by UUID or by Name, Group and Project
+type JobReference struct {
+	Name                string          `yaml:"name,omitempty"`
+	Group               string          `yaml:"group,omitempty"`
+	UUID                string          `yaml:"uuid,omitempty"`
+	Project             string          `yaml:"project,omitempty"`
+	Args                string          `yaml:"args,omitempty"`
+	NodeStep            bool            `yaml:"nodeStep,omitempty"`
+	ImportOptions       bool            `yaml:"importOptions,omitempty"`
+	FailOnDisable       bool            `yaml:"failOnDisable,omitempty"`
+	ChildNodes          bool            `yaml:"childNodes,omitempty"`
+	IgnoreNotifications bool            `yaml:"ignoreNotifications,omitempty"`
+	UseName             bool            `yaml:"useName,omitempty"`
+	NodeFilters         *JobNodeFilters `yaml:"nodefilters,omitempty"`
+}
+
+// UnmarshalYAML reads a job reference
+// rundeck writes the boolean flags of a job reference as quoted strings
+func (r *JobReference) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	raw := map[string]interface{}{}
+	if err := unmarshal(&raw); err != nil {
+		return err
+	}
+	for _, key := range []string{"nodeStep", "importOptions", "failOnDisable", "childNodes", "ignoreNotifications", "useName"} {
+		s, ok := raw[key].(string)
+		if !ok {
+			continue
+		}
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return fmt.Errorf("jobref %s: %s", key, err)
+		}
+		raw[key] = b
+	}
+	data, err := yaml.Marshal(raw)
+	if err != nil {
+		return err
+	}
+	type plain JobReference
+	return yaml.Unmarshal(data, (*plain)(r))
+}
+
+// ParseJobDefinitions parses job definitions in one of rundeck's job formats
+func ParseJobDefinitions(data []byte, format string) ([]*JobDefinition, error) {
+	jobs := []*JobDefinition{}
+	var err error
+	switch format {
+	case JobFormatYAML:
+		err = yaml.Unmarshal(data, &jobs)
+	case JobFormatJSON:
+		jobs, err = parseJSONJobDefinitions(data)
+	case JobFormatXML:
+		jobs, err = parseXMLJobDefinitions(data)
+	default:
+		return nil, fmt.Errorf("Unknown/unsupported format \"%s\"", format)
+	}
+	if err != nil {
+		return nil, &UnmarshalError{msg: multierror.Append(errDecoding, err).Error()}
+	}
+	return jobs, nil
+}
+
+// parseJSONJobDefinitions reads the json format, which shares the yaml field names
+// The json is decoded with encoding/json since yaml doesn't accept every json escape (i.e. \/ or surrogate pairs)
+// and the result is converted to the yaml document structure
+func parseJSONJobDefinitions(data []byte) ([]*JobDefinition, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var doc interface{}
+	if err := dec.Decode(&doc); err != nil {
+		return nil, err
+	}
+	yamlData, err := yaml.Marshal(yamlValue(doc))
+	if err != nil {
+		return nil, err
+	}
+	jobs := []*JobDefinition{}
+	if err := yaml.Unmarshal(yamlData, &jobs); err != nil {
+		return nil, err
+	}
+	return jobs, nil
+}
+
+// yamlValue converts json numbers so they are written as yaml numbers instead of strings
+func yamlValue(v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for k, val := range t {
+			t[k] = yamlValue(val)
+		}
+		return t
+	case []interface{}:
+		for i := range t {
+			t[i] = yamlValue(t[i])
+		}
+		return t
+	case json.Number:
+		if i, err := t.Int64(); err == nil {
+			return i
+		}
+		if f, err := t.Float64(); err == nil {
+			return f
+		}
+		return t.String()
+	default:
+		return v
+	}
+}
+
+// MarshalJobDefinitions writes job definitions in one of rundeck's job formats
+func MarshalJobDefinitions(jobs []*JobDefinition, format string) ([]byte, error) {
+	var data []byte
+	var err error
+	switch format {
+	case JobFormatYAML:
+		data, err = yaml.Marshal(jobs)
+	case JobFormatJSON:
+		data, err = marshalJSONJobDefinitions(jobs)
+	case JobFormatXML:
+		data, err = marshalXMLJobDefinitions(jobs)
+	default:
+		return nil, fmt.Errorf("Unknown/unsupported format \"%s\"", format)
+	}
+	if err != nil {
+		return nil, &MarshalError{msg: multierror.Append(errEncoding, err).Error()}
+	}
+	return data, nil
+}
+
+// marshalJSONJobDefinitions writes the yaml document structure as json
+func marshalJSONJobDefinitions(jobs []*JobDefinition) ([]byte, error) {
+	if jobs == nil {
+		jobs = []*JobDefinition{}
+	}
+	data, err := yaml.Marshal(jobs)
+	if err != nil {
+		return nil, err
+	}
+	var doc interface{}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	return json.MarshalIndent(jsonValue(doc), "", "  ")
+}
+
+// jsonValue converts the maps decoded by yaml to maps json can encode
+func jsonValue(v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(t))
+		for k, val := range t {
+			m[fmt.Sprintf("%v", k)] = jsonValue(val)
+		}
+		return m
+	case []interface{}:
+		for i := range t {
+			t[i] = jsonValue(t[i])
+		}
+		return t
+	default:
+		return v
+	}
+}
+
+// ExportJobDefinition gets the definition of a job
+// http://rundeck.org/docs/api/index.html#getting-a-job-definition
+func (c *Client) ExportJobDefinition(id string) (*JobDefinition, error) {
+	return c.ExportJobDefinitionContext(context.Background(), id)
+}
+
+// ExportJobDefinitionContext gets the definition of a job
+func (c *Client) ExportJobDefinitionContext(ctx context.Context, id string) (*JobDefinition, error) {
+	data, err := c.GetJobDefinitionContext(ctx, id, JobFormatYAML)
+	if err != nil {
+		return nil, err
+	}
+	jobs, err := ParseJobDefinitions(data, JobFormatYAML)
+	if err != nil {
+		return nil, err
+	}
+	if len(jobs) != 1 {
+		return nil, &UnmarshalError{msg: multierror.Append(errDecoding, fmt.Errorf("expected 1 job definition but got %d", len(jobs))).Error()}
+	}
+	return jobs[0], nil
+}
+
+// ImportJobDefinitions imports jobs into a project
+// The jobs are sent in the format set with ImportFormat or as yaml by default
+// http://rundeck.org/docs/api/index.html#importing-jobs
+func (c *Client) ImportJobDefinitions(project string, jobs []*JobDefinition, opts ...JobImportOption) (*JobImportResult, error) {
+	return c.ImportJobDefinitionsContext(context.Background(), project, jobs, opts...)
+}
+
+// ImportJobDefinitionsContext imports jobs into a project
+func (c *Client) ImportJobDefinitionsContext(ctx context.Context, project string, jobs []*JobDefinition, opts ...JobImportOption) (*JobImportResult, error) {
+	if err := c.checkRequiredAPIVersion(responses.ImportedJobResponse{}); err != nil {
+		return nil, err
+	}
+	importDef := &JobImportDefinition{}
+	for _, o := range opts {
+		if err := o(importDef); err != nil {
+			return nil, &OptionError{msg: multierror.Append(errOption, err).Error()}
+		}
+	}
+	if importDef.Format == "" {
+		importDef.Format = JobFormatYAML
+		opts = append(opts, ImportFormat(JobFormatYAML))
+	}
+	data, err := MarshalJobDefinitions(jobs, importDef.Format)
+	if err != nil {
+		return nil, err
+	}
+	return c.ImportJobContext(ctx, project, bytes.NewReader(data), opts...)
+}
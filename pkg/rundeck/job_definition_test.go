@@ -0,0 +1,469 @@
+package rundeck
+
+import (
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/lusis/go-rundeck/pkg/rundeck/responses"
+	"github.com/stretchr/testify/require"
+)
+
+func testFullJobDefinition() *JobDefinition {
+	selected, enabled := false, true
+	return &JobDefinition{
+		ID:                         "d1b9f2c1-d6a6-43ca-8e9f-85519d5a1323",
+		UUID:                       "d1b9f2c1-d6a6-43ca-8e9f-85519d5a1323",
+		Name:                       "deploy",
+		Group:                      "apps/web",
+		Description:                "deploys the web app",
+		LogLevel:                   "INFO",
+		LogLimit:                   "100MB",
+		LogLimitAction:             "truncate",
+		LogLimitStatus:             "failed",
+		ExecutionEnabled:           &enabled,
+		ScheduleEnabled:            &enabled,
+		MultipleExecutions:         true,
+		MaxMultipleExecutions:      "3",
+		Timeout:                    "1h",
+		Retry:                      &JobRetry{Retry: "2", Delay: "30s"},
+		NodeFilterEditable:         true,
+		NodesSelectedByDefault:     &selected,
+		TimeZone:                   "America/New_York",
+		NotifyAvgDurationThreshold: "+30",
+		DefaultTab:                 "output",
+		NodeFilters: &JobNodeFilters{
+			Filter:        "tags: web",
+			FilterExclude: "name: web-3",
+			Dispatch: &JobDispatch{
+				ThreadCount:       "${option.threads}",
+				KeepGoing:         true,
+				ExcludePrecedence: true,
+				RankAttribute:     "nodename",
+				RankOrder:         "descending",
+			},
+		},
+		Schedule: &JobSchedule{
+			Month:      "*",
+			DayOfMonth: &JobScheduleDay{Day: "1"},
+			Time:       &JobScheduleTime{Hour: "09", Minute: "30", Seconds: "0"},
+			Year:       "*",
+		},
+		Notification: &JobNotifications{
+			OnSuccess: &JobNotification{
+				Email: &JobEmailNotification{Recipients: "ops@example.com", Subject: "deployed", AttachLog: true, AttachLogInline: true},
+			},
+			OnFailure: &JobNotification{
+				URLs:       "https://hooks.example.com/a,https://hooks.example.com/b",
+				Format:     "json",
+				HTTPMethod: "post",
+				Plugins: JobPlugins{
+					{Type: "SlackNotification", Configuration: map[string]interface{}{"channel": "#ops", "webhook": "abc"}},
+					{Type: "PagerDutyNotification", Configuration: map[string]interface{}{"service_key": "123"}},
+				},
+			},
+			OnStart: &JobNotification{
+				Plugins: JobPlugins{{Type: "SlackNotification", Configuration: map[string]interface{}{"channel": "#deploys"}}},
+			},
+		},
+		Options: JobOptionDefinitions{
+			{
+				Name:                "version",
+				Label:               "Version",
+				Description:         "version to deploy",
+				Value:               "latest",
+				Values:              []string{"latest", "1.0", "1.1"},
+				ValuesListDelimiter: ";",
+				Enforced:            true,
+				Required:            true,
+				MultiValued:         true,
+				Delimiter:           ",",
+			},
+			{
+				Name:         "password",
+				Secure:       true,
+				ValueExposed: true,
+				StoragePath:  "keys/web/password",
+			},
+			{
+				Name:       "when",
+				IsDate:     true,
+				DateFormat: "YYYY-MM-DD",
+				Hidden:     true,
+				Regex:      `\d+`,
+				ValuesURL:  "https://example.com/values.json",
+				SortValues: true,
+			},
+			{Name: "config", Type: "file"},
+		},
+		Orchestrator: &JobOrchestrator{Type: "subset", Configuration: map[string]string{"count": "2"}},
+		Sequence: &JobSequence{
+			KeepGoing: true,
+			Strategy:  "node-first",
+			Commands: []*JobStep{
+				{
+					Description: "stop",
+					Exec:        "service web stop",
+					ErrorHandler: &JobStep{
+						Exec:               "echo could not stop",
+						KeepGoingOnSuccess: true,
+					},
+				},
+				{
+					Script:                "#!/bin/bash\necho <deploying> ${option.version} && exit 0\n",
+					Args:                  "-v ${option.version}",
+					FileExtension:         "sh",
+					ScriptInterpreter:     "sudo -u web",
+					InterpreterArgsQuoted: true,
+					Plugins: &JobStepPlugins{LogFilter: []*JobLogFilter{
+						{Type: "key-value-data", Config: map[string]string{"regex": `^RUNDECK:DATA:(.+?)\s*=\s*(.+)$`}},
+					}},
+				},
+				{ScriptFile: "/opt/scripts/migrate.sh", ExpandTokenInScriptFile: true},
+				{ScriptURL: "https://example.com/check.sh"},
+				{
+					JobRef: &JobReference{
+						Name:          "notify",
+						Group:         "common",
+						UUID:          "e3f5a2b1-0000-1111-2222-333344445555",
+						Project:       "shared",
+						Args:          "-env prod",
+						NodeStep:      true,
+						ImportOptions: true,
+						FailOnDisable: true,
+						ChildNodes:    true,
+						UseName:       true,
+						NodeFilters: &JobNodeFilters{
+							Filter:   "name: web-1",
+							Dispatch: &JobDispatch{ThreadCount: "1"},
+						},
+					},
+				},
+				{Type: "localexec", NodeStep: true, Configuration: map[string]interface{}{"command": "uptime"}},
+				{Type: "export-var", Description: "export", Configuration: map[string]interface{}{"export": "v", "group": "vars", "value": "x"}},
+			},
+		},
+	}
+}
+
+func TestJobDefinitionRoundTrip(t *testing.T) {
+	job := testFullJobDefinition()
+	scheduled := false
+	cronJob := &JobDefinition{
+		Name:            "nightly",
+		Retry:           &JobRetry{Retry: "1"},
+		ScheduleEnabled: &scheduled,
+		Schedule:        &JobSchedule{Crontab: "0 0 2 ? * * *"},
+		Sequence:        &JobSequence{Commands: []*JobStep{{Exec: "true"}}},
+	}
+	for _, format := range []string{JobFormatYAML, JobFormatJSON, JobFormatXML} {
+		t.Run(format, func(t *testing.T) {
+			data, err := MarshalJobDefinitions([]*JobDefinition{job, cronJob}, format)
+			require.NoError(t, err)
+			parsed, err := ParseJobDefinitions(data, format)
+			require.NoError(t, err)
+			require.Equal(t, []*JobDefinition{job, cronJob}, parsed)
+		})
+	}
+}
+
+func TestJobDefinitionCrossFormat(t *testing.T) {
+	job := testFullJobDefinition()
+	xmlData, err := MarshalJobDefinitions([]*JobDefinition{job}, JobFormatXML)
+	require.NoError(t, err)
+	fromXML, err := ParseJobDefinitions(xmlData, JobFormatXML)
+	require.NoError(t, err)
+	jsonData, err := MarshalJobDefinitions(fromXML, JobFormatJSON)
+	require.NoError(t, err)
+	fromJSON, err := ParseJobDefinitions(jsonData, JobFormatJSON)
+	require.NoError(t, err)
+	yamlData, err := MarshalJobDefinitions(fromJSON, JobFormatYAML)
+	require.NoError(t, err)
+	fromYAML, err := ParseJobDefinitions(yamlData, JobFormatYAML)
+	require.NoError(t, err)
+	require.Equal(t, []*JobDefinition{job}, fromYAML)
+}
+
+func TestJobDefinitionPluginsXMLRoundTrip(t *testing.T) {
+	data := []byte(`- name: cleanup
+  plugins:
+    ExecutionLifecycle:
+      killhandler:
+        killChilds: 'true'
+      result-data-json-template:
+        jsonTemplate: '{"status": "${execution.status}"}'
+  sequence:
+    commands:
+    - exec: rm -rf /tmp/build
+    pluginConfig:
+      WorkflowStrategy:
+        node-first: {}
+      LogFilter:
+      - type: mask-passwords
+        config:
+          color: red
+      - type: highlight-output
+        config:
+          regex: ERROR
+    strategy: node-first
+`)
+	jobs, err := ParseJobDefinitions(data, JobFormatYAML)
+	require.NoError(t, err)
+	xmlData, err := MarshalJobDefinitions(jobs, JobFormatXML)
+	require.NoError(t, err)
+	require.Contains(t, string(xmlData), "<plugins>\n      <ExecutionLifecycle>\n        <killhandler>\n          <killChilds>true</killChilds>")
+	require.Contains(t, string(xmlData), "<WorkflowStrategy>\n          <node-first></node-first>\n        </WorkflowStrategy>")
+	fromXML, err := ParseJobDefinitions(xmlData, JobFormatXML)
+	require.NoError(t, err)
+	require.Equal(t, jobs, fromXML)
+	yamlData, err := MarshalJobDefinitions(fromXML, JobFormatYAML)
+	require.NoError(t, err)
+	fromYAML, err := ParseJobDefinitions(yamlData, JobFormatYAML)
+	require.NoError(t, err)
+	require.Equal(t, jobs, fromYAML)
+}
+
+func TestJobDefinitionPluginConfigRoundTrip(t *testing.T) {
+	data := []byte(`- name: notify
+  notification:
+    onsuccess:
+      plugin:
+        type: HttpNotification
+        configuration:
+          url: https://hooks.example.com
+          headers:
+            X-Token: abc
+  sequence:
+    commands:
+    - type: ansible-playbook
+      nodeStep: true
+      configuration:
+        playbook: site.yml
+        extraVars:
+        - env=prod
+        - region=us-east-1
+`)
+	jobs, err := ParseJobDefinitions(data, JobFormatYAML)
+	require.NoError(t, err)
+	xmlData, err := MarshalJobDefinitions(jobs, JobFormatXML)
+	require.NoError(t, err)
+	require.Contains(t, string(xmlData), `<configuration data="true">`)
+	require.Contains(t, string(xmlData), "<extraVars>env=prod</extraVars>\n            <extraVars>region=us-east-1</extraVars>")
+	for _, format := range []string{JobFormatYAML, JobFormatJSON, JobFormatXML} {
+		t.Run(format, func(t *testing.T) {
+			out, err := MarshalJobDefinitions(jobs, format)
+			require.NoError(t, err)
+			parsed, err := ParseJobDefinitions(out, format)
+			require.NoError(t, err)
+			require.Equal(t, jobs, parsed)
+		})
+	}
+}
+
+func TestJobDefinitionUnknownKeys(t *testing.T) {
+	data := []byte(`- name: cleanup
+  runnerSelector:
+    filter: tags:linux
+    runnerFilterType: TAG_FILTER_AND
+  successOnEmptyNodeFilter: 'true'
+  sequence:
+    commands:
+    - exec: rm -rf /tmp/build
+`)
+	jobs, err := ParseJobDefinitions(data, JobFormatYAML)
+	require.NoError(t, err)
+	require.Equal(t, "true", jobs[0].Extra["successOnEmptyNodeFilter"])
+	xmlData, err := MarshalJobDefinitions(jobs, JobFormatXML)
+	require.NoError(t, err)
+	require.Contains(t, string(xmlData), "<runnerSelector>\n      <filter>tags:linux</filter>")
+	for _, format := range []string{JobFormatYAML, JobFormatJSON, JobFormatXML} {
+		t.Run(format, func(t *testing.T) {
+			out, err := MarshalJobDefinitions(jobs, format)
+			require.NoError(t, err)
+			parsed, err := ParseJobDefinitions(out, format)
+			require.NoError(t, err)
+			require.Equal(t, jobs, parsed)
+		})
+	}
+}
+
+func TestJobDefinitionEnabledDefaults(t *testing.T) {
+	data := []byte(`- name: cleanup
+  sequence:
+    commands:
+    - exec: rm -rf /tmp/build
+`)
+	jobs, err := ParseJobDefinitions(data, JobFormatYAML)
+	require.NoError(t, err)
+	require.Nil(t, jobs[0].ExecutionEnabled)
+	require.Nil(t, jobs[0].ScheduleEnabled)
+	require.True(t, jobs[0].IsExecutionEnabled())
+	require.True(t, jobs[0].IsScheduleEnabled())
+	for _, format := range []string{JobFormatYAML, JobFormatJSON, JobFormatXML} {
+		t.Run(format, func(t *testing.T) {
+			out, err := MarshalJobDefinitions(jobs, format)
+			require.NoError(t, err)
+			require.NotContains(t, string(out), "executionEnabled")
+			require.NotContains(t, string(out), "scheduleEnabled")
+			parsed, err := ParseJobDefinitions(out, format)
+			require.NoError(t, err)
+			require.Equal(t, jobs, parsed)
+		})
+	}
+
+	disabled := false
+	jobs[0].ExecutionEnabled = &disabled
+	out, err := MarshalJobDefinitions(jobs, JobFormatYAML)
+	require.NoError(t, err)
+	require.Contains(t, string(out), "executionEnabled: false")
+	require.False(t, jobs[0].IsExecutionEnabled())
+}
+
+func TestParseJobDefinitionsJSONEscapes(t *testing.T) {
+	data := []byte(`[{"name":"deploy \ud83d\ude00","group":"app\/web","description":"a\tb","executionEnabled":true,` +
+		`"timeout":30,"sequence":{"keepgoing":false,"commands":[{"exec":"echo \"\/opt\/app\""}]}}]`)
+	jobs, err := ParseJobDefinitions(data, JobFormatJSON)
+	require.NoError(t, err)
+	require.Len(t, jobs, 1)
+	require.Equal(t, "deploy \U0001F600", jobs[0].Name)
+	require.Equal(t, "app/web", jobs[0].Group)
+	require.Equal(t, "a\tb", jobs[0].Description)
+	require.Equal(t, "30", jobs[0].Timeout)
+	require.True(t, *jobs[0].ExecutionEnabled)
+	require.Equal(t, `echo "/opt/app"`, jobs[0].Sequence.Commands[0].Exec)
+
+	_, err = ParseJobDefinitions([]byte(`[{"name":`), JobFormatJSON)
+	require.IsType(t, &UnmarshalError{}, err)
+}
+
+func TestParseJobDefinitionsRundeckYAML(t *testing.T) {
+	data, err := responses.GetTestData(responses.JobYAMLResponseTestFile)
+	require.NoError(t, err)
+	jobs, err := ParseJobDefinitions(data, JobFormatYAML)
+	require.NoError(t, err)
+	require.Len(t, jobs, 1)
+	job := jobs[0]
+	require.Equal(t, "sleep-job", job.Name)
+	require.True(t, *job.ExecutionEnabled)
+	require.Len(t, job.Options, 1)
+	require.True(t, job.Options[0].Required)
+	require.Equal(t, "node-first", job.Sequence.Strategy)
+	require.Len(t, job.Sequence.Commands, 3)
+	require.Equal(t, "localexec", job.Sequence.Commands[0].Type)
+	require.Equal(t, "sleep ${option.sleeptime}", job.Sequence.Commands[0].Configuration["command"])
+	require.True(t, job.Sequence.Commands[1].JobRef.NodeStep)
+	require.Equal(t, "others/things", job.Sequence.Commands[1].JobRef.Group)
+}
+
+func TestParseJobDefinitionsRundeckXML(t *testing.T) {
+	data := `<?xml version="1.0" encoding="UTF-8"?>
+<joblist>
+  <job>
+    <description>sleeps</description>
+    <executionEnabled>true</executionEnabled>
+    <id>abc</id>
+    <loglevel>INFO</loglevel>
+    <name>sleep</name>
+    <nodeFilterEditable>false</nodeFilterEditable>
+    <retry>3</retry>
+    <schedule>
+      <month month="*" />
+      <time hour="12" minute="0" seconds="0" />
+      <weekday day="MON-FRI" />
+      <year year="*" />
+    </schedule>
+    <scheduleEnabled>true</scheduleEnabled>
+    <context>
+      <options preserveOrder="true">
+        <option name="sleeptime" value="30" values="10,30,60" enforcedvalues="true" required="true">
+          <description>how long</description>
+        </option>
+      </options>
+    </context>
+    <sequence keepgoing="false" strategy="node-first">
+      <command>
+        <exec>sleep ${option.sleeptime}</exec>
+      </command>
+      <command>
+        <script><![CDATA[echo "done" && exit 0]]></script>
+      </command>
+    </sequence>
+  </job>
+</joblist>`
+	jobs, err := ParseJobDefinitions([]byte(data), JobFormatXML)
+	require.NoError(t, err)
+	require.Len(t, jobs, 1)
+	job := jobs[0]
+	require.Equal(t, &JobRetry{Retry: "3"}, job.Retry)
+	require.Equal(t, &JobScheduleDay{Day: "MON-FRI"}, job.Schedule.Weekday)
+	require.Equal(t, []string{"10", "30", "60"}, job.Options[0].Values)
+	require.True(t, job.Options[0].Enforced)
+	require.Equal(t, "how long", job.Options[0].Description)
+	require.Equal(t, `echo "done" && exit 0`, job.Sequence.Commands[1].Script)
+}
+
+func TestParseJobDefinitionsOptionMap(t *testing.T) {
+	data := `
+- name: old
+  options:
+    zeta:
+      required: true
+    alpha:
+      value: a
+    empty:
+`
+	jobs, err := ParseJobDefinitions([]byte(data), JobFormatYAML)
+	require.NoError(t, err)
+	require.Equal(t, JobOptionDefinitions{{Name: "zeta", Required: true}, {Name: "alpha", Value: "a"}, {Name: "empty"}}, jobs[0].Options)
+}
+
+func TestJobDefinitionFormatErrors(t *testing.T) {
+	_, err := ParseJobDefinitions([]byte("name: x"), "toml")
+	require.Error(t, err)
+	_, err = MarshalJobDefinitions(nil, "toml")
+	require.Error(t, err)
+	_, err = ParseJobDefinitions([]byte("- name: [x"), JobFormatYAML)
+	require.Error(t, err)
+	_, ok := err.(*UnmarshalError)
+	require.True(t, ok)
+}
+
+func TestExportJobDefinition(t *testing.T) {
+	data, err := responses.GetTestData(responses.JobYAMLResponseTestFile)
+	require.NoError(t, err)
+	client, server, cErr := newTestRundeckClientWithHandler(func(w http.ResponseWriter, r *http.Request) {
+		require.True(t, strings.HasSuffix(r.URL.Path, "/job/abc"))
+		require.Equal(t, "yaml", r.URL.Query().Get("format"))
+		w.Header().Set("Content-Type", "application/yaml")
+		_, _ = w.Write(data)
+	})
+	defer server.Close()
+	require.NoError(t, cErr)
+	job, err := client.ExportJobDefinition("abc")
+	require.NoError(t, err)
+	require.Equal(t, "sleep-job", job.Name)
+}
+
+func TestImportJobDefinitions(t *testing.T) {
+	imported, err := responses.GetTestData(responses.ImportedJobResponseTestFile)
+	require.NoError(t, err)
+	job := testFullJobDefinition()
+	client, server, cErr := newTestRundeckClientWithHandler(func(w http.ResponseWriter, r *http.Request) {
+		require.True(t, strings.HasSuffix(r.URL.Path, "/project/testproject/jobs/import"))
+		require.Equal(t, "xml", r.URL.Query().Get("format"))
+		require.Equal(t, "update", r.URL.Query().Get("dupeOption"))
+		body, readErr := ioutil.ReadAll(r.Body)
+		require.NoError(t, readErr)
+		jobs, parseErr := ParseJobDefinitions(body, JobFormatXML)
+		require.NoError(t, parseErr)
+		require.Equal(t, []*JobDefinition{job}, jobs)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(imported)
+	})
+	defer server.Close()
+	require.NoError(t, cErr)
+	res, err := client.ImportJobDefinitions("testproject", []*JobDefinition{job}, ImportFormat("xml"), ImportDupe("update"))
+	require.NoError(t, err)
+	require.NotNil(t, res)
+}
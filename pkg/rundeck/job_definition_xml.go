@@ -0,0 +1,954 @@
+package rundeck
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// the xml job format differs enough from the yaml format that it has its own types
+// rather than a second set of tags on JobDefinition
+
+type xmlJobList struct {
+	XMLName xml.Name  `xml:"joblist"`
+	Jobs    []*xmlJob `xml:"job"`
+}
+
+type xmlJob struct {
+	ID                         string            `xml:"id,omitempty"`
+	UUID                       string            `xml:"uuid,omitempty"`
+	Name                       string            `xml:"name"`
+	Group                      string            `xml:"group,omitempty"`
+	Description                string            `xml:"description"`
+	LogLevel                   string            `xml:"loglevel,omitempty"`
+	Logging                    *xmlLogging       `xml:"logging,omitempty"`
+	ExecutionEnabled           *bool             `xml:"executionEnabled,omitempty"`
+	ScheduleEnabled            *bool             `xml:"scheduleEnabled,omitempty"`
+	MultipleExecutions         bool              `xml:"multipleExecutions,omitempty"`
+	MaxMultipleExecutions      string            `xml:"maxMultipleExecutions,omitempty"`
+	Timeout                    string            `xml:"timeout,omitempty"`
+	Retry                      *xmlRetry         `xml:"retry,omitempty"`
+	NodeFilterEditable         bool              `xml:"nodeFilterEditable"`
+	NodesSelectedByDefault     *bool             `xml:"nodesSelectedByDefault,omitempty"`
+	NodeFilters                *xmlNodeFilters   `xml:"nodefilters,omitempty"`
+	Dispatch                   *xmlDispatch      `xml:"dispatch,omitempty"`
+	Schedule                   *xmlSchedule      `xml:"schedule,omitempty"`
+	TimeZone                   string            `xml:"timeZone,omitempty"`
+	Notification               *xmlNotifications `xml:"notification,omitempty"`
+	NotifyAvgDurationThreshold string            `xml:"notifyAvgDurationThreshold,omitempty"`
+	DefaultTab                 string            `xml:"defaultTab,omitempty"`
+	Context                    *xmlContext       `xml:"context,omitempty"`
+	Orchestrator               *xmlOrchestrator  `xml:"orchestrator,omitempty"`
+	Plugins                    xmlElementTree    `xml:"plugins,omitempty"`
+	Sequence                   *xmlSequence      `xml:"sequence,omitempty"`
+	Extra                      []*xmlAnyElement  `xml:",any"`
+}
+
+type xmlLogging struct {
+	Limit       string `xml:"limit,attr,omitempty"`
+	LimitAction string `xml:"limitAction,attr,omitempty"`
+	Status      string `xml:"status,attr,omitempty"`
+}
+
+type xmlRetry struct {
+	Delay string `xml:"delay,attr,omitempty"`
+	Retry string `xml:",chardata"`
+}
+
+type xmlNodeFilters struct {
+	Filter        string `xml:"filter,omitempty"`
+	FilterExclude string `xml:"filterExclude,omitempty"`
+}
+
+type xmlDispatch struct {
+	ThreadCount              string `xml:"threadcount,omitempty"`
+	KeepGoing                bool   `xml:"keepgoing"`
+	ExcludePrecedence        bool   `xml:"excludePrecedence"`
+	RankAttribute            string `xml:"rankAttribute,omitempty"`
+	RankOrder                string `xml:"rankOrder,omitempty"`
+	SuccessOnEmptyNodeFilter bool   `xml:"successOnEmptyNodeFilter,omitempty"`
+}
+
+type xmlSchedule struct {
+	Crontab string           `xml:"crontab,attr,omitempty"`
+	Month   *xmlScheduleAttr `xml:"month,omitempty"`
+	Time    *xmlScheduleTime `xml:"time,omitempty"`
+	Weekday *xmlScheduleAttr `xml:"weekday,omitempty"`
+	Year    *xmlScheduleAttr `xml:"year,omitempty"`
+}
+
+type xmlScheduleAttr struct {
+	Month string `xml:"month,attr,omitempty"`
+	Day   string `xml:"day,attr,omitempty"`
+	Year  string `xml:"year,attr,omitempty"`
+}
+
+type xmlScheduleTime struct {
+	Hour    string `xml:"hour,attr"`
+	Minute  string `xml:"minute,attr"`
+	Seconds string `xml:"seconds,attr,omitempty"`
+}
+
+type xmlNotifications struct {
+	OnSuccess          *xmlNotification `xml:"onsuccess,omitempty"`
+	OnFailure          *xmlNotification `xml:"onfailure,omitempty"`
+	OnStart            *xmlNotification `xml:"onstart,omitempty"`
+	OnAvgDuration      *xmlNotification `xml:"onavgduration,omitempty"`
+	OnRetryableFailure *xmlNotification `xml:"onretryablefailure,omitempty"`
+}
+
+type xmlNotification struct {
+	Email   *xmlEmail    `xml:"email,omitempty"`
+	Webhook *xmlWebhook  `xml:"webhook,omitempty"`
+	Plugins []*xmlPlugin `xml:"plugin"`
+}
+
+type xmlEmail struct {
+	Recipients      string `xml:"recipients,attr"`
+	Subject         string `xml:"subject,attr,omitempty"`
+	AttachLog       bool   `xml:"attachLog,attr,omitempty"`
+	AttachLogInFile bool   `xml:"attachLogInFile,attr,omitempty"`
+	AttachLogInline bool   `xml:"attachLogInline,attr,omitempty"`
+}
+
+type xmlWebhook struct {
+	URLs       string `xml:"urls,attr"`
+	Format     string `xml:"format,attr,omitempty"`
+	HTTPMethod string `xml:"httpMethod,attr,omitempty"`
+}
+
+type xmlPlugin struct {
+	Type          string          `xml:"type,attr"`
+	Configuration xmlPluginConfig `xml:"configuration,omitempty"`
+}
+
+type xmlEntryList struct {
+	Entries []*xmlEntry `xml:"entry"`
+}
+
+type xmlEntry struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:"value,attr"`
+}
+
+type xmlContext struct {
+	Project string      `xml:"project,omitempty"`
+	Options *xmlOptions `xml:"options,omitempty"`
+}
+
+type xmlOptions struct {
+	PreserveOrder bool         `xml:"preserveOrder,attr,omitempty"`
+	Options       []*xmlOption `xml:"option"`
+}
+
+type xmlOption struct {
+	Name                  string `xml:"name,attr"`
+	Type                  string `xml:"type,attr,omitempty"`
+	Value                 string `xml:"value,attr,omitempty"`
+	Values                string `xml:"values,attr,omitempty"`
+	ValuesURL             string `xml:"valuesUrl,attr,omitempty"`
+	ValuesListDelimiter   string `xml:"valuesListDelimiter,attr,omitempty"`
+	SortValues            bool   `xml:"sortValues,attr,omitempty"`
+	Enforced              bool   `xml:"enforcedvalues,attr,omitempty"`
+	Regex                 string `xml:"regex,attr,omitempty"`
+	Required              bool   `xml:"required,attr,omitempty"`
+	Hidden                bool   `xml:"hidden,attr,omitempty"`
+	Secure                bool   `xml:"secure,attr,omitempty"`
+	ValueExposed          bool   `xml:"valueExposed,attr,omitempty"`
+	StoragePath           string `xml:"storagePath,attr,omitempty"`
+	MultiValued           bool   `xml:"multivalued,attr,omitempty"`
+	Delimiter             string `xml:"delimiter,attr,omitempty"`
+	MultiValueAllSelected bool   `xml:"multivalueAllSelected,attr,omitempty"`
+	IsDate                bool   `xml:"isDate,attr,omitempty"`
+	DateFormat            string `xml:"dateFormat,attr,omitempty"`
+	Label                 string `xml:"label,omitempty"`
+	Description           string `xml:"description,omitempty"`
+}
+
+type xmlOrchestrator struct {
+	Type          string        `xml:"type"`
+	Configuration xmlElementMap `xml:"configuration,omitempty"`
+}
+
+type xmlSequence struct {
+	KeepGoing    bool           `xml:"keepgoing,attr"`
+	Strategy     string         `xml:"strategy,attr,omitempty"`
+	Commands     []*xmlCommand  `xml:"command"`
+	PluginConfig xmlElementTree `xml:"pluginConfig,omitempty"`
+}
+
+type xmlCommand struct {
+	KeepGoingOnSuccess      bool                  `xml:"keepgoingOnSuccess,attr,omitempty"`
+	Description             string                `xml:"description,omitempty"`
+	Exec                    string                `xml:"exec,omitempty"`
+	Script                  string                `xml:"script,omitempty"`
+	ScriptFile              string                `xml:"scriptfile,omitempty"`
+	ScriptURL               string                `xml:"scripturl,omitempty"`
+	ScriptArgs              string                `xml:"scriptargs,omitempty"`
+	FileExtension           string                `xml:"fileExtension,omitempty"`
+	ScriptInterpreter       *xmlScriptInterpreter `xml:"scriptInterpreter,omitempty"`
+	ExpandTokenInScriptFile bool                  `xml:"expandTokenInScriptFile,omitempty"`
+	JobRef                  *xmlJobRef            `xml:"jobref,omitempty"`
+	NodeStepPlugin          *xmlPlugin            `xml:"node-step-plugin,omitempty"`
+	StepPlugin              *xmlPlugin            `xml:"step-plugin,omitempty"`
+	ErrorHandler            *xmlCommand           `xml:"errorhandler,omitempty"`
+	Plugins                 *xmlStepPlugins       `xml:"plugins,omitempty"`
+}
+
+type xmlScriptInterpreter struct {
+	ArgsQuoted  bool   `xml:"argsquoted,attr,omitempty"`
+	Interpreter string `xml:",chardata"`
+}
+
+type xmlJobRef struct {
+	Name                string          `xml:"name,attr,omitempty"`
+	Group               string          `xml:"group,attr,omitempty"`
+	Project             string          `xml:"project,attr,omitempty"`
+	NodeStep            bool            `xml:"nodeStep,attr,omitempty"`
+	ImportOptions       bool            `xml:"importOptions,attr,omitempty"`
+	FailOnDisable       bool            `xml:"failOnDisable,attr,omitempty"`
+	ChildNodes          bool            `xml:"childNodes,attr,omitempty"`
+	IgnoreNotifications bool            `xml:"ignoreNotifications,attr,omitempty"`
+	UseName             bool            `xml:"useName,attr,omitempty"`
+	UUID                string          `xml:"uuid,omitempty"`
+	Arg                 *xmlArg         `xml:"arg,omitempty"`
+	NodeFilters         *xmlNodeFilters `xml:"nodefilters,omitempty"`
+	Dispatch            *xmlDispatch    `xml:"dispatch,omitempty"`
+}
+
+type xmlArg struct {
+	Line string `xml:"line,attr"`
+}
+
+type xmlStepPlugins struct {
+	LogFilters []*xmlLogFilter `xml:"LogFilter"`
+}
+
+type xmlLogFilter struct {
+	Type   string        `xml:"type,attr"`
+	Config xmlElementMap `xml:"config,omitempty"`
+}
+
+// xmlElementMap is a map written as one child element per key
+type xmlElementMap map[string]string
+
+func (m xmlElementMap) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	if err := e.EncodeToken(start); err != nil {
+		return err
+	}
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if err := e.EncodeElement(m[k], xml.StartElement{Name: xml.Name{Local: k}}); err != nil {
+			return err
+		}
+	}
+	return e.EncodeToken(start.End())
+}
+
+func (m *xmlElementMap) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	values := xmlElementMap{}
+	for {
+		token, err := d.Token()
+		if err != nil {
+			if err == io.EOF {
+				return io.ErrUnexpectedEOF
+			}
+			return err
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			var v string
+			if err := d.DecodeElement(&v, &t); err != nil {
+				return err
+			}
+			values[t.Name.Local] = v
+		case xml.EndElement:
+			*m = values
+			return nil
+		}
+	}
+}
+
+// xmlPluginConfig is the configuration of a plugin
+// A configuration of only strings is written as key/value entries. Anything else is flagged with
+// data="true" and written as nested elements the same as an xmlElementTree
+type xmlPluginConfig map[string]interface{}
+
+func (c xmlPluginConfig) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	keys := make([]string, 0, len(c))
+	for k := range c {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	entries := &xmlEntryList{}
+	for _, k := range keys {
+		s, ok := c[k].(string)
+		if !ok {
+			start.Attr = append(start.Attr, xml.Attr{Name: xml.Name{Local: "data"}, Value: "true"})
+			return encodeXMLTreeValue(e, start, map[string]interface{}(c))
+		}
+		entries.Entries = append(entries.Entries, &xmlEntry{Key: k, Value: s})
+	}
+	return e.EncodeElement(entries, start)
+}
+
+func (c *xmlPluginConfig) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	for _, a := range start.Attr {
+		if a.Name.Local == "data" && a.Value == "true" {
+			tree := xmlElementTree{}
+			if err := tree.UnmarshalXML(d, start); err != nil {
+				return err
+			}
+			*c = xmlPluginConfig(tree)
+			return nil
+		}
+	}
+	entries := &xmlEntryList{}
+	if err := d.DecodeElement(entries, &start); err != nil {
+		return err
+	}
+	config := xmlPluginConfig{}
+	for _, e := range entries.Entries {
+		config[e.Key] = e.Value
+	}
+	*c = config
+	return nil
+}
+
+// xmlElementTree is a generic nested map written as nested child elements, the way rundeck writes
+// job plugins and workflow plugin config. Maps become elements with a child per key, lists become
+// repeated elements and anything else is the element's text.
+// An element with no children or text is read back as an empty map
+type xmlElementTree map[string]interface{}
+
+func (m xmlElementTree) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return encodeXMLTreeValue(e, start, map[string]interface{}(m))
+}
+
+func encodeXMLTreeValue(e *xml.Encoder, start xml.StartElement, value interface{}) error {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		values := make(map[interface{}]interface{}, len(v))
+		for k, val := range v {
+			values[k] = val
+		}
+		return encodeXMLTreeValue(e, start, values)
+	case map[interface{}]interface{}:
+		if err := e.EncodeToken(start); err != nil {
+			return err
+		}
+		keys := make([]string, 0, len(v))
+		values := make(map[string]interface{}, len(v))
+		for k, val := range v {
+			key := fmt.Sprint(k)
+			keys = append(keys, key)
+			values[key] = val
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			if err := encodeXMLTreeValue(e, xml.StartElement{Name: xml.Name{Local: k}}, values[k]); err != nil {
+				return err
+			}
+		}
+		return e.EncodeToken(start.End())
+	case []interface{}:
+		for _, item := range v {
+			if err := encodeXMLTreeValue(e, start, item); err != nil {
+				return err
+			}
+		}
+		return nil
+	case nil:
+		return e.EncodeElement("", start)
+	default:
+		return e.EncodeElement(fmt.Sprint(v), start)
+	}
+}
+
+func (m *xmlElementTree) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	value, err := decodeXMLTreeValue(d)
+	if err != nil {
+		return err
+	}
+	values := xmlElementTree{}
+	if children, ok := value.(map[interface{}]interface{}); ok {
+		for k, v := range children {
+			values[fmt.Sprint(k)] = v
+		}
+	}
+	*m = values
+	return nil
+}
+
+// decodeXMLTreeValue reads the contents of the element whose start was just read
+func decodeXMLTreeValue(d *xml.Decoder) (interface{}, error) {
+	var text strings.Builder
+	var children map[interface{}]interface{}
+	for {
+		token, err := d.Token()
+		if err != nil {
+			if err == io.EOF {
+				return nil, io.ErrUnexpectedEOF
+			}
+			return nil, err
+		}
+		switch t := token.(type) {
+		case xml.CharData:
+			text.Write(t)
+		case xml.StartElement:
+			child, err := decodeXMLTreeValue(d)
+			if err != nil {
+				return nil, err
+			}
+			if children == nil {
+				children = map[interface{}]interface{}{}
+			}
+			name := t.Name.Local
+			switch existing := children[name].(type) {
+			case nil:
+				children[name] = child
+			case []interface{}:
+				children[name] = append(existing, child)
+			default:
+				children[name] = []interface{}{existing, child}
+			}
+		case xml.EndElement:
+			if children != nil {
+				return children, nil
+			}
+			if strings.TrimSpace(text.String()) == "" {
+				return map[interface{}]interface{}{}, nil
+			}
+			return text.String(), nil
+		}
+	}
+}
+
+// xmlAnyElement is an element the xml types don't know about, read and written as an xmlElementTree value
+type xmlAnyElement struct {
+	Name  string
+	Value interface{}
+}
+
+func (a *xmlAnyElement) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return encodeXMLTreeValue(e, xml.StartElement{Name: xml.Name{Local: a.Name}}, a.Value)
+}
+
+func (a *xmlAnyElement) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	value, err := decodeXMLTreeValue(d)
+	if err != nil {
+		return err
+	}
+	a.Name = start.Name.Local
+	a.Value = value
+	return nil
+}
+
+func parseXMLJobDefinitions(data []byte) ([]*JobDefinition, error) {
+	list := &xmlJobList{}
+	if err := xml.Unmarshal(data, list); err != nil {
+		return nil, err
+	}
+	jobs := make([]*JobDefinition, 0, len(list.Jobs))
+	for _, j := range list.Jobs {
+		jobs = append(jobs, j.definition())
+	}
+	return jobs, nil
+}
+
+func marshalXMLJobDefinitions(jobs []*JobDefinition) ([]byte, error) {
+	list := &xmlJobList{}
+	for _, j := range jobs {
+		x, err := newXMLJob(j)
+		if err != nil {
+			return nil, err
+		}
+		list.Jobs = append(list.Jobs, x)
+	}
+	data, err := xml.MarshalIndent(list, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), append(data, '\n')...), nil
+}
+
+func newXMLJob(j *JobDefinition) (*xmlJob, error) {
+	x := &xmlJob{
+		ID:                         j.ID,
+		UUID:                       j.UUID,
+		Name:                       j.Name,
+		Group:                      j.Group,
+		Description:                j.Description,
+		LogLevel:                   j.LogLevel,
+		ExecutionEnabled:           j.ExecutionEnabled,
+		ScheduleEnabled:            j.ScheduleEnabled,
+		MultipleExecutions:         j.MultipleExecutions,
+		MaxMultipleExecutions:      j.MaxMultipleExecutions,
+		Timeout:                    j.Timeout,
+		NodeFilterEditable:         j.NodeFilterEditable,
+		NodesSelectedByDefault:     j.NodesSelectedByDefault,
+		TimeZone:                   j.TimeZone,
+		NotifyAvgDurationThreshold: j.NotifyAvgDurationThreshold,
+		DefaultTab:                 j.DefaultTab,
+		Schedule:                   newXMLSchedule(j.Schedule),
+		Notification:               newXMLNotifications(j.Notification),
+		Plugins:                    j.Plugins,
+	}
+	extraKeys := make([]string, 0, len(j.Extra))
+	for k := range j.Extra {
+		extraKeys = append(extraKeys, k)
+	}
+	sort.Strings(extraKeys)
+	for _, k := range extraKeys {
+		x.Extra = append(x.Extra, &xmlAnyElement{Name: k, Value: j.Extra[k]})
+	}
+	if j.LogLimit != "" || j.LogLimitAction != "" || j.LogLimitStatus != "" {
+		x.Logging = &xmlLogging{Limit: j.LogLimit, LimitAction: j.LogLimitAction, Status: j.LogLimitStatus}
+	}
+	if j.Retry != nil {
+		x.Retry = &xmlRetry{Retry: j.Retry.Retry, Delay: j.Retry.Delay}
+	}
+	x.NodeFilters, x.Dispatch = newXMLNodeFilters(j.NodeFilters)
+	if j.Project != "" || len(j.Options) > 0 {
+		x.Context = &xmlContext{Project: j.Project}
+		if len(j.Options) > 0 {
+			x.Context.Options = &xmlOptions{PreserveOrder: true}
+			for _, o := range j.Options {
+				x.Context.Options.Options = append(x.Context.Options.Options, newXMLOption(o))
+			}
+		}
+	}
+	if j.Orchestrator != nil {
+		x.Orchestrator = &xmlOrchestrator{Type: j.Orchestrator.Type, Configuration: j.Orchestrator.Configuration}
+	}
+	if j.Sequence != nil {
+		x.Sequence = &xmlSequence{KeepGoing: j.Sequence.KeepGoing, Strategy: j.Sequence.Strategy, PluginConfig: j.Sequence.PluginConfig}
+		for _, step := range j.Sequence.Commands {
+			x.Sequence.Commands = append(x.Sequence.Commands, newXMLCommand(step))
+		}
+	}
+	return x, nil
+}
+
+func (x *xmlJob) definition() *JobDefinition {
+	j := &JobDefinition{
+		ID:                         x.ID,
+		UUID:                       x.UUID,
+		Name:                       x.Name,
+		Group:                      x.Group,
+		Description:                x.Description,
+		LogLevel:                   x.LogLevel,
+		ExecutionEnabled:           x.ExecutionEnabled,
+		ScheduleEnabled:            x.ScheduleEnabled,
+		MultipleExecutions:         x.MultipleExecutions,
+		MaxMultipleExecutions:      x.MaxMultipleExecutions,
+		Timeout:                    x.Timeout,
+		NodeFilterEditable:         x.NodeFilterEditable,
+		NodesSelectedByDefault:     x.NodesSelectedByDefault,
+		TimeZone:                   x.TimeZone,
+		NotifyAvgDurationThreshold: x.NotifyAvgDurationThreshold,
+		DefaultTab:                 x.DefaultTab,
+		NodeFilters:                x.NodeFilters.definition(x.Dispatch),
+		Schedule:                   x.Schedule.definition(),
+		Notification:               x.Notification.definition(),
+	}
+	if len(x.Plugins) > 0 {
+		j.Plugins = x.Plugins
+	}
+	for _, a := range x.Extra {
+		if j.Extra == nil {
+			j.Extra = map[string]interface{}{}
+		}
+		switch existing := j.Extra[a.Name].(type) {
+		case nil:
+			j.Extra[a.Name] = a.Value
+		case []interface{}:
+			j.Extra[a.Name] = append(existing, a.Value)
+		default:
+			j.Extra[a.Name] = []interface{}{existing, a.Value}
+		}
+	}
+	if x.Logging != nil {
+		j.LogLimit = x.Logging.Limit
+		j.LogLimitAction = x.Logging.LimitAction
+		j.LogLimitStatus = x.Logging.Status
+	}
+	if x.Retry != nil {
+		j.Retry = &JobRetry{Retry: strings.TrimSpace(x.Retry.Retry), Delay: x.Retry.Delay}
+	}
+	if x.Context != nil {
+		j.Project = x.Context.Project
+		if x.Context.Options != nil {
+			for _, o := range x.Context.Options.Options {
+				j.Options = append(j.Options, o.definition())
+			}
+		}
+	}
+	if x.Orchestrator != nil {
+		j.Orchestrator = &JobOrchestrator{Type: x.Orchestrator.Type, Configuration: x.Orchestrator.Configuration}
+	}
+	if x.Sequence != nil {
+		j.Sequence = &JobSequence{KeepGoing: x.Sequence.KeepGoing, Strategy: x.Sequence.Strategy, Commands: []*JobStep{}}
+		for _, c := range x.Sequence.Commands {
+			j.Sequence.Commands = append(j.Sequence.Commands, c.definition())
+		}
+		if len(x.Sequence.PluginConfig) > 0 {
+			j.Sequence.PluginConfig = x.Sequence.PluginConfig
+		}
+	}
+	return j
+}
+
+func newXMLNodeFilters(f *JobNodeFilters) (*xmlNodeFilters, *xmlDispatch) {
+	if f == nil {
+		return nil, nil
+	}
+	var filters *xmlNodeFilters
+	if f.Filter != "" || f.FilterExclude != "" {
+		filters = &xmlNodeFilters{Filter: f.Filter, FilterExclude: f.FilterExclude}
+	}
+	var dispatch *xmlDispatch
+	if d := f.Dispatch; d != nil {
+		dispatch = &xmlDispatch{
+			ThreadCount:              d.ThreadCount,
+			KeepGoing:                d.KeepGoing,
+			ExcludePrecedence:        d.ExcludePrecedence,
+			RankAttribute:            d.RankAttribute,
+			RankOrder:                d.RankOrder,
+			SuccessOnEmptyNodeFilter: d.SuccessOnEmptyNodeFilter,
+		}
+	}
+	return filters, dispatch
+}
+
+func (x *xmlNodeFilters) definition(d *xmlDispatch) *JobNodeFilters {
+	if x == nil && d == nil {
+		return nil
+	}
+	f := &JobNodeFilters{}
+	if x != nil {
+		f.Filter = x.Filter
+		f.FilterExclude = x.FilterExclude
+	}
+	if d != nil {
+		f.Dispatch = &JobDispatch{
+			ThreadCount:              d.ThreadCount,
+			KeepGoing:                d.KeepGoing,
+			ExcludePrecedence:        d.ExcludePrecedence,
+			RankAttribute:            d.RankAttribute,
+			RankOrder:                d.RankOrder,
+			SuccessOnEmptyNodeFilter: d.SuccessOnEmptyNodeFilter,
+		}
+	}
+	return f
+}
+
+func newXMLSchedule(s *JobSchedule) *xmlSchedule {
+	if s == nil {
+		return nil
+	}
+	x := &xmlSchedule{Crontab: s.Crontab}
+	if s.Month != "" || s.DayOfMonth != nil {
+		x.Month = &xmlScheduleAttr{Month: s.Month}
+		if s.DayOfMonth != nil {
+			x.Month.Day = s.DayOfMonth.Day
+		}
+	}
+	if s.Weekday != nil {
+		x.Weekday = &xmlScheduleAttr{Day: s.Weekday.Day}
+	}
+	if s.Time != nil {
+		x.Time = &xmlScheduleTime{Hour: s.Time.Hour, Minute: s.Time.Minute, Seconds: s.Time.Seconds}
+	}
+	if s.Year != "" {
+		x.Year = &xmlScheduleAttr{Year: s.Year}
+	}
+	return x
+}
+
+func (x *xmlSchedule) definition() *JobSchedule {
+	if x == nil {
+		return nil
+	}
+	s := &JobSchedule{Crontab: x.Crontab}
+	if x.Month != nil {
+		s.Month = x.Month.Month
+		if x.Month.Day != "" {
+			s.DayOfMonth = &JobScheduleDay{Day: x.Month.Day}
+		}
+	}
+	if x.Weekday != nil {
+		s.Weekday = &JobScheduleDay{Day: x.Weekday.Day}
+	}
+	if x.Time != nil {
+		s.Time = &JobScheduleTime{Hour: x.Time.Hour, Minute: x.Time.Minute, Seconds: x.Time.Seconds}
+	}
+	if x.Year != nil {
+		s.Year = x.Year.Year
+	}
+	return s
+}
+
+func newXMLNotifications(n *JobNotifications) *xmlNotifications {
+	if n == nil {
+		return nil
+	}
+	return &xmlNotifications{
+		OnSuccess:          newXMLNotification(n.OnSuccess),
+		OnFailure:          newXMLNotification(n.OnFailure),
+		OnStart:            newXMLNotification(n.OnStart),
+		OnAvgDuration:      newXMLNotification(n.OnAvgDuration),
+		OnRetryableFailure: newXMLNotification(n.OnRetryableFailure),
+	}
+}
+
+func (x *xmlNotifications) definition() *JobNotifications {
+	if x == nil {
+		return nil
+	}
+	return &JobNotifications{
+		OnSuccess:          x.OnSuccess.definition(),
+		OnFailure:          x.OnFailure.definition(),
+		OnStart:            x.OnStart.definition(),
+		OnAvgDuration:      x.OnAvgDuration.definition(),
+		OnRetryableFailure: x.OnRetryableFailure.definition(),
+	}
+}
+
+func newXMLNotification(n *JobNotification) *xmlNotification {
+	if n == nil {
+		return nil
+	}
+	x := &xmlNotification{}
+	if e := n.Email; e != nil {
+		x.Email = &xmlEmail{
+			Recipients:      e.Recipients,
+			Subject:         e.Subject,
+			AttachLog:       e.AttachLog,
+			AttachLogInFile: e.AttachLogInFile,
+			AttachLogInline: e.AttachLogInline,
+		}
+	}
+	if n.URLs != "" {
+		x.Webhook = &xmlWebhook{URLs: n.URLs, Format: n.Format, HTTPMethod: n.HTTPMethod}
+	}
+	for _, p := range n.Plugins {
+		x.Plugins = append(x.Plugins, newXMLPlugin(p.Type, p.Configuration))
+	}
+	return x
+}
+
+func (x *xmlNotification) definition() *JobNotification {
+	if x == nil {
+		return nil
+	}
+	n := &JobNotification{}
+	if e := x.Email; e != nil {
+		n.Email = &JobEmailNotification{
+			Recipients:      e.Recipients,
+			Subject:         e.Subject,
+			AttachLog:       e.AttachLog,
+			AttachLogInFile: e.AttachLogInFile,
+			AttachLogInline: e.AttachLogInline,
+		}
+	}
+	if x.Webhook != nil {
+		n.URLs = x.Webhook.URLs
+		n.Format = x.Webhook.Format
+		n.HTTPMethod = x.Webhook.HTTPMethod
+	}
+	for _, p := range x.Plugins {
+		n.Plugins = append(n.Plugins, &JobPlugin{Type: p.Type, Configuration: p.configuration()})
+	}
+	return n
+}
+
+func newXMLPlugin(pluginType string, config map[string]interface{}) *xmlPlugin {
+	return &xmlPlugin{Type: pluginType, Configuration: config}
+}
+
+func (x *xmlPlugin) configuration() map[string]interface{} {
+	if len(x.Configuration) == 0 {
+		return nil
+	}
+	return x.Configuration
+}
+
+func newXMLOption(o *JobOptionDefinition) *xmlOption {
+	x := &xmlOption{
+		Name:                  o.Name,
+		Type:                  o.Type,
+		Value:                 o.Value,
+		ValuesURL:             o.ValuesURL,
+		ValuesListDelimiter:   o.ValuesListDelimiter,
+		SortValues:            o.SortValues,
+		Enforced:              o.Enforced,
+		Regex:                 o.Regex,
+		Required:              o.Required,
+		Hidden:                o.Hidden,
+		Secure:                o.Secure,
+		ValueExposed:          o.ValueExposed,
+		StoragePath:           o.StoragePath,
+		MultiValued:           o.MultiValued,
+		Delimiter:             o.Delimiter,
+		MultiValueAllSelected: o.MultiValueAllSelected,
+		IsDate:                o.IsDate,
+		DateFormat:            o.DateFormat,
+		Label:                 o.Label,
+		Description:           o.Description,
+	}
+	if len(o.Values) > 0 {
+		x.Values = strings.Join(o.Values, optionValuesDelimiter(o.ValuesListDelimiter))
+	}
+	return x
+}
+
+func (x *xmlOption) definition() *JobOptionDefinition {
+	o := &JobOptionDefinition{
+		Name:                  x.Name,
+		Type:                  x.Type,
+		Value:                 x.Value,
+		ValuesURL:             x.ValuesURL,
+		ValuesListDelimiter:   x.ValuesListDelimiter,
+		SortValues:            x.SortValues,
+		Enforced:              x.Enforced,
+		Regex:                 x.Regex,
+		Required:              x.Required,
+		Hidden:                x.Hidden,
+		Secure:                x.Secure,
+		ValueExposed:          x.ValueExposed,
+		StoragePath:           x.StoragePath,
+		MultiValued:           x.MultiValued,
+		Delimiter:             x.Delimiter,
+		MultiValueAllSelected: x.MultiValueAllSelected,
+		IsDate:                x.IsDate,
+		DateFormat:            x.DateFormat,
+		Label:                 x.Label,
+		Description:           x.Description,
+	}
+	if x.Values != "" {
+		o.Values = strings.Split(x.Values, optionValuesDelimiter(x.ValuesListDelimiter))
+	}
+	return o
+}
+
+// optionValuesDelimiter is the delimiter between option values in xml
+func optionValuesDelimiter(d string) string {
+	if d == "" {
+		return ","
+	}
+	return d
+}
+
+func newXMLCommand(s *JobStep) *xmlCommand {
+	x := &xmlCommand{
+		KeepGoingOnSuccess:      s.KeepGoingOnSuccess,
+		Description:             s.Description,
+		Exec:                    s.Exec,
+		Script:                  s.Script,
+		ScriptFile:              s.ScriptFile,
+		ScriptURL:               s.ScriptURL,
+		ScriptArgs:              s.Args,
+		FileExtension:           s.FileExtension,
+		ExpandTokenInScriptFile: s.ExpandTokenInScriptFile,
+	}
+	if s.ScriptInterpreter != "" || s.InterpreterArgsQuoted {
+		x.ScriptInterpreter = &xmlScriptInterpreter{Interpreter: s.ScriptInterpreter, ArgsQuoted: s.InterpreterArgsQuoted}
+	}
+	if r := s.JobRef; r != nil {
+		x.JobRef = &xmlJobRef{
+			Name:                r.Name,
+			Group:               r.Group,
+			Project:             r.Project,
+			NodeStep:            r.NodeStep,
+			ImportOptions:       r.ImportOptions,
+			FailOnDisable:       r.FailOnDisable,
+			ChildNodes:          r.ChildNodes,
+			IgnoreNotifications: r.IgnoreNotifications,
+			UseName:             r.UseName,
+			UUID:                r.UUID,
+		}
+		if r.Args != "" {
+			x.JobRef.Arg = &xmlArg{Line: r.Args}
+		}
+		x.JobRef.NodeFilters, x.JobRef.Dispatch = newXMLNodeFilters(r.NodeFilters)
+	}
+	if s.Type != "" {
+		if s.NodeStep {
+			x.NodeStepPlugin = newXMLPlugin(s.Type, s.Configuration)
+		} else {
+			x.StepPlugin = newXMLPlugin(s.Type, s.Configuration)
+		}
+	}
+	if s.ErrorHandler != nil {
+		x.ErrorHandler = newXMLCommand(s.ErrorHandler)
+	}
+	if s.Plugins != nil {
+		x.Plugins = &xmlStepPlugins{}
+		for _, f := range s.Plugins.LogFilter {
+			x.Plugins.LogFilters = append(x.Plugins.LogFilters, &xmlLogFilter{Type: f.Type, Config: f.Config})
+		}
+	}
+	return x
+}
+
+func (x *xmlCommand) definition() *JobStep {
+	s := &JobStep{
+		KeepGoingOnSuccess:      x.KeepGoingOnSuccess,
+		Description:             x.Description,
+		Exec:                    x.Exec,
+		Script:                  x.Script,
+		ScriptFile:              x.ScriptFile,
+		ScriptURL:               x.ScriptURL,
+		Args:                    x.ScriptArgs,
+		FileExtension:           x.FileExtension,
+		ExpandTokenInScriptFile: x.ExpandTokenInScriptFile,
+	}
+	if x.ScriptInterpreter != nil {
+		s.ScriptInterpreter = x.ScriptInterpreter.Interpreter
+		s.InterpreterArgsQuoted = x.ScriptInterpreter.ArgsQuoted
+	}
+	if r := x.JobRef; r != nil {
+		s.JobRef = &JobReference{
+			Name:                r.Name,
+			Group:               r.Group,
+			Project:             r.Project,
+			NodeStep:            r.NodeStep,
+			ImportOptions:       r.ImportOptions,
+			FailOnDisable:       r.FailOnDisable,
+			ChildNodes:          r.ChildNodes,
+			IgnoreNotifications: r.IgnoreNotifications,
+			UseName:             r.UseName,
+			UUID:                r.UUID,
+			NodeFilters:         r.NodeFilters.definition(r.Dispatch),
+		}
+		if r.Arg != nil {
+			s.JobRef.Args = r.Arg.Line
+		}
+	}
+	if x.NodeStepPlugin != nil {
+		s.Type = x.NodeStepPlugin.Type
+		s.NodeStep = true
+		s.Configuration = x.NodeStepPlugin.configuration()
+	}
+	if x.StepPlugin != nil {
+		s.Type = x.StepPlugin.Type
+		s.Configuration = x.StepPlugin.configuration()
+	}
+	if x.ErrorHandler != nil {
+		s.ErrorHandler = x.ErrorHandler.definition()
+	}
+	if x.Plugins != nil {
+		s.Plugins = &JobStepPlugins{}
+		for _, f := range x.Plugins.LogFilters {
+			s.Plugins.LogFilter = append(s.Plugins.LogFilter, &JobLogFilter{Type: f.Type, Config: f.Config})
+		}
+	}
+	return s
+}
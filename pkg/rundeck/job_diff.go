@@ -0,0 +1,316 @@
+package rundeck
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	multierror "github.com/hashicorp/go-multierror"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// JobDiffKind is the kind of difference between two job definitions
+type JobDiffKind string
+
+const (
+	// JobDiffAdded is a setting that is only in the new definition
+	JobDiffAdded JobDiffKind = "added"
+	// JobDiffRemoved is a setting that is only in the old definition
+	JobDiffRemoved JobDiffKind = "removed"
+	// JobDiffChanged is a setting with a different value in each definition
+	JobDiffChanged JobDiffKind = "changed"
+)
+
+// JobDefinitionChange is a single difference between two job definitions
+// Path names the setting using the yaml field names. Options are keyed by name and steps by their
+// position starting at 1. e.g. options[env].required or steps[2].errorhandler.exec
+type JobDefinitionChange struct {
+	Kind JobDiffKind
+	Path string
+	From string
+	To   string
+}
+
+// JobDefinitionDiff is every difference between two job definitions
+type JobDefinitionDiff []*JobDefinitionChange
+
+// jobDiffIgnored are top level fields that differ between copies of the same job
+var jobDiffIgnored = []string{"id", "uuid"}
+
+// jobDiffCommaLists are fields holding comma separated lists where order doesn't matter
+var jobDiffCommaLists = []string{"recipients", "urls"}
+
+// DiffJobDefinitions compares two job definitions setting by setting
+// Job ids and uuids are ignored as are the order of options, map keys and email recipients.
+// Unset settings and settings set to their zero value are treated the same, except for executionEnabled
+// and scheduleEnabled which rundeck enables when they aren't set
+func DiffJobDefinitions(from, to *JobDefinition) (JobDefinitionDiff, error) {
+	a, err := jobDiffTree(from)
+	if err != nil {
+		return nil, err
+	}
+	b, err := jobDiffTree(to)
+	if err != nil {
+		return nil, err
+	}
+	for _, k := range jobDiffIgnored {
+		delete(a, k)
+		delete(b, k)
+	}
+	diff := JobDefinitionDiff{}
+	diff.compare("", a, b)
+	return diff, nil
+}
+
+// jobDiffTree converts a definition to generic maps and lists keyed the way the diff paths are
+func jobDiffTree(job *JobDefinition) (map[string]interface{}, error) {
+	if job == nil {
+		return map[string]interface{}{}, nil
+	}
+	data, err := yaml.Marshal(job)
+	if err != nil {
+		return nil, &MarshalError{msg: multierror.Append(errEncoding, err).Error()}
+	}
+	var raw map[interface{}]interface{}
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, &UnmarshalError{msg: multierror.Append(errDecoding, err).Error()}
+	}
+	tree := jobDiffNormalize(raw).(map[string]interface{})
+	// disabling a job is a change rather than the removal of a setting so these are compared as text
+	tree["executionEnabled"] = strconv.FormatBool(job.IsExecutionEnabled())
+	tree["scheduleEnabled"] = strconv.FormatBool(job.IsScheduleEnabled())
+	if opts, ok := tree["options"].([]interface{}); ok {
+		tree["options"] = jobDiffKeyed(opts, "name")
+	}
+	if seq, ok := tree["sequence"].(map[string]interface{}); ok {
+		if steps, ok := seq["commands"].([]interface{}); ok {
+			tree["steps"] = steps
+			delete(seq, "commands")
+		}
+	}
+	return tree, nil
+}
+
+// jobDiffNormalize turns yaml maps into string keyed maps and puts comma separated lists in order
+func jobDiffNormalize(v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[interface{}]interface{}:
+		m := map[string]interface{}{}
+		for k, val := range t {
+			key := fmt.Sprint(k)
+			m[key] = jobDiffNormalize(val)
+			if s, ok := m[key].(string); ok && stringInSlice(key, jobDiffCommaLists) {
+				parts := strings.Split(s, ",")
+				for i := range parts {
+					parts[i] = strings.TrimSpace(parts[i])
+				}
+				sort.Strings(parts)
+				m[key] = strings.Join(parts, ",")
+			}
+		}
+		return m
+	case []interface{}:
+		l := make([]interface{}, len(t))
+		for i, val := range t {
+			l[i] = jobDiffNormalize(val)
+		}
+		return l
+	}
+	return v
+}
+
+// jobDiffKeyed turns a list of maps into a map keyed by a field so that order is ignored
+// entries without the field, or with a repeated value, keep their position as the key
+func jobDiffKeyed(list []interface{}, field string) map[string]interface{} {
+	m := map[string]interface{}{}
+	for i, item := range list {
+		key := fmt.Sprintf("#%d", i+1)
+		if entry, ok := item.(map[string]interface{}); ok {
+			if name, ok := entry[field].(string); ok && name != "" {
+				if _, dupe := m[name]; !dupe {
+					key = name
+				}
+			}
+		}
+		m[key] = item
+	}
+	return m
+}
+
+func (d *JobDefinitionDiff) compare(path string, a, b interface{}) {
+	aZero, bZero := jobDiffZero(a), jobDiffZero(b)
+	// options and steps are always listed one at a time even when the other side has none
+	if path == "options" || path == "steps" {
+		a, b = jobDiffEmptyAs(a, b), jobDiffEmptyAs(b, a)
+		aZero, bZero = aZero && bZero, aZero && bZero
+	}
+	switch {
+	case aZero && bZero:
+		return
+	case aZero:
+		*d = append(*d, &JobDefinitionChange{Kind: JobDiffAdded, Path: path, To: jobDiffString(b)})
+		return
+	case bZero:
+		*d = append(*d, &JobDefinitionChange{Kind: JobDiffRemoved, Path: path, From: jobDiffString(a)})
+		return
+	}
+	am, aIsMap := a.(map[string]interface{})
+	bm, bIsMap := b.(map[string]interface{})
+	if aIsMap && bIsMap {
+		keys := map[string]bool{}
+		for k := range am {
+			keys[k] = true
+		}
+		for k := range bm {
+			keys[k] = true
+		}
+		sorted := make([]string, 0, len(keys))
+		for k := range keys {
+			sorted = append(sorted, k)
+		}
+		sort.Strings(sorted)
+		for _, k := range sorted {
+			d.compare(jobDiffPath(path, k, path == "options"), am[k], bm[k])
+		}
+		return
+	}
+	al, aIsList := a.([]interface{})
+	bl, bIsList := b.([]interface{})
+	if aIsList && bIsList && !(jobDiffScalars(al) && jobDiffScalars(bl)) {
+		for i := 0; i < len(al) || i < len(bl); i++ {
+			var av, bv interface{}
+			if i < len(al) {
+				av = al[i]
+			}
+			if i < len(bl) {
+				bv = bl[i]
+			}
+			d.compare(fmt.Sprintf("%s[%d]", path, i+1), av, bv)
+		}
+		return
+	}
+	if as, bs := jobDiffString(a), jobDiffString(b); as != bs {
+		*d = append(*d, &JobDefinitionChange{Kind: JobDiffChanged, Path: path, From: as, To: bs})
+	}
+}
+
+// jobDiffEmptyAs returns an empty value of the same type as other when v isn't set
+func jobDiffEmptyAs(v, other interface{}) interface{} {
+	if v != nil {
+		return v
+	}
+	switch other.(type) {
+	case map[string]interface{}:
+		return map[string]interface{}{}
+	case []interface{}:
+		return []interface{}{}
+	}
+	return v
+}
+
+func jobDiffPath(parent, key string, indexed bool) string {
+	switch {
+	case indexed:
+		return fmt.Sprintf("%s[%s]", parent, key)
+	case parent == "":
+		return key
+	}
+	return parent + "." + key
+}
+
+// jobDiffZero reports if a value is the same as not being set
+func jobDiffZero(v interface{}) bool {
+	switch t := v.(type) {
+	case nil:
+		return true
+	case string:
+		return t == ""
+	case bool:
+		return !t
+	case int:
+		return t == 0
+	case map[string]interface{}:
+		for _, val := range t {
+			if !jobDiffZero(val) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		return len(t) == 0
+	}
+	return false
+}
+
+// jobDiffScalars reports if a list only holds plain values so it can be compared as a whole
+func jobDiffScalars(l []interface{}) bool {
+	for _, v := range l {
+		switch v.(type) {
+		case map[string]interface{}, []interface{}:
+			return false
+		}
+	}
+	return true
+}
+
+// jobDiffString formats a value for display. Lists of values are joined and anything else is shown as yaml
+func jobDiffString(v interface{}) string {
+	switch t := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return t
+	case []interface{}:
+		if jobDiffScalars(t) {
+			parts := make([]string, len(t))
+			for i, p := range t {
+				parts[i] = fmt.Sprint(p)
+			}
+			return strings.Join(parts, ",")
+		}
+	case map[string]interface{}:
+	default:
+		return fmt.Sprint(t)
+	}
+	data, err := yaml.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// DiffJobDefinition compares the definition of a job on the server with a local definition
+// Changes are from the server version to the local one
+func (c *Client) DiffJobDefinition(id string, job *JobDefinition) (JobDefinitionDiff, error) {
+	return c.DiffJobDefinitionContext(context.Background(), id, job)
+}
+
+// DiffJobDefinitionContext compares the definition of a job on the server with a local definition
+func (c *Client) DiffJobDefinitionContext(ctx context.Context, id string, job *JobDefinition) (JobDefinitionDiff, error) {
+	server, err := c.ExportJobDefinitionContext(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return DiffJobDefinitions(server, job)
+}
+
+// DiffJobs compares a job with another job. other can be this client or a client for another server
+// Changes are from this job to the other job
+func (c *Client) DiffJobs(id string, other *Client, otherID string) (JobDefinitionDiff, error) {
+	return c.DiffJobsContext(context.Background(), id, other, otherID)
+}
+
+// DiffJobsContext compares a job with another job. other can be this client or a client for another server
+func (c *Client) DiffJobsContext(ctx context.Context, id string, other *Client, otherID string) (JobDefinitionDiff, error) {
+	from, err := c.ExportJobDefinitionContext(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	to, err := other.ExportJobDefinitionContext(ctx, otherID)
+	if err != nil {
+		return nil, err
+	}
+	return DiffJobDefinitions(from, to)
+}
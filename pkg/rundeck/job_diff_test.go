@@ -0,0 +1,154 @@
+package rundeck
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	yaml "gopkg.in/yaml.v2"
+)
+
+func testDiffJob() *JobDefinition {
+	job, _ := NewJobBuilder("deploy").
+		Group("app").
+		UUID("uuid-1").
+		Option("env", JobOptionRequired(), JobOptionValues(true, "dev", "prod")).
+		Option("version", JobOptionDefault("latest")).
+		Command("echo ${option.env}").
+		Script("#!/bin/sh\nexit 0", StepArgs("-x")).
+		NotifyEmail(NotifyOnFailure, "ops@example.com,dev@example.com", "deploy failed", false).
+		Build()
+	return job
+}
+
+func testDiffPaths(diff JobDefinitionDiff) []string {
+	paths := []string{}
+	for _, c := range diff {
+		paths = append(paths, string(c.Kind)+" "+c.Path)
+	}
+	return paths
+}
+
+func TestDiffJobDefinitionsEqual(t *testing.T) {
+	from := testDiffJob()
+	to := testDiffJob()
+	to.UUID = "uuid-2"
+	to.ID = "uuid-2"
+	to.Options[0], to.Options[1] = to.Options[1], to.Options[0]
+	to.Notification.OnFailure.Email.Recipients = "dev@example.com, ops@example.com"
+	diff, err := DiffJobDefinitions(from, to)
+	require.NoError(t, err)
+	require.Empty(t, diff)
+}
+
+func TestDiffJobDefinitionsAcrossFormats(t *testing.T) {
+	from := testDiffJob()
+	data, err := MarshalJobDefinitions([]*JobDefinition{from}, JobFormatXML)
+	require.NoError(t, err)
+	jobs, err := ParseJobDefinitions(data, JobFormatXML)
+	require.NoError(t, err)
+	diff, err := DiffJobDefinitions(from, jobs[0])
+	require.NoError(t, err)
+	require.Empty(t, diff)
+}
+
+func TestDiffJobDefinitions(t *testing.T) {
+	from := testDiffJob()
+	to := testDiffJob()
+	to.Description = "deploys the app"
+	to.Options[0].Required = false
+	to.Options = append(to.Options[:1], &JobOptionDefinition{Name: "region", Value: "us-east-1"})
+	to.Sequence.Commands[1].Args = "-e"
+	to.Sequence.Commands = append(to.Sequence.Commands, &JobStep{Exec: "uptime"})
+	to.Sequence.KeepGoing = true
+
+	diff, err := DiffJobDefinitions(from, to)
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"added description",
+		"removed options[env].required",
+		"added options[region]",
+		"removed options[version]",
+		"added sequence.keepgoing",
+		"changed steps[2].args",
+		"added steps[3]",
+	}, testDiffPaths(diff))
+	require.Equal(t, "-x", diff[5].From)
+	require.Equal(t, "-e", diff[5].To)
+	require.Equal(t, "exec: uptime", diff[6].To)
+	require.Contains(t, diff[2].To, "name: region")
+}
+
+func TestDiffJobDefinitionsEnabled(t *testing.T) {
+	from, err := NewJobBuilder("deploy").Command("uptime").Build()
+	require.NoError(t, err)
+	jobs, err := ParseJobDefinitions([]byte(`- name: deploy
+  loglevel: INFO
+  sequence:
+    strategy: node-first
+    commands:
+    - exec: uptime
+`), JobFormatYAML)
+	require.NoError(t, err)
+	diff, err := DiffJobDefinitions(from, jobs[0])
+	require.NoError(t, err)
+	require.Empty(t, diff)
+
+	to, err := NewJobBuilderFrom(from).Enabled(false, true).Build()
+	require.NoError(t, err)
+	diff, err = DiffJobDefinitions(from, to)
+	require.NoError(t, err)
+	require.Equal(t, []string{"changed executionEnabled"}, testDiffPaths(diff))
+	require.Equal(t, "true", diff[0].From)
+	require.Equal(t, "false", diff[0].To)
+}
+
+func TestDiffJobDefinitionsNoOptions(t *testing.T) {
+	from, err := NewJobBuilder("deploy").Command("uptime").Build()
+	require.NoError(t, err)
+	to := testDiffJob()
+	to.Name = "deploy"
+	to.Group = ""
+	to.Notification = nil
+	to.Sequence.Commands = to.Sequence.Commands[:1]
+	to.Sequence.Commands[0].Exec = "uptime"
+	diff, err := DiffJobDefinitions(from, to)
+	require.NoError(t, err)
+	require.Equal(t, []string{"added options[env]", "added options[version]"}, testDiffPaths(diff))
+}
+
+func TestDiffJobDefinition(t *testing.T) {
+	server := testDiffJob()
+	data, err := yaml.Marshal([]*JobDefinition{server})
+	require.NoError(t, err)
+	client, srv, cErr := newTestRundeckClientWithHandler(func(w http.ResponseWriter, r *http.Request) {
+		require.True(t, strings.HasSuffix(r.URL.Path, "/job/uuid-1"))
+		w.Header().Set("Content-Type", "application/yaml")
+		_, _ = w.Write(data)
+	})
+	defer srv.Close()
+	require.NoError(t, cErr)
+	local := testDiffJob()
+	local.Sequence.Commands[0].Exec = "echo deploying ${option.env}"
+	diff, err := client.DiffJobDefinition("uuid-1", local)
+	require.NoError(t, err)
+	require.Len(t, diff, 1)
+	require.Equal(t, "steps[1].exec", diff[0].Path)
+	require.Equal(t, "echo ${option.env}", diff[0].From)
+
+	diff, err = client.DiffJobs("uuid-1", client, "uuid-1")
+	require.NoError(t, err)
+	require.Empty(t, diff)
+}
+
+func TestDiffJobDefinitionHTTPError(t *testing.T) {
+	client, server, _ := newTestRundeckClient([]byte(""), "application/yaml", 500)
+	defer server.Close()
+	diff, err := client.DiffJobDefinition("uuid-1", testDiffJob())
+	require.Error(t, err)
+	require.Nil(t, diff)
+	diff, err = client.DiffJobs("uuid-1", client, "uuid-2")
+	require.Error(t, err)
+	require.Nil(t, diff)
+}
@@ -1,6 +1,7 @@
 package rundeck
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"io"
@@ -50,6 +51,11 @@ func ImportUUID(f string) JobImportOption {
 // ImportJob imports a job
 // http://rundeck.org/docs/api/index.html#importing-jobs
 func (c *Client) ImportJob(project string, data io.Reader, opt ...JobImportOption) (*JobImportResult, error) {
+	return c.ImportJobContext(context.Background(), project, data, opt...)
+}
+
+// ImportJobContext imports a job
+func (c *Client) ImportJobContext(ctx context.Context, project string, data io.Reader, opt ...JobImportOption) (*JobImportResult, error) {
 	if err := c.checkRequiredAPIVersion(responses.ImportedJobResponse{}); err != nil {
 		return nil, err
 	}
@@ -73,7 +79,7 @@ func (c *Client) ImportJob(project string, data io.Reader, opt ...JobImportOptio
 		opts["uuidOption"] = importDef.UUIDOption
 	}
 
-	res, postErr := c.httpPost("project/"+project+"/jobs/import",
+	res, postErr := c.httpPost(ctx, "project/"+project+"/jobs/import",
 		withBody(data),
 		contentType("application/"+importDef.Format),
 		queryParams(opts),
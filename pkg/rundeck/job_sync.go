@@ -0,0 +1,265 @@
+package rundeck
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	multierror "github.com/hashicorp/go-multierror"
+)
+
+// JobSyncAction is what applying a sync plan does to a job
+type JobSyncAction string
+
+const (
+	// JobSyncCreate is a job that only exists locally
+	JobSyncCreate JobSyncAction = "create"
+	// JobSyncUpdate is a job that exists locally and on the server
+	JobSyncUpdate JobSyncAction = "update"
+	// JobSyncDelete is a job that only exists on the server
+	JobSyncDelete JobSyncAction = "delete"
+)
+
+// JobDefinitionFile is a job definition along with the file it was read from
+type JobDefinitionFile struct {
+	Path string
+	Job  *JobDefinition
+}
+
+// JobSyncChange is a single job in a sync plan
+type JobSyncChange struct {
+	Action JobSyncAction
+	// ID is the server job id for updates and deletes and the definition uuid (if any) for creates
+	ID    string
+	Name  string
+	Group string
+	// Source is the file the definition was read from. It is empty for deletes
+	Source     string
+	Definition *JobDefinition
+}
+
+// JobSyncPlan is the set of changes needed to make a project's jobs match a set of definitions
+type JobSyncPlan struct {
+	Project string
+	Create  []*JobSyncChange
+	Update  []*JobSyncChange
+	Delete  []*JobSyncChange
+}
+
+// Changes returns every change in the plan in the order they are applied
+func (p *JobSyncPlan) Changes() []*JobSyncChange {
+	var changes []*JobSyncChange
+	changes = append(changes, p.Create...)
+	changes = append(changes, p.Update...)
+	return append(changes, p.Delete...)
+}
+
+// JobSyncResult is the result of applying a sync plan
+type JobSyncResult struct {
+	Imported *JobImportResult
+	Deleted  *BulkDeleteJobResponse
+}
+
+// jobFormatForFile returns the job definition format for a file based on its extension
+func jobFormatForFile(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return JobFormatYAML
+	case ".xml":
+		return JobFormatXML
+	case ".json":
+		return JobFormatJSON
+	}
+	return ""
+}
+
+// ReadJobDefinitionsDir reads every job definition under a directory
+// Files are matched on a .yaml, .yml, .xml or .json extension and everything else is ignored
+// Each definition is validated and all problems are returned together
+func ReadJobDefinitionsDir(dir string) ([]*JobDefinitionFile, error) {
+	var files []*JobDefinitionFile
+	var errs *multierror.Error
+	walkErr := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		format := jobFormatForFile(path)
+		if info.IsDir() || format == "" {
+			return nil
+		}
+		data, err := ioutil.ReadFile(path) // nolint: gosec
+		if err != nil {
+			return err
+		}
+		jobs, err := ParseJobDefinitions(data, format)
+		if err != nil {
+			errs = multierror.Append(errs, fmt.Errorf("%s: %s", path, err))
+			return nil
+		}
+		for _, j := range jobs {
+			if vErr := j.Validate(); vErr != nil {
+				errs = multierror.Append(errs, fmt.Errorf("%s: job %q: %s", path, j.Name, vErr))
+				continue
+			}
+			files = append(files, &JobDefinitionFile{Path: path, Job: j})
+		}
+		return nil
+	})
+	if walkErr != nil {
+		return nil, walkErr
+	}
+	if errs != nil {
+		return nil, &JobValidationError{msg: multierror.Append(errValidation, errs.Errors...).Error(), Errors: errs.Errors}
+	}
+	return files, nil
+}
+
+// jobSyncKey is how jobs without a uuid are matched between the server and local definitions
+func jobSyncKey(group, name string) string {
+	return strings.Trim(group, "/") + "/" + name
+}
+
+// PlanJobSync compares job definitions with the jobs in a project
+// Definitions are matched to server jobs by uuid or, when a definition has no uuid, by group and name
+// Server jobs with no matching definition are only planned for deletion when prune is true
+func (c *Client) PlanJobSync(project string, jobs []*JobDefinitionFile, prune bool) (*JobSyncPlan, error) {
+	return c.PlanJobSyncContext(context.Background(), project, jobs, prune)
+}
+
+// PlanJobSyncContext compares job definitions with the jobs in a project
+func (c *Client) PlanJobSyncContext(ctx context.Context, project string, jobs []*JobDefinitionFile, prune bool) (*JobSyncPlan, error) {
+	serverJobs, err := c.ListJobsContext(ctx, project)
+	if err != nil {
+		return nil, err
+	}
+	byID := map[string]Job{}
+	byName := map[string]Job{}
+	for _, j := range serverJobs {
+		byID[j.ID] = j
+		byName[jobSyncKey(j.Group, j.Name)] = j
+	}
+	plan := &JobSyncPlan{Project: project}
+	matched := map[string]bool{}
+	seenNames := map[string]string{}
+	seenUUIDs := map[string]string{}
+	var errs *multierror.Error
+	fail := func(f *JobDefinitionFile, format string, args ...interface{}) {
+		errs = multierror.Append(errs, fmt.Errorf("%s: job %q: %s", f.Path, f.Job.Name, fmt.Sprintf(format, args...)))
+	}
+	for _, f := range jobs {
+		j := f.Job
+		key := jobSyncKey(j.Group, j.Name)
+		if j.Project != "" && j.Project != project {
+			fail(f, "belongs to project %q", j.Project)
+			continue
+		}
+		if prev, ok := seenNames[key]; ok {
+			fail(f, "is also defined in %s", prev)
+			continue
+		}
+		seenNames[key] = f.Path
+		change := &JobSyncChange{Name: j.Name, Group: j.Group, Source: f.Path, Definition: j}
+		named, nameExists := byName[key]
+		if j.UUID != "" {
+			if prev, ok := seenUUIDs[j.UUID]; ok {
+				fail(f, "uuid %s is also used in %s", j.UUID, prev)
+				continue
+			}
+			seenUUIDs[j.UUID] = f.Path
+			if _, ok := byID[j.UUID]; ok {
+				change.Action = JobSyncUpdate
+				change.ID = j.UUID
+			} else if nameExists {
+				fail(f, "server job %s has the same group and name but a different uuid", named.ID)
+				continue
+			} else {
+				change.Action = JobSyncCreate
+				change.ID = j.UUID
+			}
+		} else if nameExists {
+			change.Action = JobSyncUpdate
+			change.ID = named.ID
+		} else {
+			change.Action = JobSyncCreate
+		}
+		if change.Action == JobSyncUpdate {
+			matched[change.ID] = true
+			plan.Update = append(plan.Update, change)
+		} else {
+			plan.Create = append(plan.Create, change)
+		}
+	}
+	if errs != nil {
+		return nil, &JobValidationError{msg: multierror.Append(errValidation, errs.Errors...).Error(), Errors: errs.Errors}
+	}
+	if prune {
+		for _, j := range serverJobs {
+			if matched[j.ID] {
+				continue
+			}
+			plan.Delete = append(plan.Delete, &JobSyncChange{Action: JobSyncDelete, ID: j.ID, Name: j.Name, Group: j.Group})
+		}
+	}
+	for _, changes := range [][]*JobSyncChange{plan.Create, plan.Update, plan.Delete} {
+		sortJobSyncChanges(changes)
+	}
+	return plan, nil
+}
+
+func sortJobSyncChanges(changes []*JobSyncChange) {
+	sort.SliceStable(changes, func(i, j int) bool {
+		return jobSyncKey(changes[i].Group, changes[i].Name) < jobSyncKey(changes[j].Group, changes[j].Name)
+	})
+}
+
+// ApplyJobSyncPlan creates and updates the planned jobs with a single import and then deletes any pruned jobs
+// Nothing is deleted if any job fails to import
+func (c *Client) ApplyJobSyncPlan(plan *JobSyncPlan) (*JobSyncResult, error) {
+	return c.ApplyJobSyncPlanContext(context.Background(), plan)
+}
+
+// ApplyJobSyncPlanContext creates and updates the planned jobs with a single import and then deletes any pruned jobs
+func (c *Client) ApplyJobSyncPlanContext(ctx context.Context, plan *JobSyncPlan) (*JobSyncResult, error) {
+	result := &JobSyncResult{}
+	var defs []*JobDefinition
+	for _, change := range append(append([]*JobSyncChange{}, plan.Create...), plan.Update...) {
+		defs = append(defs, change.Definition)
+	}
+	if len(defs) > 0 {
+		imported, err := c.ImportJobDefinitionsContext(ctx, plan.Project, defs, ImportDupe("update"))
+		if err != nil {
+			return result, err
+		}
+		result.Imported = imported
+		if len(imported.Failed) > 0 {
+			var errs *multierror.Error
+			for _, f := range imported.Failed {
+				errs = multierror.Append(errs, fmt.Errorf("job %q: %s", jobSyncKey(f.Group, f.Name), f.Messages))
+			}
+			return result, errs.ErrorOrNil()
+		}
+	}
+	if len(plan.Delete) > 0 {
+		ids := make([]string, 0, len(plan.Delete))
+		for _, change := range plan.Delete {
+			ids = append(ids, change.ID)
+		}
+		deleted, err := c.BulkJobDeleteContext(ctx, ids...)
+		if err != nil {
+			return result, err
+		}
+		result.Deleted = deleted
+		if len(deleted.Failed) > 0 {
+			var errs *multierror.Error
+			for _, f := range deleted.Failed {
+				errs = multierror.Append(errs, fmt.Errorf("job %s: %s", f.ID, f.Message))
+			}
+			return result, errs.ErrorOrNil()
+		}
+	}
+	return result, nil
+}
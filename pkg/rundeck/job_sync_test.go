@@ -0,0 +1,246 @@
+package rundeck
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/lusis/go-rundeck/pkg/rundeck/responses"
+	"github.com/stretchr/testify/require"
+)
+
+func testSyncServerJobs() []byte {
+	data, _ := json.Marshal([]map[string]string{
+		{"id": "uuid-1", "name": "deploy", "group": "app"},
+		{"id": "uuid-2", "name": "restart", "group": "app"},
+		{"id": "uuid-3", "name": "cleanup", "group": ""},
+	})
+	return data
+}
+
+func testSyncFile(name string, def *JobDefinition) *JobDefinitionFile {
+	return &JobDefinitionFile{Path: name + ".yaml", Job: def}
+}
+
+func testSyncJob(group, name, uuid string) *JobDefinition {
+	job, _ := NewJobBuilder(name).Group(group).UUID(uuid).Command("uptime").Build()
+	return job
+}
+
+func TestReadJobDefinitionsDir(t *testing.T) {
+	dir, err := ioutil.TempDir("", "jobsync")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir) // nolint: errcheck
+	yml, err := MarshalJobDefinitions([]*JobDefinition{testSyncJob("app", "deploy", ""), testSyncJob("app", "restart", "")}, JobFormatYAML)
+	require.NoError(t, err)
+	xml, err := MarshalJobDefinitions([]*JobDefinition{testSyncJob("", "cleanup", "")}, JobFormatXML)
+	require.NoError(t, err)
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "nested"), 0755))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "app.yml"), yml, 0644))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "nested", "cleanup.xml"), xml, 0644))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "README.md"), []byte("not a job"), 0644))
+
+	files, err := ReadJobDefinitionsDir(dir)
+	require.NoError(t, err)
+	require.Len(t, files, 3)
+	require.Equal(t, "deploy", files[0].Job.Name)
+	require.Equal(t, filepath.Join(dir, "app.yml"), files[0].Path)
+	require.Equal(t, "cleanup", files[2].Job.Name)
+}
+
+func TestReadJobDefinitionsDirInvalid(t *testing.T) {
+	dir, err := ioutil.TempDir("", "jobsync")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir) // nolint: errcheck
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "nosteps.yaml"), []byte("- name: nosteps\n"), 0644))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "broken.json"), []byte("{"), 0644))
+
+	files, err := ReadJobDefinitionsDir(dir)
+	require.Nil(t, files)
+	require.IsType(t, &JobValidationError{}, err)
+	require.Len(t, err.(*JobValidationError).Errors, 2)
+	require.Contains(t, err.Error(), "nosteps.yaml")
+	require.Contains(t, err.Error(), "broken.json")
+}
+
+func TestPlanJobSync(t *testing.T) {
+	client, server, cErr := newTestRundeckClientWithHandler(func(w http.ResponseWriter, r *http.Request) {
+		require.True(t, strings.HasSuffix(r.URL.Path, "/project/testproject/jobs"))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(testSyncServerJobs())
+	})
+	defer server.Close()
+	require.NoError(t, cErr)
+	files := []*JobDefinitionFile{
+		testSyncFile("deploy", testSyncJob("app", "deploy", "uuid-1")),
+		testSyncFile("restart", testSyncJob("app", "restart", "")),
+		testSyncFile("new", testSyncJob("app", "new", "")),
+	}
+
+	plan, err := client.PlanJobSync("testproject", files, false)
+	require.NoError(t, err)
+	require.Len(t, plan.Create, 1)
+	require.Equal(t, "new", plan.Create[0].Name)
+	require.Equal(t, JobSyncCreate, plan.Create[0].Action)
+	require.Len(t, plan.Update, 2)
+	require.Equal(t, "uuid-1", plan.Update[0].ID)
+	require.Equal(t, "uuid-2", plan.Update[1].ID)
+	require.Empty(t, plan.Delete)
+
+	plan, err = client.PlanJobSync("testproject", files, true)
+	require.NoError(t, err)
+	require.Len(t, plan.Delete, 1)
+	require.Equal(t, "uuid-3", plan.Delete[0].ID)
+	require.Len(t, plan.Changes(), 4)
+}
+
+func TestPlanJobSyncConflicts(t *testing.T) {
+	client, server, cErr := newTestRundeckClient(testSyncServerJobs(), "application/json", 200)
+	defer server.Close()
+	require.NoError(t, cErr)
+	otherProject := testSyncJob("app", "other", "")
+	otherProject.Project = "elsewhere"
+	files := []*JobDefinitionFile{
+		testSyncFile("a", testSyncJob("app", "deploy", "not-uuid-1")),
+		testSyncFile("b", testSyncJob("app", "twice", "")),
+		testSyncFile("c", testSyncJob("app", "twice", "")),
+		testSyncFile("d", testSyncJob("app", "one", "same")),
+		testSyncFile("e", testSyncJob("app", "two", "same")),
+		testSyncFile("f", otherProject),
+	}
+	plan, err := client.PlanJobSync("testproject", files, true)
+	require.Nil(t, plan)
+	require.IsType(t, &JobValidationError{}, err)
+	require.Len(t, err.(*JobValidationError).Errors, 4)
+}
+
+func TestPlanJobSyncHTTPError(t *testing.T) {
+	client, server, _ := newTestRundeckClient([]byte(""), "application/json", 500)
+	defer server.Close()
+	plan, err := client.PlanJobSync("testproject", nil, true)
+	require.Error(t, err)
+	require.Nil(t, plan)
+}
+
+func TestApplyJobSyncPlan(t *testing.T) {
+	deleted, err := responses.GetTestData(responses.BulkDeleteJobResponseTestFile)
+	require.NoError(t, err)
+	var imported, deletedIDs []string
+	client, server, cErr := newTestRundeckClientWithHandler(func(w http.ResponseWriter, r *http.Request) {
+		body, readErr := ioutil.ReadAll(r.Body)
+		require.NoError(t, readErr)
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/project/testproject/jobs/import"):
+			require.Equal(t, "update", r.URL.Query().Get("dupeOption"))
+			jobs, parseErr := ParseJobDefinitions(body, JobFormatYAML)
+			require.NoError(t, parseErr)
+			for _, j := range jobs {
+				imported = append(imported, j.Name)
+			}
+			_, _ = w.Write([]byte(`{"succeeded":[],"failed":[],"skipped":[]}`))
+		case strings.HasSuffix(r.URL.Path, "/jobs/delete"):
+			req := map[string][]string{}
+			require.NoError(t, json.Unmarshal(body, &req))
+			deletedIDs = req["ids"]
+			_, _ = w.Write([]byte(`{"requestCount":1,"allsuccessful":true,"succeeded":[{"id":"uuid-3"}],"failed":[]}`))
+		default:
+			t.Fatalf("unexpected request %s", r.URL.Path)
+		}
+	})
+	defer server.Close()
+	require.NoError(t, cErr)
+	plan := &JobSyncPlan{
+		Project: "testproject",
+		Create:  []*JobSyncChange{{Action: JobSyncCreate, Name: "new", Definition: testSyncJob("app", "new", "")}},
+		Update:  []*JobSyncChange{{Action: JobSyncUpdate, ID: "uuid-1", Name: "deploy", Definition: testSyncJob("app", "deploy", "uuid-1")}},
+		Delete:  []*JobSyncChange{{Action: JobSyncDelete, ID: "uuid-3", Name: "cleanup"}},
+	}
+	res, err := client.ApplyJobSyncPlan(plan)
+	require.NoError(t, err)
+	require.NotNil(t, res.Imported)
+	require.NotNil(t, res.Deleted)
+	require.Equal(t, []string{"new", "deploy"}, imported)
+	require.Equal(t, []string{"uuid-3"}, deletedIDs)
+
+	// a failed delete is reported
+	client, server, cErr = newTestRundeckClient(deleted, "application/json", 200)
+	defer server.Close()
+	require.NoError(t, cErr)
+	res, err = client.ApplyJobSyncPlan(&JobSyncPlan{Project: "testproject", Delete: plan.Delete})
+	require.Error(t, err)
+	require.NotNil(t, res.Deleted)
+}
+
+func TestApplyJobSyncPlanImportFailure(t *testing.T) {
+	imported, err := responses.GetTestData(responses.ImportedJobResponseTestFile)
+	require.NoError(t, err)
+	client, server, cErr := newTestRundeckClientWithHandler(func(w http.ResponseWriter, r *http.Request) {
+		require.False(t, strings.HasSuffix(r.URL.Path, "/jobs/delete"), "jobs should not be deleted after a failed import")
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(imported)
+	})
+	defer server.Close()
+	require.NoError(t, cErr)
+	plan := &JobSyncPlan{
+		Project: "testproject",
+		Create:  []*JobSyncChange{{Action: JobSyncCreate, Name: "new", Definition: testSyncJob("app", "new", "")}},
+		Delete:  []*JobSyncChange{{Action: JobSyncDelete, ID: "uuid-3", Name: "cleanup"}},
+	}
+	res, err := client.ApplyJobSyncPlan(plan)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "app2/dev/bad")
+	require.NotNil(t, res.Imported)
+	require.Nil(t, res.Deleted)
+}
+
+func TestApplyJobSyncPlanHandWritten(t *testing.T) {
+	dir, err := ioutil.TempDir("", "jobsync")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir) // nolint: errcheck
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "nightly.yaml"), []byte(`- name: nightly
+  group: ops
+  schedule:
+    crontab: 0 0 2 ? * * *
+  sequence:
+    commands:
+    - exec: /opt/backup.sh
+`), 0644))
+	files, err := ReadJobDefinitionsDir(dir)
+	require.NoError(t, err)
+
+	var body []byte
+	client, server, cErr := newTestRundeckClientWithHandler(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/project/testproject/jobs"):
+			_, _ = w.Write([]byte(`[]`))
+		case strings.HasSuffix(r.URL.Path, "/project/testproject/jobs/import"):
+			var readErr error
+			body, readErr = ioutil.ReadAll(r.Body)
+			require.NoError(t, readErr)
+			_, _ = w.Write([]byte(`{"succeeded":[],"failed":[],"skipped":[]}`))
+		default:
+			t.Fatalf("unexpected request %s", r.URL.Path)
+		}
+	})
+	defer server.Close()
+	require.NoError(t, cErr)
+	plan, err := client.PlanJobSync("testproject", files, false)
+	require.NoError(t, err)
+	require.Len(t, plan.Create, 1)
+	_, err = client.ApplyJobSyncPlan(plan)
+	require.NoError(t, err)
+
+	require.NotContains(t, string(body), "executionEnabled")
+	require.NotContains(t, string(body), "scheduleEnabled")
+	jobs, err := ParseJobDefinitions(body, JobFormatYAML)
+	require.NoError(t, err)
+	require.Len(t, jobs, 1)
+	require.True(t, jobs[0].IsExecutionEnabled())
+	require.True(t, jobs[0].IsScheduleEnabled())
+}
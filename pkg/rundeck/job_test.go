@@ -1,7 +1,11 @@
 package rundeck
 
 import (
+	"bytes"
 	"errors"
+	"io/ioutil"
+	"net/http"
+	"strings"
 	"testing"
 	"time"
 
@@ -326,6 +330,27 @@ func TestExportJobHTTPError(t *testing.T) {
 	require.Nil(t, obj)
 }
 
+func TestExportJobTo(t *testing.T) {
+	jsonfile, err := responses.GetTestData("job_definition.yaml")
+	require.NoError(t, err)
+	client, server, cErr := newTestRundeckClient(jsonfile, "application/yaml", 200)
+	defer server.Close()
+	require.NoError(t, cErr)
+	var buf bytes.Buffer
+	require.NoError(t, client.ExportJobTo("abcdefg", "yaml", &buf))
+	require.Equal(t, string(jsonfile), buf.String())
+}
+
+func TestExportJobToHTTPError(t *testing.T) {
+	client, server, cErr := newTestRundeckClient([]byte(""), "application/yaml", 404)
+	defer server.Close()
+	require.NoError(t, cErr)
+	var buf bytes.Buffer
+	err := client.ExportJobTo("abcdefg", "yaml", &buf)
+	require.True(t, errors.Is(err, ErrMissingResource))
+	require.Equal(t, 0, buf.Len())
+}
+
 func TestDeleteAllExecutionsForJob(t *testing.T) {
 	jsonfile, err := responses.GetTestData(responses.BulkDeleteExecutionsResponseTestFile)
 	if err != nil {
@@ -368,3 +393,238 @@ func TestDeleteAllExecutionsForJobJSONError(t *testing.T) {
 	require.Error(t, oErr)
 	require.Nil(t, obj)
 }
+
+func TestUploadFileForJobOption(t *testing.T) {
+	jsonfile, err := responses.GetTestData(responses.JobOptionFileUploadResponseTestFile)
+	require.NoError(t, err)
+	client, server, cErr := newTestRundeckClientWithHandler(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPost, r.Method)
+		require.True(t, strings.HasSuffix(r.URL.Path, "/job/abc123/input/file"))
+		require.Equal(t, "myfile", r.URL.Query().Get("optionName"))
+		require.Equal(t, "data.txt", r.URL.Query().Get("fileName"))
+		require.Equal(t, "application/octet-stream", r.Header.Get("Content-Type"))
+		body, _ := ioutil.ReadAll(r.Body)
+		require.Equal(t, "some data", string(body))
+		_, _ = w.Write(jsonfile)
+	})
+	defer server.Close()
+	require.NoError(t, cErr)
+	ref, oErr := client.UploadFileForJobOption("abc123", "myfile", "data.txt", strings.NewReader("some data"))
+	require.NoError(t, oErr)
+	require.Equal(t, "bb704988-6467-4613-b961-13014f6a55cb", ref)
+}
+
+func TestUploadFileForJobOptionMissingRef(t *testing.T) {
+	jsonfile, err := responses.GetTestData(responses.JobOptionFileUploadResponseTestFile)
+	require.NoError(t, err)
+	client, server, cErr := newTestRundeckClient(jsonfile, "application/json", 200)
+	defer server.Close()
+	require.NoError(t, cErr)
+	ref, oErr := client.UploadFileForJobOption("abc123", "otherfile", "", strings.NewReader("some data"))
+	require.Error(t, oErr)
+	require.Empty(t, ref)
+}
+
+func TestUploadFileForJobOptionHTTPError(t *testing.T) {
+	client, server, cErr := newTestRundeckClient([]byte(""), "application/json", 500)
+	defer server.Close()
+	require.NoError(t, cErr)
+	ref, oErr := client.UploadFileForJobOption("abc123", "myfile", "", strings.NewReader("some data"))
+	require.Error(t, oErr)
+	require.Empty(t, ref)
+}
+
+func TestUploadFilesForJobOptions(t *testing.T) {
+	jsonfile, err := responses.GetTestData(responses.JobOptionFileUploadResponseTestFile)
+	require.NoError(t, err)
+	client, server, cErr := newTestRundeckClientWithHandler(func(w http.ResponseWriter, r *http.Request) {
+		require.True(t, strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data"))
+		f, h, fErr := r.FormFile("option.myfile")
+		require.NoError(t, fErr)
+		defer f.Close() // nolint: errcheck
+		require.Equal(t, "data.txt", h.Filename)
+		body, _ := ioutil.ReadAll(f)
+		require.Equal(t, "some data", string(body))
+		_, _ = w.Write(jsonfile)
+	})
+	defer server.Close()
+	require.NoError(t, cErr)
+	obj, oErr := client.UploadFilesForJobOptions("abc123", &JobOptionFile{
+		OptionName: "myfile",
+		FileName:   "data.txt",
+		Contents:   strings.NewReader("some data"),
+	})
+	require.NoError(t, oErr)
+	require.Equal(t, 1, obj.Total)
+	require.Contains(t, obj.Options, "myfile")
+}
+
+func TestUploadFilesForJobOptionsNoFiles(t *testing.T) {
+	client, server, cErr := newTestRundeckClient([]byte(""), "application/json", 200)
+	defer server.Close()
+	require.NoError(t, cErr)
+	obj, oErr := client.UploadFilesForJobOptions("abc123")
+	require.Error(t, oErr)
+	require.Nil(t, obj)
+}
+
+func TestListFilesUploadedForJob(t *testing.T) {
+	jsonfile, err := responses.GetTestData(responses.UploadedJobInputFilesResponseTestFile)
+	require.NoError(t, err)
+	client, server, cErr := newTestRundeckClientWithHandler(func(w http.ResponseWriter, r *http.Request) {
+		require.True(t, strings.HasSuffix(r.URL.Path, "/job/abc123/input/files"))
+		require.Equal(t, "deleted", r.URL.Query().Get("fileState"))
+		require.Equal(t, "10", r.URL.Query().Get("max"))
+		_, _ = w.Write(jsonfile)
+	})
+	defer server.Close()
+	require.NoError(t, cErr)
+	obj, oErr := client.ListFilesUploadedForJob("abc123", ListUploadedFilesState("deleted"), ListUploadedFilesMax(10))
+	require.NoError(t, oErr)
+	require.NotEmpty(t, obj.Files)
+}
+
+func TestListFilesUploadedForJobInvalidState(t *testing.T) {
+	client, server, cErr := newTestRundeckClient([]byte(""), "application/json", 200)
+	defer server.Close()
+	require.NoError(t, cErr)
+	obj, oErr := client.ListFilesUploadedForJob("abc123", ListUploadedFilesState("bogus"))
+	require.Error(t, oErr)
+	require.IsType(t, &OptionError{}, oErr)
+	require.Nil(t, obj)
+}
+
+func TestGetUploadedFileInfo(t *testing.T) {
+	jsonfile, err := responses.GetTestData(responses.UploadedJobInputFileResponseTestFile)
+	require.NoError(t, err)
+	client, server, cErr := newTestRundeckClient(jsonfile, "application/json", 200)
+	defer server.Close()
+	require.NoError(t, cErr)
+	obj, oErr := client.GetUploadedFileInfo("f985864b-fa1b-4e09-af7a-4315e9908372")
+	require.NoError(t, oErr)
+	require.Equal(t, "deleted", obj.FileState)
+	require.Equal(t, 2741, obj.ExecID)
+}
+
+func TestGetUploadedFileInfoJSONError(t *testing.T) {
+	client, server, cErr := newTestRundeckClient([]byte(""), "application/json", 200)
+	defer server.Close()
+	require.NoError(t, cErr)
+	obj, oErr := client.GetUploadedFileInfo("f985864b-fa1b-4e09-af7a-4315e9908372")
+	require.Error(t, oErr)
+	require.IsType(t, &UnmarshalError{}, oErr)
+	require.Nil(t, obj)
+}
+
+func TestGetExecutionsForJob(t *testing.T) {
+	jsonfile, err := responses.GetTestData(responses.ListRunningExecutionsResponseTestFile)
+	require.NoError(t, err)
+	client, server, cErr := newTestRundeckClientWithHandler(func(w http.ResponseWriter, r *http.Request) {
+		require.True(t, strings.HasSuffix(r.URL.Path, "/job/abc123/executions"))
+		require.Equal(t, "failed", r.URL.Query().Get("status"))
+		_, _ = w.Write(jsonfile)
+	})
+	defer server.Close()
+	require.NoError(t, cErr)
+	obj, oErr := client.GetExecutionsForJob("abc123", map[string]string{"status": "failed"})
+	require.NoError(t, oErr)
+	require.NotEmpty(t, obj.Executions)
+}
+
+func TestGetExecutionsForJobJSONError(t *testing.T) {
+	client, server, cErr := newTestRundeckClient([]byte(""), "application/json", 200)
+	defer server.Close()
+	require.NoError(t, cErr)
+	obj, oErr := client.GetExecutionsForJob("abc123")
+	require.Error(t, oErr)
+	require.IsType(t, &UnmarshalError{}, oErr)
+	require.Nil(t, obj)
+}
+
+func TestGetJobForecast(t *testing.T) {
+	jsonfile, err := responses.GetTestData(responses.JobForecastResponseTestFile)
+	require.NoError(t, err)
+	client, server, cErr := newTestRundeckClientWithHandler(func(w http.ResponseWriter, r *http.Request) {
+		require.True(t, strings.HasSuffix(r.URL.Path, "/job/b7361e0e-abed-4dcc-98d5-ec35189937c0/forecast"))
+		require.Equal(t, "1d", r.URL.Query().Get("time"))
+		require.Equal(t, "5", r.URL.Query().Get("max"))
+		_, _ = w.Write(jsonfile)
+	})
+	defer server.Close()
+	require.NoError(t, cErr)
+	obj, oErr := client.GetJobForecast("b7361e0e-abed-4dcc-98d5-ec35189937c0", 24*time.Hour, 5)
+	require.NoError(t, oErr)
+	require.Equal(t, "testjob", obj.Name)
+	require.Len(t, obj.FireTimes(), 0)
+}
+
+func TestGetJobForecastFireTimes(t *testing.T) {
+	jsonfile := []byte(`{"id":"abc123","name":"testjob","scheduled":true,"futureScheduledExecutions":["2019-05-01T03:00:00Z","2019-05-01T04:00:00Z","2019-05-01T05:00:00Z"]}`)
+	client, server, cErr := newTestRundeckClient(jsonfile, "application/json", 200)
+	defer server.Close()
+	require.NoError(t, cErr)
+	obj, oErr := client.GetJobForecast("abc123", 0, 0)
+	require.NoError(t, oErr)
+	require.Len(t, obj.FireTimes(), 3)
+	start := time.Date(2019, 5, 1, 3, 30, 0, 0, time.UTC)
+	end := time.Date(2019, 5, 1, 5, 0, 0, 0, time.UTC)
+	window := obj.FireTimesBetween(start, end)
+	require.Len(t, window, 2)
+	require.Equal(t, 4, window[0].Hour())
+	require.Len(t, obj.FireTimesBetween(start, time.Time{}), 2)
+}
+
+func TestGetJobForecastInvalid(t *testing.T) {
+	client, server, cErr := newTestRundeckClient([]byte(""), "application/json", 200)
+	defer server.Close()
+	require.NoError(t, cErr)
+	obj, oErr := client.GetJobForecast("abc123", -1*time.Hour, 0)
+	require.Error(t, oErr)
+	require.Nil(t, obj)
+}
+
+func TestGetJobForecastJSONError(t *testing.T) {
+	client, server, cErr := newTestRundeckClient([]byte(""), "application/json", 200)
+	defer server.Close()
+	require.NoError(t, cErr)
+	obj, oErr := client.GetJobForecast("abc123", 0, 0)
+	require.IsType(t, &UnmarshalError{}, oErr)
+	require.Nil(t, obj)
+}
+
+func TestBulkJobDelete(t *testing.T) {
+	jsonfile, err := responses.GetTestData(responses.BulkDeleteJobResponseTestFile)
+	require.NoError(t, err)
+	client, server, cErr := newTestRundeckClientWithHandler(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPost, r.Method)
+		require.True(t, strings.HasSuffix(r.URL.Path, "/jobs/delete"))
+		body, readErr := ioutil.ReadAll(r.Body)
+		require.NoError(t, readErr)
+		require.JSONEq(t, `{"ids":["a","b"]}`, string(body))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(jsonfile)
+	})
+	defer server.Close()
+	require.NoError(t, cErr)
+	obj, err := client.BulkJobDelete("a", "b")
+	require.NoError(t, err)
+	require.NotNil(t, obj)
+	require.Equal(t, 2, obj.RequestCount)
+	require.Len(t, obj.Failed, 1)
+}
+
+func TestBulkJobDeleteHTTPError(t *testing.T) {
+	client, server, _ := newTestRundeckClient([]byte(""), "application/json", 500)
+	defer server.Close()
+	obj, err := client.BulkJobDelete("a", "b")
+	require.Error(t, err)
+	require.Nil(t, obj)
+}
+
+func TestBulkJobDeleteJSONError(t *testing.T) {
+	client, server, _ := newTestRundeckClient([]byte(""), "application/json", 200)
+	defer server.Close()
+	obj, err := client.BulkJobDelete("a", "b")
+	require.Error(t, err)
+	require.Nil(t, obj)
+}
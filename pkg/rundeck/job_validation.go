@@ -0,0 +1,334 @@
+package rundeck
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	multierror "github.com/hashicorp/go-multierror"
+)
+
+// optionNamePattern is the set of names rundeck allows for job options
+var optionNamePattern = regexp.MustCompile(`^[a-zA-Z_0-9.+-]+$`)
+
+var validLogLevels = []string{"DEBUG", "VERBOSE", "INFO", "WARN", "ERROR"}
+
+// Validate checks a job definition for problems rundeck would reject it for
+// It only checks what can be known locally. i.e. referenced jobs aren't looked up
+func (j *JobDefinition) Validate() error {
+	var errs *multierror.Error
+	fail := func(format string, args ...interface{}) {
+		errs = multierror.Append(errs, fmt.Errorf(format, args...))
+	}
+	if strings.TrimSpace(j.Name) == "" {
+		fail("job name is required")
+	}
+	if j.LogLevel != "" && !stringInSlice(j.LogLevel, validLogLevels) {
+		fail("invalid log level %q", j.LogLevel)
+	}
+	if j.Retry != nil && j.Retry.Retry == "" {
+		fail("retry count is required when retry is set")
+	}
+	seen := map[string]bool{}
+	for i, o := range j.Options {
+		if o == nil {
+			fail("option %d is empty", i+1)
+			continue
+		}
+		if seen[o.Name] {
+			fail("option %q is defined more than once", o.Name)
+		}
+		seen[o.Name] = true
+		for _, err := range o.validate() {
+			errs = multierror.Append(errs, fmt.Errorf("option %q: %s", o.Name, err))
+		}
+	}
+	if j.Schedule != nil {
+		if err := j.Schedule.validate(); err != nil {
+			fail("schedule: %s", err)
+		}
+	}
+	if j.Notification != nil {
+		for _, e := range j.Notification.byEvent() {
+			for _, err := range e.notification.validate() {
+				fail("%s notification: %s", e.event, err)
+			}
+		}
+	}
+	if j.Sequence == nil || len(j.Sequence.Commands) == 0 {
+		fail("job must have at least one step")
+	} else {
+		for i, step := range j.Sequence.Commands {
+			if err := step.validate(); err != nil {
+				fail("step %d: %s", i+1, err)
+			}
+		}
+	}
+	if errs == nil {
+		return nil
+	}
+	return &JobValidationError{msg: multierror.Append(errValidation, errs.Errors...).Error(), Errors: errs.Errors}
+}
+
+func (o *JobOptionDefinition) validate() []error {
+	var errs []error
+	if !optionNamePattern.MatchString(o.Name) {
+		errs = append(errs, fmt.Errorf("invalid option name"))
+	}
+	if o.Regex != "" {
+		re, err := regexp.Compile(o.Regex)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("invalid regex: %s", err))
+		} else if o.Value != "" && !o.MultiValued && !strings.Contains(o.Value, "${") && !re.MatchString(o.Value) {
+			errs = append(errs, fmt.Errorf("default value %q does not match regex", o.Value))
+		}
+	}
+	if o.Enforced && len(o.Values) == 0 && o.ValuesURL == "" {
+		errs = append(errs, fmt.Errorf("enforced options need values or a values url"))
+	}
+	if o.Enforced && o.Value != "" && len(o.Values) > 0 && !o.MultiValued && !stringInSlice(o.Value, o.Values) {
+		errs = append(errs, fmt.Errorf("default value %q is not one of the allowed values", o.Value))
+	}
+	if o.MultiValued && o.Delimiter == "" {
+		errs = append(errs, fmt.Errorf("multivalued options need a delimiter"))
+	}
+	if o.MultiValued && o.Secure {
+		errs = append(errs, fmt.Errorf("secure options cannot be multivalued"))
+	}
+	if o.StoragePath != "" && !o.Secure {
+		errs = append(errs, fmt.Errorf("only secure options can have a storage path"))
+	}
+	if o.ValuesURL != "" {
+		if _, err := url.ParseRequestURI(o.ValuesURL); err != nil {
+			errs = append(errs, fmt.Errorf("invalid values url: %s", err))
+		}
+	}
+	if o.Type != "" && o.Type != "text" && o.Type != "file" {
+		errs = append(errs, fmt.Errorf("invalid option type %q", o.Type))
+	}
+	return errs
+}
+
+func (s *JobSchedule) validate() error {
+	if s.Crontab != "" {
+		return ValidateCron(s.Crontab)
+	}
+	if s.Time == nil {
+		return fmt.Errorf("a crontab or time is required")
+	}
+	if err := cronField(s.Time.Hour, 0, 23, nil); err != nil {
+		return fmt.Errorf("hour: %s", err)
+	}
+	if err := cronField(s.Time.Minute, 0, 59, nil); err != nil {
+		return fmt.Errorf("minute: %s", err)
+	}
+	if s.Time.Seconds != "" {
+		if err := cronField(s.Time.Seconds, 0, 59, nil); err != nil {
+			return fmt.Errorf("seconds: %s", err)
+		}
+	}
+	return nil
+}
+
+// byEvent returns the notifications that are set along with the name rundeck uses for each event
+func (n *JobNotifications) byEvent() []namedNotification {
+	var events []namedNotification
+	for _, e := range []namedNotification{
+		{"onsuccess", n.OnSuccess},
+		{"onfailure", n.OnFailure},
+		{"onstart", n.OnStart},
+		{"onavgduration", n.OnAvgDuration},
+		{"onretryablefailure", n.OnRetryableFailure},
+	} {
+		if e.notification != nil {
+			events = append(events, e)
+		}
+	}
+	return events
+}
+
+type namedNotification struct {
+	event        string
+	notification *JobNotification
+}
+
+func (n *JobNotification) validate() []error {
+	var errs []error
+	if n.Email != nil && strings.TrimSpace(n.Email.Recipients) == "" {
+		errs = append(errs, fmt.Errorf("email recipients are required"))
+	}
+	if n.URLs != "" {
+		for _, u := range strings.Split(n.URLs, ",") {
+			if _, err := url.ParseRequestURI(strings.TrimSpace(u)); err != nil {
+				errs = append(errs, fmt.Errorf("invalid webhook url: %s", err))
+			}
+		}
+	}
+	for _, p := range n.Plugins {
+		if p == nil || p.Type == "" {
+			errs = append(errs, fmt.Errorf("plugin type is required"))
+		}
+	}
+	return errs
+}
+
+func (s *JobStep) validate() error {
+	if s == nil {
+		return fmt.Errorf("step is empty")
+	}
+	kinds := 0
+	for _, set := range []bool{s.Exec != "", s.Script != "", s.ScriptFile != "", s.ScriptURL != "", s.JobRef != nil, s.Type != ""} {
+		if set {
+			kinds++
+		}
+	}
+	switch {
+	case kinds == 0:
+		return fmt.Errorf("step needs a command, script, job reference or plugin type")
+	case kinds > 1:
+		return fmt.Errorf("step can only have one of a command, script, job reference or plugin type")
+	}
+	if s.JobRef != nil && s.JobRef.UUID == "" && s.JobRef.Name == "" {
+		return fmt.Errorf("job reference needs a uuid or name")
+	}
+	if s.ErrorHandler != nil {
+		if s.ErrorHandler.ErrorHandler != nil {
+			return fmt.Errorf("error handlers cannot have their own error handler")
+		}
+		if err := s.ErrorHandler.validate(); err != nil {
+			return fmt.Errorf("error handler: %s", err)
+		}
+	}
+	return nil
+}
+
+var (
+	cronMonths   = map[string]int{"JAN": 1, "FEB": 2, "MAR": 3, "APR": 4, "MAY": 5, "JUN": 6, "JUL": 7, "AUG": 8, "SEP": 9, "OCT": 10, "NOV": 11, "DEC": 12}
+	cronWeekdays = map[string]int{"SUN": 1, "MON": 2, "TUE": 3, "WED": 4, "THU": 5, "FRI": 6, "SAT": 7}
+)
+
+// ValidateCron checks a quartz cron expression as used by rundeck job schedules
+// The expression is seconds, minutes, hours, day of month, month, day of week and an optional year
+// One of day of month or day of week must be ?
+func ValidateCron(expr string) error {
+	fields := strings.Fields(expr)
+	if len(fields) != 6 && len(fields) != 7 {
+		return fmt.Errorf("cron expression %q must have 6 or 7 fields", expr)
+	}
+	type field struct {
+		name     string
+		min, max int
+		names    map[string]int
+	}
+	specs := []field{
+		{"seconds", 0, 59, nil},
+		{"minutes", 0, 59, nil},
+		{"hours", 0, 23, nil},
+		{"day of month", 1, 31, nil},
+		{"month", 1, 12, cronMonths},
+		{"day of week", 1, 7, cronWeekdays},
+		{"year", 1970, 2099, nil},
+	}
+	dom, dow := fields[3], fields[5]
+	if (dom == "?") == (dow == "?") {
+		return fmt.Errorf("cron expression %q must use ? for exactly one of day of month and day of week", expr)
+	}
+	for i, value := range fields {
+		spec := specs[i]
+		var err error
+		switch {
+		case value == "?" && (i == 3 || i == 5):
+		case i == 3:
+			err = cronDayOfMonth(value)
+		case i == 5:
+			err = cronDayOfWeek(value)
+		default:
+			err = cronField(value, spec.min, spec.max, spec.names)
+		}
+		if err != nil {
+			return fmt.Errorf("cron expression %q: %s: %s", expr, spec.name, err)
+		}
+	}
+	return nil
+}
+
+// cronField checks a list of values, ranges and increments
+func cronField(value string, min, max int, names map[string]int) error {
+	if value == "" {
+		return fmt.Errorf("value is required")
+	}
+	for _, part := range strings.Split(value, ",") {
+		base := part
+		if slash := strings.Index(part, "/"); slash >= 0 {
+			base = part[:slash]
+			step, err := strconv.Atoi(part[slash+1:])
+			if err != nil || step < 1 {
+				return fmt.Errorf("invalid increment in %q", part)
+			}
+		}
+		if base == "*" {
+			continue
+		}
+		bounds := strings.SplitN(base, "-", 2)
+		for _, b := range bounds {
+			if _, err := cronValue(b, min, max, names); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func cronValue(v string, min, max int, names map[string]int) (int, error) {
+	if n, ok := names[strings.ToUpper(v)]; ok {
+		return n, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < min || n > max {
+		return 0, fmt.Errorf("%q is not between %d and %d", v, min, max)
+	}
+	return n, nil
+}
+
+func cronDayOfMonth(value string) error {
+	switch {
+	case value == "L" || value == "LW":
+		return nil
+	case strings.HasPrefix(value, "L-"):
+		_, err := cronValue(value[2:], 1, 30, nil)
+		return err
+	case strings.HasSuffix(value, "W"):
+		_, err := cronValue(strings.TrimSuffix(value, "W"), 1, 31, nil)
+		return err
+	}
+	return cronField(value, 1, 31, nil)
+}
+
+func cronDayOfWeek(value string) error {
+	switch {
+	case value == "L":
+		return nil
+	case strings.HasSuffix(value, "L"):
+		_, err := cronValue(strings.TrimSuffix(value, "L"), 1, 7, cronWeekdays)
+		return err
+	case strings.Contains(value, "#"):
+		parts := strings.SplitN(value, "#", 2)
+		if _, err := cronValue(parts[0], 1, 7, cronWeekdays); err != nil {
+			return err
+		}
+		_, err := cronValue(parts[1], 1, 5, nil)
+		return err
+	}
+	return cronField(value, 1, 7, cronWeekdays)
+}
+
+func stringInSlice(s string, list []string) bool {
+	for _, l := range list {
+		if l == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,77 @@
+package rundeck
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateCron(t *testing.T) {
+	valid := []string{
+		"0 0 12 ? * * *",
+		"0 30 9 ? * MON-FRI",
+		"0 0/15 * * * ?",
+		"0 0 0 L * ?",
+		"0 0 0 LW * ? 2030",
+		"0 0 0 15W * ?",
+		"0 0 0 L-3 * ?",
+		"0 0 0 ? JAN,MAR,DEC 6L",
+		"0 0 0 ? * 2#1",
+		"0 0 8-18/2 ? * 2-6",
+	}
+	for _, expr := range valid {
+		require.NoError(t, ValidateCron(expr), expr)
+	}
+	invalid := []string{
+		"",
+		"* * * * *",
+		"0 0 12 * * * *",
+		"0 0 12 ? * ? *",
+		"60 0 12 ? * *",
+		"0 0 24 ? * *",
+		"0 0 0 32 * ?",
+		"0 0 0 ? FOO *",
+		"0 0 0 ? * 8",
+		"0 0/0 * * * ?",
+		"0 0 0 ? * 2#6",
+		"0 0 0 1 * ? 1900",
+	}
+	for _, expr := range invalid {
+		require.Error(t, ValidateCron(expr), expr)
+	}
+}
+
+func TestJobDefinitionValidate(t *testing.T) {
+	require.NoError(t, testFullJobDefinition().Validate())
+
+	tests := map[string]func(j *JobDefinition){
+		"missing name":        func(j *JobDefinition) { j.Name = " " },
+		"bad log level":       func(j *JobDefinition) { j.LogLevel = "LOUD" },
+		"no steps":            func(j *JobDefinition) { j.Sequence.Commands = nil },
+		"duplicate option":    func(j *JobDefinition) { j.Options = append(j.Options, &JobOptionDefinition{Name: "version"}) },
+		"bad option name":     func(j *JobDefinition) { j.Options[0].Name = "has space" },
+		"bad option regex":    func(j *JobDefinition) { j.Options[2].Regex = "[" },
+		"default not allowed": func(j *JobDefinition) { j.Options[0].MultiValued = false; j.Options[0].Value = "2.0" },
+		"enforced no values":  func(j *JobDefinition) { j.Options[0].Values = nil },
+		"regex mismatch":      func(j *JobDefinition) { j.Options[2].Value = "abc" },
+		"storage not secure":  func(j *JobDefinition) { j.Options[1].Secure = false },
+		"bad schedule time":   func(j *JobDefinition) { j.Schedule.Time.Hour = "25" },
+		"bad cron":            func(j *JobDefinition) { j.Schedule = &JobSchedule{Crontab: "0 0 0 * * *"} },
+		"no recipients":       func(j *JobDefinition) { j.Notification.OnSuccess.Email.Recipients = "" },
+		"bad webhook":         func(j *JobDefinition) { j.Notification.OnFailure.URLs = "not a url" },
+		"two step kinds":      func(j *JobDefinition) { j.Sequence.Commands[0].Script = "echo" },
+		"empty step":          func(j *JobDefinition) { j.Sequence.Commands[0] = &JobStep{Description: "nothing"} },
+		"empty jobref":        func(j *JobDefinition) { j.Sequence.Commands[4].JobRef = &JobReference{} },
+		"nested handler":      func(j *JobDefinition) { j.Sequence.Commands[0].ErrorHandler.ErrorHandler = &JobStep{Exec: "x"} },
+	}
+	for name, breakJob := range tests {
+		t.Run(name, func(t *testing.T) {
+			job := testFullJobDefinition()
+			breakJob(job)
+			err := job.Validate()
+			require.Error(t, err)
+			_, ok := err.(*JobValidationError)
+			require.True(t, ok)
+		})
+	}
+}
@@ -1,52 +1,219 @@
 package rundeck
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"io"
+	"strings"
 
+	multierror "github.com/hashicorp/go-multierror"
 	"github.com/lusis/go-rundeck/pkg/rundeck/responses"
 )
 
+const (
+	// KeyTypePrivate is the key type for private keys
+	KeyTypePrivate = "private"
+	// KeyTypePublic is the key type for public keys
+	KeyTypePublic = "public"
+	// KeyTypePassword is the key type for passwords
+	KeyTypePassword = "password"
+)
+
+// keyContentTypes maps the key types to the content-type rundeck expects when storing them
+var keyContentTypes = map[string]string{
+	KeyTypePrivate:  "application/octet-stream",
+	KeyTypePublic:   "application/pgp-keys",
+	KeyTypePassword: "application/x-rundeck-data-password",
+}
+
+// Keys represents a key storage directory listing
+type Keys struct {
+	responses.ListKeysResponse
+}
+
+// KeyMeta represents a resource in key storage
+type KeyMeta struct {
+	responses.ListKeysResourceResponse
+}
+
+// IsDirectory returns if the key storage resource is a directory
+func (k *KeyMeta) IsDirectory() bool {
+	return k.Type == "directory"
+}
+
+// keyStoragePath converts a key path to an api path
+// paths can be passed with or without the leading `keys/`
+func keyStoragePath(path string) string {
+	p := strings.Trim(path, "/")
+	if p == "keys" {
+		p = ""
+	}
+	p = strings.TrimPrefix(p, "keys/")
+	if p == "" {
+		return "storage/keys"
+	}
+	return "storage/keys/" + p
+}
+
 // UploadKey stores keys on the rundeck server
 // http://rundeck.org/docs/api/index.html#upload-keys
-func (c *Client) UploadKey() error {
-	if err := c.checkRequiredAPIVersion(responses.GenericVersionedResponse{}); err != nil {
-		return err
+func (c *Client) UploadKey(path, keyType string, contents io.Reader) (*KeyMeta, error) {
+	return c.UploadKeyContext(context.Background(), path, keyType, contents)
+}
+
+// UploadKeyContext stores keys on the rundeck server
+func (c *Client) UploadKeyContext(ctx context.Context, path, keyType string, contents io.Reader) (*KeyMeta, error) {
+	if err := c.checkRequiredAPIVersion(responses.ListKeysResourceResponse{}); err != nil {
+		return nil, err
+	}
+	ct, ok := keyContentTypes[keyType]
+	if !ok {
+		return nil, fmt.Errorf("unknown key type: %s", keyType)
+	}
+	res, err := c.httpPost(ctx, keyStoragePath(path),
+		withBody(contents),
+		contentType(ct),
+		accept("application/json"),
+		requestExpects(201))
+	if err != nil {
+		return nil, err
 	}
-	return fmt.Errorf("not yet implemented")
+	data := &KeyMeta{}
+	if jsonErr := json.Unmarshal(res, data); jsonErr != nil {
+		return nil, &UnmarshalError{msg: multierror.Append(errDecoding, jsonErr).Error()}
+	}
+	return data, nil
+}
+
+// UpdateKey replaces the contents of an existing key on the rundeck server
+// http://rundeck.org/docs/api/index.html#upload-keys
+func (c *Client) UpdateKey(path, keyType string, contents io.Reader) (*KeyMeta, error) {
+	return c.UpdateKeyContext(context.Background(), path, keyType, contents)
+}
+
+// UpdateKeyContext replaces the contents of an existing key on the rundeck server
+func (c *Client) UpdateKeyContext(ctx context.Context, path, keyType string, contents io.Reader) (*KeyMeta, error) {
+	if err := c.checkRequiredAPIVersion(responses.ListKeysResourceResponse{}); err != nil {
+		return nil, err
+	}
+	ct, ok := keyContentTypes[keyType]
+	if !ok {
+		return nil, fmt.Errorf("unknown key type: %s", keyType)
+	}
+	res, err := c.httpPut(ctx, keyStoragePath(path),
+		withBody(contents),
+		contentType(ct),
+		accept("application/json"),
+		requestExpects(200))
+	if err != nil {
+		return nil, err
+	}
+	data := &KeyMeta{}
+	if jsonErr := json.Unmarshal(res, data); jsonErr != nil {
+		return nil, &UnmarshalError{msg: multierror.Append(errDecoding, jsonErr).Error()}
+	}
+	return data, nil
 }
 
 // ListKeys lists key resources
 // http://rundeck.org/docs/api/index.html#list-keys
-func (c *Client) ListKeys() error {
+func (c *Client) ListKeys(path string) (*Keys, error) {
+	return c.ListKeysContext(context.Background(), path)
+}
+
+// ListKeysContext lists key resources
+func (c *Client) ListKeysContext(ctx context.Context, path string) (*Keys, error) {
 	if err := c.checkRequiredAPIVersion(responses.ListKeysResponse{}); err != nil {
-		return err
+		return nil, err
 	}
-	return fmt.Errorf("not yet implemented")
+	res, err := c.httpGet(ctx, keyStoragePath(path), requestJSON(), requestExpects(200))
+	if err != nil {
+		return nil, err
+	}
+	data := &Keys{}
+	if jsonErr := json.Unmarshal(res, data); jsonErr != nil {
+		return nil, &UnmarshalError{msg: multierror.Append(errDecoding, jsonErr).Error()}
+	}
+	return data, nil
+}
+
+// ListKeysRecursive lists all keys under the provided path, descending into subdirectories
+// Only keys are returned, not the directories themselves
+func (c *Client) ListKeysRecursive(path string) ([]*KeyMeta, error) {
+	return c.ListKeysRecursiveContext(context.Background(), path)
+}
+
+// ListKeysRecursiveContext lists all keys under the provided path, descending into subdirectories
+func (c *Client) ListKeysRecursiveContext(ctx context.Context, path string) ([]*KeyMeta, error) {
+	dir, err := c.ListKeysContext(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	results := []*KeyMeta{}
+	for _, r := range dir.Resources {
+		k := &KeyMeta{r}
+		if !k.IsDirectory() {
+			results = append(results, k)
+			continue
+		}
+		children, childErr := c.ListKeysRecursiveContext(ctx, k.Path)
+		if childErr != nil {
+			return nil, childErr
+		}
+		results = append(results, children...)
+	}
+	return results, nil
 }
 
 // GetKeyMetaData returns the metadata about a stored key
 // http://rundeck.org/docs/api/index.html#get-key-metadata
-func (c *Client) GetKeyMetaData() error {
+func (c *Client) GetKeyMetaData(path string) (*KeyMeta, error) {
+	return c.GetKeyMetaDataContext(context.Background(), path)
+}
+
+// GetKeyMetaDataContext returns the metadata about a stored key
+func (c *Client) GetKeyMetaDataContext(ctx context.Context, path string) (*KeyMeta, error) {
 	if err := c.checkRequiredAPIVersion(responses.KeyMetaResponse{}); err != nil {
-		return err
+		return nil, err
+	}
+	res, err := c.httpGet(ctx, keyStoragePath(path), requestJSON(), requestExpects(200))
+	if err != nil {
+		return nil, err
 	}
-	return fmt.Errorf("not yet implemented")
+	data := &KeyMeta{}
+	if jsonErr := json.Unmarshal(res, data); jsonErr != nil {
+		return nil, &UnmarshalError{msg: multierror.Append(errDecoding, jsonErr).Error()}
+	}
+	return data, nil
 }
 
 // GetKeyContents provides the public key content
+// rundeck only allows retrieving the contents of public keys
 // http://rundeck.org/docs/api/index.html#get-key-contents
-func (c *Client) GetKeyContents() error {
+func (c *Client) GetKeyContents(path string) ([]byte, error) {
+	return c.GetKeyContentsContext(context.Background(), path)
+}
+
+// GetKeyContentsContext provides the public key content
+func (c *Client) GetKeyContentsContext(ctx context.Context, path string) ([]byte, error) {
 	if err := c.checkRequiredAPIVersion(responses.GenericVersionedResponse{}); err != nil {
-		return err
+		return nil, err
 	}
-	return fmt.Errorf("not yet implemented")
+	return c.httpGet(ctx, keyStoragePath(path), accept(keyContentTypes[KeyTypePublic]), requestExpects(200))
 }
 
 // DeleteKey deletes a key
 // http://rundeck.org/docs/api/index.html#delete-keys
-func (c *Client) DeleteKey() error {
+func (c *Client) DeleteKey(path string) error {
+	return c.DeleteKeyContext(context.Background(), path)
+}
+
+// DeleteKeyContext deletes a key
+func (c *Client) DeleteKeyContext(ctx context.Context, path string) error {
 	if err := c.checkRequiredAPIVersion(responses.GenericVersionedResponse{}); err != nil {
 		return err
 	}
-	return fmt.Errorf("not yet implemented")
+	_, err := c.httpDelete(ctx, keyStoragePath(path), requestExpects(204))
+	return err
 }
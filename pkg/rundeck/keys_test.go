@@ -0,0 +1,175 @@
+package rundeck
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/lusis/go-rundeck/pkg/rundeck/responses"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeyStoragePath(t *testing.T) {
+	testCases := map[string]string{
+		"":                  "storage/keys",
+		"/":                 "storage/keys",
+		"keys":              "storage/keys",
+		"keys/":             "storage/keys",
+		"foo/bar.pem":       "storage/keys/foo/bar.pem",
+		"/keys/foo/bar.pem": "storage/keys/foo/bar.pem",
+		"keysfoo/bar":       "storage/keys/keysfoo/bar",
+	}
+	for in, expected := range testCases {
+		require.Equal(t, expected, keyStoragePath(in), in)
+	}
+}
+
+func TestListKeys(t *testing.T) {
+	jsonfile, err := responses.GetTestData(responses.ListKeysResponseTestFile)
+	require.NoError(t, err)
+	client, server, cErr := newTestRundeckClient(jsonfile, "application/json", 200)
+	defer server.Close()
+	require.NoError(t, cErr)
+	obj, oErr := client.ListKeys("")
+	require.NoError(t, oErr)
+	require.Len(t, obj.Resources, 4)
+	require.Equal(t, "keys", obj.Path)
+}
+
+func TestListKeysJSONError(t *testing.T) {
+	client, server, cErr := newTestRundeckClient([]byte(""), "application/json", 200)
+	defer server.Close()
+	require.NoError(t, cErr)
+	obj, oErr := client.ListKeys("")
+	require.Error(t, oErr)
+	require.IsType(t, &UnmarshalError{}, oErr)
+	require.Nil(t, obj)
+}
+
+func TestListKeysRecursive(t *testing.T) {
+	jsonfile, err := responses.GetTestData(responses.ListKeysResponseTestFile)
+	require.NoError(t, err)
+	subdir := `{"resources":[{"meta":{"Rundeck-key-type":"password","Rundeck-content-mask":"content","Rundeck-content-type":"application/x-rundeck-data-password"},"url":"http://dignan.local:4440/api/11/storage/keys/subdir/db","name":"db","type":"file","path":"keys/subdir/db"}],"url":"http://dignan.local:4440/api/11/storage/keys/subdir","type":"directory","path":"keys/subdir"}`
+	client, server, cErr := newTestRundeckClientWithHandler(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if strings.HasSuffix(r.URL.Path, "/storage/keys/subdir") {
+			_, _ = w.Write([]byte(subdir))
+			return
+		}
+		_, _ = w.Write(jsonfile)
+	})
+	defer server.Close()
+	require.NoError(t, cErr)
+	obj, oErr := client.ListKeysRecursive("keys")
+	require.NoError(t, oErr)
+	require.Len(t, obj, 4)
+	paths := []string{}
+	for _, k := range obj {
+		require.False(t, k.IsDirectory())
+		paths = append(paths, k.Path)
+	}
+	require.Contains(t, paths, "keys/subdir/db")
+}
+
+func TestGetKeyMetaData(t *testing.T) {
+	jsonfile, err := responses.GetTestData(responses.ListKeysResourceResponseTestFile)
+	require.NoError(t, err)
+	client, server, cErr := newTestRundeckClient(jsonfile, "application/json", 200)
+	defer server.Close()
+	require.NoError(t, cErr)
+	obj, oErr := client.GetKeyMetaData("keys/test1.pub")
+	require.NoError(t, oErr)
+	require.Equal(t, "test1.pub", obj.Name)
+	require.Equal(t, "public", obj.Meta.RundeckKeyType)
+	require.False(t, obj.IsDirectory())
+}
+
+func TestGetKeyMetaDataNotFound(t *testing.T) {
+	client, server, cErr := newTestRundeckClient([]byte(""), "application/json", 404)
+	defer server.Close()
+	require.NoError(t, cErr)
+	obj, oErr := client.GetKeyMetaData("keys/test1.pub")
+	require.Error(t, oErr)
+	require.Nil(t, obj)
+}
+
+func TestGetKeyContents(t *testing.T) {
+	client, server, cErr := newTestRundeckClientWithHandler(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "application/pgp-keys", r.Header.Get("Accept"))
+		_, _ = w.Write([]byte("ssh-rsa AAAA"))
+	})
+	defer server.Close()
+	require.NoError(t, cErr)
+	obj, oErr := client.GetKeyContents("keys/test1.pub")
+	require.NoError(t, oErr)
+	require.Equal(t, "ssh-rsa AAAA", string(obj))
+}
+
+func TestUploadKey(t *testing.T) {
+	jsonfile, err := responses.GetTestData(responses.ListKeysResourceResponseTestFile)
+	require.NoError(t, err)
+	client, server, cErr := newTestRundeckClientWithHandler(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPost, r.Method)
+		require.Equal(t, "application/x-rundeck-data-password", r.Header.Get("Content-Type"))
+		require.True(t, strings.HasSuffix(r.URL.Path, "/storage/keys/test/password"))
+		body, _ := ioutil.ReadAll(r.Body)
+		require.Equal(t, "s3cr3t", string(body))
+		w.WriteHeader(201)
+		_, _ = w.Write(jsonfile)
+	})
+	defer server.Close()
+	require.NoError(t, cErr)
+	obj, oErr := client.UploadKey("test/password", KeyTypePassword, strings.NewReader("s3cr3t"))
+	require.NoError(t, oErr)
+	require.NotNil(t, obj)
+}
+
+func TestUploadKeyInvalidType(t *testing.T) {
+	client, server, cErr := newTestRundeckClient([]byte(""), "application/json", 201)
+	defer server.Close()
+	require.NoError(t, cErr)
+	obj, oErr := client.UploadKey("test/password", "secret", strings.NewReader("s3cr3t"))
+	require.Error(t, oErr)
+	require.Nil(t, obj)
+}
+
+func TestUploadKeyConflict(t *testing.T) {
+	client, server, cErr := newTestRundeckClient([]byte(""), "application/json", 409)
+	defer server.Close()
+	require.NoError(t, cErr)
+	obj, oErr := client.UploadKey("test/password", KeyTypePassword, strings.NewReader("s3cr3t"))
+	require.True(t, errors.Is(oErr, ErrResourceConflict))
+	require.Nil(t, obj)
+}
+
+func TestUpdateKey(t *testing.T) {
+	jsonfile, err := responses.GetTestData(responses.ListKeysResourceResponseTestFile)
+	require.NoError(t, err)
+	client, server, cErr := newTestRundeckClientWithHandler(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPut, r.Method)
+		require.Equal(t, "application/pgp-keys", r.Header.Get("Content-Type"))
+		_, _ = w.Write(jsonfile)
+	})
+	defer server.Close()
+	require.NoError(t, cErr)
+	obj, oErr := client.UpdateKey("keys/test1.pub", KeyTypePublic, strings.NewReader("ssh-rsa AAAA"))
+	require.NoError(t, oErr)
+	require.Equal(t, "keys/test1.pub", obj.Path)
+}
+
+func TestDeleteKey(t *testing.T) {
+	client, server, cErr := newTestRundeckClient([]byte(""), "application/json", 204)
+	defer server.Close()
+	require.NoError(t, cErr)
+	require.NoError(t, client.DeleteKey("keys/test1.pub"))
+}
+
+func TestDeleteKeyNotFound(t *testing.T) {
+	client, server, cErr := newTestRundeckClient([]byte(""), "application/json", 404)
+	defer server.Close()
+	require.NoError(t, cErr)
+	require.Error(t, client.DeleteKey("keys/test1.pub"))
+}
@@ -1,6 +1,7 @@
 package rundeck
 
 import (
+	"context"
 	"encoding/json"
 
 	multierror "github.com/hashicorp/go-multierror"
@@ -20,11 +21,16 @@ type IncompleteLogStorage struct {
 // GetLogStorageInfo gets the logstorage
 // http://rundeck.org/docs/api/index.html#log-storage-info
 func (c *Client) GetLogStorageInfo() (*LogStorage, error) {
+	return c.GetLogStorageInfoContext(context.Background())
+}
+
+// GetLogStorageInfoContext gets the logstorage
+func (c *Client) GetLogStorageInfoContext(ctx context.Context) (*LogStorage, error) {
 	if err := c.checkRequiredAPIVersion(responses.LogStorageResponse{}); err != nil {
 		return nil, err
 	}
 	ls := &LogStorage{}
-	data, err := c.httpGet("system/logstorage", requestJSON(), requestExpects(200))
+	data, err := c.httpGet(ctx, "system/logstorage", requestJSON(), requestExpects(200))
 	if err != nil {
 		return nil, err
 	}
@@ -37,11 +43,16 @@ func (c *Client) GetLogStorageInfo() (*LogStorage, error) {
 // GetIncompleteLogStorage gets executions with incomplete logstorage
 // http://rundeck.org/docs/api/index.html#list-executions-with-incomplete-log-storage
 func (c *Client) GetIncompleteLogStorage() (*IncompleteLogStorage, error) {
+	return c.GetIncompleteLogStorageContext(context.Background())
+}
+
+// GetIncompleteLogStorageContext gets executions with incomplete logstorage
+func (c *Client) GetIncompleteLogStorageContext(ctx context.Context) (*IncompleteLogStorage, error) {
 	if err := c.checkRequiredAPIVersion(responses.IncompleteLogStorageResponse{}); err != nil {
 		return nil, err
 	}
 	ls := &IncompleteLogStorage{}
-	data, err := c.httpGet("system/logstorage/incomplete", requestJSON(), requestExpects(200))
+	data, err := c.httpGet(ctx, "system/logstorage/incomplete", requestJSON(), requestExpects(200))
 	if err != nil {
 		return nil, err
 	}
@@ -54,11 +65,16 @@ func (c *Client) GetIncompleteLogStorage() (*IncompleteLogStorage, error) {
 // ResumeIncompleteLogStorage resumes processing incomplete log storage uploads
 // http://rundeck.org/docs/api/index.html#resume-incomplete-log-storage
 func (c *Client) ResumeIncompleteLogStorage() (bool, error) {
+	return c.ResumeIncompleteLogStorageContext(context.Background())
+}
+
+// ResumeIncompleteLogStorageContext resumes processing incomplete log storage uploads
+func (c *Client) ResumeIncompleteLogStorageContext(ctx context.Context) (bool, error) {
 	if err := c.checkRequiredAPIVersion(responses.IncompleteLogStorageResponse{}); err != nil {
 		return false, err
 	}
 	res := make(map[string]bool)
-	data, err := c.httpPost("system/logstorage/incomplete/resume", requestJSON(), requestExpects(200))
+	data, err := c.httpPost(ctx, "system/logstorage/incomplete/resume", requestJSON(), requestExpects(200))
 	if err != nil {
 		return false, err
 	}
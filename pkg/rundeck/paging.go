@@ -0,0 +1,188 @@
+package rundeck
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strconv"
+
+	multierror "github.com/hashicorp/go-multierror"
+	responses "github.com/lusis/go-rundeck/pkg/rundeck/responses"
+)
+
+// DefaultPageSize is the number of results requested per page by the iterators
+const DefaultPageSize = 20
+
+// PagingOption is a functional option for the list iterators
+type PagingOption func(p *pager) error
+
+// PageSize sets the number of results requested from the server per page
+func PageSize(size int) PagingOption {
+	return func(p *pager) error {
+		if size <= 0 {
+			return errors.New("page size must be greater than zero")
+		}
+		p.pageSize = size
+		return nil
+	}
+}
+
+// PageLimit sets the maximum number of results the iterator will return
+// a limit of 0 returns all results
+func PageLimit(limit int) PagingOption {
+	return func(p *pager) error {
+		if limit < 0 {
+			return errors.New("limit cannot be negative")
+		}
+		p.limit = limit
+		return nil
+	}
+}
+
+// pager tracks the offsets shared by all of the list iterators
+type pager struct {
+	ctx       context.Context
+	params    url.Values
+	pageSize  int
+	limit     int
+	offset    int
+	returned  int
+	exhausted bool
+	err       error
+}
+
+func newPager(ctx context.Context, params url.Values, opts ...PagingOption) pager {
+	p := pager{
+		ctx:      ctx,
+		params:   copyValues(params),
+		pageSize: DefaultPageSize,
+	}
+	for _, opt := range opts {
+		if err := opt(&p); err != nil {
+			p.err = &OptionError{msg: multierror.Append(errOption, err).Error()}
+			return p
+		}
+	}
+	return p
+}
+
+// limitReached reports if the iterator has returned as many results as requested
+func (p *pager) limitReached() bool {
+	return p.limit > 0 && p.returned >= p.limit
+}
+
+// nextPageParams returns the query params and requested size for the next page
+// the requested page size is trimmed so we never fetch more than the limit
+func (p *pager) nextPageParams() (url.Values, int) {
+	max := p.pageSize
+	if p.limit > 0 && p.limit-p.returned < max {
+		max = p.limit - p.returned
+	}
+	params := copyValues(p.params)
+	params.Set("max", strconv.Itoa(max))
+	params.Set("offset", strconv.Itoa(p.offset))
+	return params, max
+}
+
+// pageFetched records the results of fetching a page
+func (p *pager) pageFetched(requested, count int, paging *responses.PagingResponse) {
+	p.offset += count
+	if count == 0 || count < requested {
+		p.exhausted = true
+	}
+	if paging != nil && paging.Total > 0 && p.offset >= paging.Total {
+		p.exhausted = true
+	}
+}
+
+// Err returns the first error encountered by the iterator
+func (p *pager) Err() error {
+	return p.err
+}
+
+// ExecutionIterator iterates over a paged list of executions
+type ExecutionIterator struct {
+	pager
+	fetch   func(ctx context.Context, params url.Values) (*Executions, error)
+	buf     []responses.ExecutionResponse
+	current *Execution
+}
+
+// Next advances the iterator to the next execution, fetching another page from the server if needed
+// It returns false when there are no more results or an error occurred
+func (it *ExecutionIterator) Next() bool {
+	if it.err != nil || it.limitReached() {
+		return false
+	}
+	if len(it.buf) == 0 {
+		if it.exhausted {
+			return false
+		}
+		params, requested := it.nextPageParams()
+		data, err := it.fetch(it.ctx, params)
+		if err != nil {
+			it.err = err
+			return false
+		}
+		it.buf = data.Executions
+		it.pageFetched(requested, len(data.Executions), &data.Paging)
+		if len(it.buf) == 0 {
+			return false
+		}
+	}
+	it.current = &Execution{it.buf[0]}
+	it.buf = it.buf[1:]
+	it.returned++
+	return true
+}
+
+// Execution returns the current execution
+func (it *ExecutionIterator) Execution() *Execution {
+	return it.current
+}
+
+// HistoryEvent represents an individual project history event
+type HistoryEvent struct {
+	responses.HistoryEventResponse
+}
+
+// HistoryIterator iterates over a paged project history
+type HistoryIterator struct {
+	pager
+	fetch   func(ctx context.Context, params url.Values) (*History, error)
+	buf     []*responses.HistoryEventResponse
+	current *HistoryEvent
+}
+
+// Next advances the iterator to the next event, fetching another page from the server if needed
+// It returns false when there are no more results or an error occurred
+func (it *HistoryIterator) Next() bool {
+	if it.err != nil || it.limitReached() {
+		return false
+	}
+	if len(it.buf) == 0 {
+		if it.exhausted {
+			return false
+		}
+		params, requested := it.nextPageParams()
+		data, err := it.fetch(it.ctx, params)
+		if err != nil {
+			it.err = err
+			return false
+		}
+		it.buf = data.Events
+		it.pageFetched(requested, len(data.Events), data.Paging)
+		if len(it.buf) == 0 {
+			return false
+		}
+	}
+	it.current = &HistoryEvent{*it.buf[0]}
+	it.buf = it.buf[1:]
+	it.returned++
+	return true
+}
+
+// Event returns the current history event
+func (it *HistoryIterator) Event() *HistoryEvent {
+	return it.current
+}
@@ -0,0 +1,140 @@
+package rundeck
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// testPagedExecutionsHandler serves total executions honoring max and offset
+func testPagedExecutionsHandler(total int, requests *int) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		*requests++
+		max, _ := strconv.Atoi(r.URL.Query().Get("max"))
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		executions := []string{}
+		for i := offset; i < offset+max && i < total; i++ {
+			executions = append(executions, fmt.Sprintf(`{"id":%d}`, i+1))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprintf(w, `{"paging":{"count":%d,"total":%d,"offset":%d,"max":%d},"executions":[%s]}`,
+			len(executions), total, offset, max, strings.Join(executions, ","))
+	}
+}
+
+func TestIterateProjectExecutions(t *testing.T) {
+	requests := 0
+	client, server, cErr := newTestRundeckClientWithHandler(testPagedExecutionsHandler(25, &requests))
+	defer server.Close()
+	require.NoError(t, cErr)
+	it := client.IterateProjectExecutions("testproject", map[string]string{"statusFilter": "failed"}, PageSize(10))
+	ids := []int{}
+	for it.Next() {
+		ids = append(ids, it.Execution().ID)
+	}
+	require.NoError(t, it.Err())
+	require.Len(t, ids, 25)
+	require.Equal(t, 1, ids[0])
+	require.Equal(t, 25, ids[24])
+	require.Equal(t, 3, requests)
+}
+
+func TestIterateProjectExecutionsLimit(t *testing.T) {
+	requests := 0
+	var maxes []string
+	client, server, cErr := newTestRundeckClientWithHandler(func(w http.ResponseWriter, r *http.Request) {
+		maxes = append(maxes, r.URL.Query().Get("max"))
+		testPagedExecutionsHandler(100, &requests)(w, r)
+	})
+	defer server.Close()
+	require.NoError(t, cErr)
+	it := client.IterateProjectExecutions("testproject", nil, PageSize(10), PageLimit(15))
+	count := 0
+	for it.Next() {
+		count++
+	}
+	require.NoError(t, it.Err())
+	require.Equal(t, 15, count)
+	require.Equal(t, []string{"10", "5"}, maxes)
+}
+
+func TestIterateProjectExecutionsExactPage(t *testing.T) {
+	requests := 0
+	client, server, cErr := newTestRundeckClientWithHandler(testPagedExecutionsHandler(20, &requests))
+	defer server.Close()
+	require.NoError(t, cErr)
+	it := client.IterateProjectExecutions("testproject", nil, PageSize(10))
+	count := 0
+	for it.Next() {
+		count++
+	}
+	require.NoError(t, it.Err())
+	require.Equal(t, 20, count)
+	require.Equal(t, 2, requests)
+}
+
+func TestIterateProjectExecutionsHTTPError(t *testing.T) {
+	client, server, cErr := newTestRundeckClient([]byte(""), "application/json", 500)
+	defer server.Close()
+	require.NoError(t, cErr)
+	it := client.IterateProjectExecutions("testproject", nil)
+	require.False(t, it.Next())
+	require.Error(t, it.Err())
+}
+
+func TestIterateProjectExecutionsInvalidOption(t *testing.T) {
+	client, server, cErr := newTestRundeckClient([]byte(""), "application/json", 200)
+	defer server.Close()
+	require.NoError(t, cErr)
+	it := client.IterateProjectExecutions("testproject", nil, PageSize(0))
+	require.False(t, it.Next())
+	require.IsType(t, &OptionError{}, it.Err())
+}
+
+func TestIterateJobExecutions(t *testing.T) {
+	requests := 0
+	client, server, cErr := newTestRundeckClientWithHandler(func(w http.ResponseWriter, r *http.Request) {
+		require.True(t, strings.HasSuffix(r.URL.Path, "/job/abc123/executions"))
+		testPagedExecutionsHandler(5, &requests)(w, r)
+	})
+	defer server.Close()
+	require.NoError(t, cErr)
+	it := client.IterateJobExecutions("abc123", nil)
+	count := 0
+	for it.Next() {
+		count++
+	}
+	require.NoError(t, it.Err())
+	require.Equal(t, 5, count)
+	require.Equal(t, 1, requests)
+}
+
+func TestIterateHistory(t *testing.T) {
+	requests := 0
+	client, server, cErr := newTestRundeckClientWithHandler(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		max, _ := strconv.Atoi(r.URL.Query().Get("max"))
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		events := []string{}
+		for i := offset; i < offset+max && i < 7; i++ {
+			events = append(events, fmt.Sprintf(`{"title":"event-%d"}`, i))
+		}
+		_, _ = fmt.Fprintf(w, `{"paging":{"count":%d,"total":7,"offset":%d,"max":%d},"events":[%s]}`,
+			len(events), offset, max, strings.Join(events, ","))
+	})
+	defer server.Close()
+	require.NoError(t, cErr)
+	it := client.IterateHistory("testproject", nil, PageSize(3))
+	titles := []string{}
+	for it.Next() {
+		titles = append(titles, it.Event().Title)
+	}
+	require.NoError(t, it.Err())
+	require.Len(t, titles, 7)
+	require.Equal(t, "event-6", titles[6])
+	require.Equal(t, 3, requests)
+}
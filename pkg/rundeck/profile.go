@@ -0,0 +1,118 @@
+package rundeck
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+
+	multierror "github.com/hashicorp/go-multierror"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// ProfilesFileEnv is the environment variable that overrides where profiles are read from
+const ProfilesFileEnv = "RUNDECK_PROFILES"
+
+// Profile is a named set of connection settings for a rundeck server
+// A profile needs a url and either a token or a username and password
+type Profile struct {
+	URL      string `yaml:"url"`
+	Token    string `yaml:"token,omitempty"`
+	Username string `yaml:"username,omitempty"`
+	Password string `yaml:"password,omitempty"`
+	// Version is the api version to use. It defaults to the newest supported version
+	Version  string `yaml:"version,omitempty"`
+	Insecure bool   `yaml:"insecure,omitempty"`
+}
+
+// ProfilesFile returns the file profiles are read from
+// This is $RUNDECK_PROFILES if it is set and ~/.rundeck/profiles.yaml otherwise
+func ProfilesFile() (string, error) {
+	if f := os.Getenv(ProfilesFileEnv); f != "" {
+		return f, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".rundeck", "profiles.yaml"), nil
+}
+
+// LoadProfiles reads a yaml file mapping profile names to their settings
+func LoadProfiles(path string) (map[string]*Profile, error) {
+	data, err := ioutil.ReadFile(path) // nolint: gosec
+	if err != nil {
+		return nil, err
+	}
+	profiles := map[string]*Profile{}
+	if err := yaml.UnmarshalStrict(data, &profiles); err != nil {
+		return nil, &UnmarshalError{msg: multierror.Append(errDecoding, fmt.Errorf("%s: %s", path, err)).Error()}
+	}
+	return profiles, nil
+}
+
+// NewClientFromProfile returns a new client using a profile from the profiles file
+func NewClientFromProfile(name string) (*Client, error) {
+	path, err := ProfilesFile()
+	if err != nil {
+		return nil, err
+	}
+	profiles, err := LoadProfiles(path)
+	if err != nil {
+		return nil, err
+	}
+	profile, ok := profiles[name]
+	if !ok {
+		names := make([]string, 0, len(profiles))
+		for n := range profiles {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		return nil, fmt.Errorf("no profile named %q in %s. available profiles: %v", name, path, names)
+	}
+	return NewClientFromProfileSettings(profile)
+}
+
+// NewClientFromProfileSettings returns a new client for a profile
+func NewClientFromProfileSettings(p *Profile) (*Client, error) {
+	config, err := defaultClientConfig()
+	if err != nil {
+		return nil, err
+	}
+	if p.URL == "" {
+		return nil, fmt.Errorf("profile has no url")
+	}
+	config.BaseURL = p.URL
+	switch {
+	case p.Token != "":
+		config.AuthMethod = tokenAuthType
+		config.Token = p.Token
+	case p.Username != "" && p.Password != "":
+		config.AuthMethod = basicAuthType
+		config.Username = p.Username
+		config.Password = p.Password
+	default:
+		return nil, fmt.Errorf("profile needs either a token or a username and password")
+	}
+	if p.Version != "" {
+		intVer, err := strconv.Atoi(p.Version)
+		if err != nil {
+			return nil, err
+		}
+		if intVer < minJSONSupportedAPIVersion {
+			return nil, fmt.Errorf("minimum api version supported is %d", minJSONSupportedAPIVersion)
+		}
+		config.APIVersion = p.Version
+	}
+	config.VerifySSL = !p.Insecure
+	client, err := NewClient(config)
+	if err != nil {
+		return nil, err
+	}
+	if !client.Config.VerifySSL {
+		client.setInsecure()
+	}
+	return client, nil
+}
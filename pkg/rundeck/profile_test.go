@@ -0,0 +1,80 @@
+package rundeck
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testProfiles = `
+old:
+  url: http://old.example.com:4440
+  token: abcdefg
+  version: "24"
+new:
+  url: https://new.example.com
+  username: admin
+  password: admin
+  insecure: true
+broken:
+  url: http://broken.example.com
+`
+
+func testProfilesFile(t *testing.T, content string) func() {
+	dir, err := ioutil.TempDir("", "profiles")
+	require.NoError(t, err)
+	path := filepath.Join(dir, "profiles.yaml")
+	require.NoError(t, ioutil.WriteFile(path, []byte(content), 0600))
+	_ = os.Setenv(ProfilesFileEnv, path)
+	return func() {
+		_ = os.Unsetenv(ProfilesFileEnv)
+		_ = os.RemoveAll(dir)
+	}
+}
+
+func TestNewClientFromProfile(t *testing.T) {
+	defer testProfilesFile(t, testProfiles)()
+
+	client, err := NewClientFromProfile("old")
+	require.NoError(t, err)
+	require.Equal(t, "token", client.Config.AuthMethod)
+	require.Equal(t, "abcdefg", client.Config.Token)
+	require.Equal(t, "24", client.Config.APIVersion)
+	require.Equal(t, "http://old.example.com:4440", client.Config.BaseURL)
+	require.True(t, client.Config.VerifySSL)
+
+	client, err = NewClientFromProfile("new")
+	require.NoError(t, err)
+	require.Equal(t, "basic", client.Config.AuthMethod)
+	require.Equal(t, MaxRundeckVersion, client.Config.APIVersion)
+	require.False(t, client.Config.VerifySSL)
+
+	_, err = NewClientFromProfile("broken")
+	require.Error(t, err)
+
+	_, err = NewClientFromProfile("missing")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "[broken new old]")
+}
+
+func TestNewClientFromProfileInvalid(t *testing.T) {
+	defer testProfilesFile(t, "old:\n  url: http://localhost\n  tokn: typo\n")()
+	_, err := NewClientFromProfile("old")
+	require.IsType(t, &UnmarshalError{}, err)
+
+	_ = os.Setenv(ProfilesFileEnv, "/does/not/exist.yaml")
+	_, err = NewClientFromProfile("old")
+	require.Error(t, err)
+}
+
+func TestNewClientFromProfileSettingsVersion(t *testing.T) {
+	_, err := NewClientFromProfileSettings(&Profile{URL: "http://localhost", Token: "a", Version: "x"})
+	require.Error(t, err)
+	_, err = NewClientFromProfileSettings(&Profile{URL: "http://localhost", Token: "a", Version: "11"})
+	require.Error(t, err)
+	_, err = NewClientFromProfileSettings(&Profile{Token: "a"})
+	require.Error(t, err)
+}
@@ -2,6 +2,7 @@ package rundeck
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -120,11 +121,16 @@ func ProjectImportJobUUIDs(s string) ProjectImportOption {
 // GetProjectInfo gets a project by name
 // http://rundeck.org/docs/api/index.html#getting-project-info
 func (c *Client) GetProjectInfo(name string) (*Project, error) {
+	return c.GetProjectInfoContext(context.Background(), name)
+}
+
+// GetProjectInfoContext gets a project by name
+func (c *Client) GetProjectInfoContext(ctx context.Context, name string) (*Project, error) {
 	if err := c.checkRequiredAPIVersion(responses.ProjectInfoResponse{}); err != nil {
 		return nil, err
 	}
 	p := &responses.ProjectInfoResponse{}
-	res, err := c.httpGet("project/"+name, requestJSON(), requestExpects(200))
+	res, err := c.httpGet(ctx, "project/"+name, requestJSON(), requestExpects(200))
 	if err != nil {
 		return nil, err
 	}
@@ -144,11 +150,16 @@ func (c *Client) GetProjectInfo(name string) (*Project, error) {
 // ListProjects lists all projects
 // http://rundeck.org/docs/api/index.html#listing-projects
 func (c *Client) ListProjects() (Projects, error) {
+	return c.ListProjectsContext(context.Background())
+}
+
+// ListProjectsContext lists all projects
+func (c *Client) ListProjectsContext(ctx context.Context) (Projects, error) {
 	if err := c.checkRequiredAPIVersion(responses.ListProjectsResponse{}); err != nil {
 		return nil, err
 	}
 	data := &responses.ListProjectsResponse{}
-	res, err := c.httpGet("projects", requestJSON(), requestExpects(200))
+	res, err := c.httpGet(ctx, "projects", requestJSON(), requestExpects(200))
 	if err != nil {
 		return nil, err
 	}
@@ -169,6 +180,11 @@ func (c *Client) ListProjects() (Projects, error) {
 // CreateProject makes a project
 // http://rundeck.org/docs/api/index.html#project-creation
 func (c *Client) CreateProject(name string, properties map[string]string) (*Project, error) {
+	return c.CreateProjectContext(context.Background(), name, properties)
+}
+
+// CreateProjectContext makes a project
+func (c *Client) CreateProjectContext(ctx context.Context, name string, properties map[string]string) (*Project, error) {
 	if err := c.checkRequiredAPIVersion(responses.ProjectInfoResponse{}); err != nil {
 		return nil, err
 	}
@@ -178,7 +194,7 @@ func (c *Client) CreateProject(name string, properties map[string]string) (*Proj
 	}
 	data, _ := json.Marshal(req)
 	info := &responses.ProjectInfoResponse{}
-	res, postErr := c.httpPost("projects", requestJSON(), withBody(bytes.NewReader(data)), requestExpects(201))
+	res, postErr := c.httpPost(ctx, "projects", requestJSON(), withBody(bytes.NewReader(data)), requestExpects(201))
 	if postErr != nil {
 		return nil, postErr
 	}
@@ -197,22 +213,32 @@ func (c *Client) CreateProject(name string, properties map[string]string) (*Proj
 // DeleteProject deletes a project
 // http://rundeck.org/docs/api/index.html#project-deletion
 func (c *Client) DeleteProject(p string) error {
+	return c.DeleteProjectContext(context.Background(), p)
+}
+
+// DeleteProjectContext deletes a project
+func (c *Client) DeleteProjectContext(ctx context.Context, p string) error {
 	if err := c.checkRequiredAPIVersion(responses.ProjectInfoResponse{}); err != nil {
 		return err
 	}
 	url := fmt.Sprintf("project/%s", p)
-	_, err := c.httpDelete(url, requestJSON(), requestExpects(204))
+	_, err := c.httpDelete(ctx, url, requestJSON(), requestExpects(204))
 	return err
 }
 
 // GetProjectConfiguration gets a project's configuration
 // http://rundeck.org/docs/api/index.html#get-project-configuration
 func (c *Client) GetProjectConfiguration(p string) (map[string]string, error) {
+	return c.GetProjectConfigurationContext(context.Background(), p)
+}
+
+// GetProjectConfigurationContext gets a project's configuration
+func (c *Client) GetProjectConfigurationContext(ctx context.Context, p string) (map[string]string, error) {
 	if err := c.checkRequiredAPIVersion(responses.ProjectConfigResponse{}); err != nil {
 		return nil, err
 	}
 	data := map[string]string{}
-	res, err := c.httpGet("project/"+p+"/config", requestExpects(200), requestJSON())
+	res, err := c.httpGet(ctx, "project/"+p+"/config", requestExpects(200), requestJSON())
 	if err != nil {
 		return nil, err
 	}
@@ -225,6 +251,11 @@ func (c *Client) GetProjectConfiguration(p string) (map[string]string, error) {
 // PutProjectConfiguration replaces all configuration data with the submitted values
 // http://rundeck.org/docs/api/index.html#put-project-configuration
 func (c *Client) PutProjectConfiguration(projectName string, config map[string]string) (map[string]string, error) {
+	return c.PutProjectConfigurationContext(context.Background(), projectName, config)
+}
+
+// PutProjectConfigurationContext replaces all configuration data with the submitted values
+func (c *Client) PutProjectConfigurationContext(ctx context.Context, projectName string, config map[string]string) (map[string]string, error) {
 	if err := c.checkRequiredAPIVersion(responses.ProjectConfigResponse{}); err != nil {
 		return nil, err
 	}
@@ -233,7 +264,7 @@ func (c *Client) PutProjectConfiguration(projectName string, config map[string]s
 	if mErr != nil {
 		return nil, mErr
 	}
-	res, err := c.httpPut("project/"+projectName+"/config", withBody(bytes.NewReader(body)), requestExpects(200), requestJSON())
+	res, err := c.httpPut(ctx, "project/"+projectName+"/config", withBody(bytes.NewReader(body)), requestExpects(200), requestJSON())
 	if err != nil {
 		return nil, err
 	}
@@ -247,39 +278,59 @@ func (c *Client) PutProjectConfiguration(projectName string, config map[string]s
 // GetProjectConfigurationKey gets a specific configuration key
 // http://rundeck.org/docs/api/index.html#get-project-configuration-key
 func (c *Client) GetProjectConfigurationKey(projectName, key string) (string, error) {
+	return c.GetProjectConfigurationKeyContext(context.Background(), projectName, key)
+}
+
+// GetProjectConfigurationKeyContext gets a specific configuration key
+func (c *Client) GetProjectConfigurationKeyContext(ctx context.Context, projectName, key string) (string, error) {
 	if err := c.checkRequiredAPIVersion(responses.ProjectConfigItemResponse{}); err != nil {
 		return "", err
 	}
 	u := fmt.Sprintf("project/%s/config/%s", projectName, key)
-	res, err := c.httpGet(u, requestExpects(200), contentType("text/plain"))
+	res, err := c.httpGet(ctx, u, requestExpects(200), contentType("text/plain"))
 	return string(res), err
 }
 
 // PutProjectConfigurationKey sets a value for a configuration key
 // http://rundeck.org/docs/api/index.html#put-project-configuration-key
 func (c *Client) PutProjectConfigurationKey(projectName, key, value string) error {
+	return c.PutProjectConfigurationKeyContext(context.Background(), projectName, key, value)
+}
+
+// PutProjectConfigurationKeyContext sets a value for a configuration key
+func (c *Client) PutProjectConfigurationKeyContext(ctx context.Context, projectName, key, value string) error {
 	if err := c.checkRequiredAPIVersion(responses.ProjectConfigItemResponse{}); err != nil {
 		return err
 	}
 	u := fmt.Sprintf("project/%s/config/%s", projectName, key)
-	_, err := c.httpPut(u, withBody(bytes.NewReader([]byte(value))), requestExpects(200), contentType("text/plain"))
+	_, err := c.httpPut(ctx, u, withBody(bytes.NewReader([]byte(value))), requestExpects(200), contentType("text/plain"))
 	return err
 }
 
 // DeleteProjectConfigurationKey deletes a configuration key
 // http://rundeck.org/docs/api/index.html#delete-project-configuration-key
 func (c *Client) DeleteProjectConfigurationKey(projectName, key string) error {
+	return c.DeleteProjectConfigurationKeyContext(context.Background(), projectName, key)
+}
+
+// DeleteProjectConfigurationKeyContext deletes a configuration key
+func (c *Client) DeleteProjectConfigurationKeyContext(ctx context.Context, projectName, key string) error {
 	if err := c.checkRequiredAPIVersion(responses.ProjectConfigItemResponse{}); err != nil {
 		return err
 	}
 	u := fmt.Sprintf("project/%s/config/%s", projectName, key)
-	_, err := c.httpDelete(u, requestExpects(204))
+	_, err := c.httpDelete(ctx, u, requestExpects(204))
 	return err
 }
 
 // GetProjectArchiveExport export exports a zip file of the project
 // http://rundeck.org/docs/api/index.html#project-archive-export
 func (c *Client) GetProjectArchiveExport(p string, w io.Writer, opts ...ProjectExportOption) error {
+	return c.GetProjectArchiveExportContext(context.Background(), p, w, opts...)
+}
+
+// GetProjectArchiveExportContext export exports a zip file of the project
+func (c *Client) GetProjectArchiveExportContext(ctx context.Context, p string, w io.Writer, opts ...ProjectExportOption) error {
 	if err := c.checkRequiredAPIVersion(responses.ProjectInfoResponse{}); err != nil {
 		return err
 	}
@@ -294,19 +345,23 @@ func (c *Client) GetProjectArchiveExport(p string, w io.Writer, opts ...ProjectE
 	}
 
 	u := fmt.Sprintf("project/%s/export", p)
-	res, resErr := c.httpGet(u, requestExpects(200), accept("application/zip"), queryParams(*params))
+	res, resErr := c.httpGetStream(ctx, u, requestExpects(200), accept("application/zip"), queryParams(*params))
 	if resErr != nil {
 		return resErr
 	}
-	if _, wErr := w.Write(res); wErr != nil {
-		return wErr
-	}
-	return nil
+	defer func() { _ = res.Close() }()
+	_, wErr := io.Copy(w, res)
+	return wErr
 }
 
 // GetProjectArchiveExportAsync export a zip archive of a project async
 // http://rundeck.org/docs/api/index.html#project-archive-export-async
 func (c *Client) GetProjectArchiveExportAsync(p string, opts ...ProjectExportOption) (string, error) {
+	return c.GetProjectArchiveExportAsyncContext(context.Background(), p, opts...)
+}
+
+// GetProjectArchiveExportAsyncContext export a zip archive of a project async
+func (c *Client) GetProjectArchiveExportAsyncContext(ctx context.Context, p string, opts ...ProjectExportOption) (string, error) {
 	if err := c.checkRequiredAPIVersion(responses.ProjectInfoResponse{}); err != nil {
 		return "", err
 	}
@@ -321,7 +376,7 @@ func (c *Client) GetProjectArchiveExportAsync(p string, opts ...ProjectExportOpt
 	}
 
 	u := fmt.Sprintf("project/%s/export/async", p)
-	res, resErr := c.httpGet(u, requestExpects(200), contentType("application/x-www-form-urlencoded"), accept("application/json"), queryParams(*params))
+	res, resErr := c.httpGet(ctx, u, requestExpects(200), contentType("application/x-www-form-urlencoded"), accept("application/json"), queryParams(*params))
 	if resErr != nil {
 		return "", resErr
 	}
@@ -335,12 +390,17 @@ func (c *Client) GetProjectArchiveExportAsync(p string, opts ...ProjectExportOpt
 // GetProjectArchiveExportAsyncStatus gets the status of an async export request
 // http://rundeck.org/docs/api/index.html#project-archive-export-async-status
 func (c *Client) GetProjectArchiveExportAsyncStatus(p, token string) (*responses.ProjectArchiveExportAsyncResponse, error) {
+	return c.GetProjectArchiveExportAsyncStatusContext(context.Background(), p, token)
+}
+
+// GetProjectArchiveExportAsyncStatusContext gets the status of an async export request
+func (c *Client) GetProjectArchiveExportAsyncStatusContext(ctx context.Context, p, token string) (*responses.ProjectArchiveExportAsyncResponse, error) {
 	if err := c.checkRequiredAPIVersion(responses.ProjectInfoResponse{}); err != nil {
 		return nil, err
 	}
 
 	u := fmt.Sprintf("project/%s/export/status/%s", p, token)
-	res, resErr := c.httpGet(u, requestExpects(200), requestJSON())
+	res, resErr := c.httpGet(ctx, u, requestExpects(200), requestJSON())
 	if resErr != nil {
 		return nil, resErr
 	}
@@ -354,24 +414,33 @@ func (c *Client) GetProjectArchiveExportAsyncStatus(p, token string) (*responses
 // GetProjectArchiveExportAsyncDownload downloads an async project export archive file
 // http://rundeck.org/docs/api/index.html#project-archive-export-async-download
 func (c *Client) GetProjectArchiveExportAsyncDownload(p, token string, w io.Writer) error {
+	return c.GetProjectArchiveExportAsyncDownloadContext(context.Background(), p, token, w)
+}
+
+// GetProjectArchiveExportAsyncDownloadContext downloads an async project export archive file
+func (c *Client) GetProjectArchiveExportAsyncDownloadContext(ctx context.Context, p, token string, w io.Writer) error {
 	if err := c.checkRequiredAPIVersion(responses.ProjectInfoResponse{}); err != nil {
 		return err
 	}
 
 	u := fmt.Sprintf("project/%s/export/download/%s", p, token)
-	res, resErr := c.httpGet(u, requestExpects(200), accept("application/zip"))
+	res, resErr := c.httpGetStream(ctx, u, requestExpects(200), accept("application/zip"))
 	if resErr != nil {
 		return resErr
 	}
-	if _, wErr := w.Write(res); wErr != nil {
-		return wErr
-	}
-	return nil
+	defer func() { _ = res.Close() }()
+	_, wErr := io.Copy(w, res)
+	return wErr
 }
 
 // ProjectArchiveImport imports a zip archive to a project
 // http://rundeck.org/docs/api/index.html#project-archive-import
 func (c *Client) ProjectArchiveImport(projectName string, f io.Reader, opts ...ProjectImportOption) (*responses.ProjectImportArchiveResponse, error) {
+	return c.ProjectArchiveImportContext(context.Background(), projectName, f, opts...)
+}
+
+// ProjectArchiveImportContext imports a zip archive to a project
+func (c *Client) ProjectArchiveImportContext(ctx context.Context, projectName string, f io.Reader, opts ...ProjectImportOption) (*responses.ProjectImportArchiveResponse, error) {
 	if err := c.checkRequiredAPIVersion(responses.ProjectImportArchiveResponse{}); err != nil {
 		return nil, err
 	}
@@ -383,7 +452,7 @@ func (c *Client) ProjectArchiveImport(projectName string, f io.Reader, opts ...P
 			return nil, &OptionError{msg: multierror.Append(errOption, err).Error()}
 		}
 	}
-	res, resErr := c.httpPut(u,
+	res, resErr := c.httpPut(ctx, u,
 		withBody(f),
 		contentType("application/zip"),
 		accept("application/json"),
@@ -208,6 +208,23 @@ func TestGetProjectArchiveExport(t *testing.T) {
 	require.NoError(t, cerr)
 }
 
+func TestGetProjectArchiveExportStreams(t *testing.T) {
+	archive := bytes.Repeat([]byte("0123456789"), 100000)
+	client, server, _ := newTestRundeckClient(archive, "application/zip", 200)
+	defer server.Close()
+	var buf bytes.Buffer
+	require.NoError(t, client.GetProjectArchiveExport("testproject", &buf))
+	require.Equal(t, archive, buf.Bytes())
+}
+
+func TestGetProjectArchiveExportHTTPError(t *testing.T) {
+	client, server, _ := newTestRundeckClient([]byte(""), "application/zip", 500)
+	defer server.Close()
+	var buf bytes.Buffer
+	require.Error(t, client.GetProjectArchiveExport("testproject", &buf))
+	require.Equal(t, 0, buf.Len())
+}
+
 func TestGetProjectArchiveExportOptions(t *testing.T) {
 	client, server, _ := newTestRundeckClient([]byte("testdata"), "application/zip", 200)
 	defer server.Close()
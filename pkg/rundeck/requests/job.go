@@ -23,3 +23,8 @@ type RunJobRequest struct {
 	RunAtTime *JSONTime         `json:"runAtTime,omitempty"`
 	Options   map[string]string `json:"options,omitempty"`
 }
+
+// BulkJobDeleteRequest is the payload for deleting jobs in bulk
+type BulkJobDeleteRequest struct {
+	IDs []string `json:"ids"`
+}
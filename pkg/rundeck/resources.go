@@ -1,6 +1,7 @@
 package rundeck
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -23,11 +24,16 @@ type ResourceDetail struct {
 // ListResourcesForProject returns resources for a project (usually nodes)
 // http://rundeck.org/docs/api/index.html#list-resources-for-a-project
 func (c *Client) ListResourcesForProject(p string) (*Resources, error) {
+	return c.ListResourcesForProjectContext(context.Background(), p)
+}
+
+// ListResourcesForProjectContext returns resources for a project (usually nodes)
+func (c *Client) ListResourcesForProjectContext(ctx context.Context, p string) (*Resources, error) {
 	if err := c.checkRequiredAPIVersion(responses.ResourceCollectionResponse{}); err != nil {
 		return nil, err
 	}
 	ls := &Resources{}
-	data, err := c.httpGet("project/"+p+"/resources", requestJSON(), requestExpects(200))
+	data, err := c.httpGet(ctx, "project/"+p+"/resources", requestJSON(), requestExpects(200))
 	if err != nil {
 		return nil, err
 	}
@@ -40,11 +46,16 @@ func (c *Client) ListResourcesForProject(p string) (*Resources, error) {
 // GetResourceInfo get a specific resource within a project (usually a node)
 // http://rundeck.org/docs/api/index.html#getting-resource-info
 func (c *Client) GetResourceInfo(projectName, resourceName string) (*ResourceDetail, error) {
+	return c.GetResourceInfoContext(context.Background(), projectName, resourceName)
+}
+
+// GetResourceInfoContext get a specific resource within a project (usually a node)
+func (c *Client) GetResourceInfoContext(ctx context.Context, projectName, resourceName string) (*ResourceDetail, error) {
 	if err := c.checkRequiredAPIVersion(responses.ResourceDetailResponse{}); err != nil {
 		return nil, err
 	}
 	r := Resource{}
-	data, err := c.httpGet("project/"+projectName+"/resource/"+resourceName, requestJSON(), requestExpects(200))
+	data, err := c.httpGet(ctx, "project/"+projectName+"/resource/"+resourceName, requestJSON(), requestExpects(200))
 	if err != nil {
 		return nil, err
 	}
@@ -61,10 +72,15 @@ func (c *Client) GetResourceInfo(projectName, resourceName string) (*ResourceDet
 // GetProjectReadme gets a project's readme.md
 // http://rundeck.org/docs/api/index.html#get-readme-file
 func (c *Client) GetProjectReadme(projectName string) (string, error) {
+	return c.GetProjectReadmeContext(context.Background(), projectName)
+}
+
+// GetProjectReadmeContext gets a project's readme.md
+func (c *Client) GetProjectReadmeContext(ctx context.Context, projectName string) (string, error) {
 	if err := c.checkRequiredAPIVersion(responses.ResourceResponse{}); err != nil {
 		return "", err
 	}
-	data, err := c.httpGet("project/"+projectName+"/readme.md", accept("text/plain"), requestExpects(200))
+	data, err := c.httpGet(ctx, "project/"+projectName+"/readme.md", accept("text/plain"), requestExpects(200))
 	if err != nil {
 		return "", err
 	}
@@ -74,29 +90,44 @@ func (c *Client) GetProjectReadme(projectName string) (string, error) {
 // PutProjectReadme creates or modifies a project's readme.md
 // http://rundeck.org/docs/api/index.html#put-readme-file
 func (c *Client) PutProjectReadme(projectName string, readme io.Reader) error {
+	return c.PutProjectReadmeContext(context.Background(), projectName, readme)
+}
+
+// PutProjectReadmeContext creates or modifies a project's readme.md
+func (c *Client) PutProjectReadmeContext(ctx context.Context, projectName string, readme io.Reader) error {
 	if err := c.checkRequiredAPIVersion(responses.ResourceResponse{}); err != nil {
 		return err
 	}
-	_, err := c.httpPut("project/"+projectName+"/readme.md", withBody(readme), requestExpects(200), contentType("text/plain"))
+	_, err := c.httpPut(ctx, "project/"+projectName+"/readme.md", withBody(readme), requestExpects(200), contentType("text/plain"))
 	return err
 }
 
 // DeleteProjectReadme deletes a project's readme.md
 func (c *Client) DeleteProjectReadme(projectName string) error {
+	return c.DeleteProjectReadmeContext(context.Background(), projectName)
+}
+
+// DeleteProjectReadmeContext deletes a project's readme.md
+func (c *Client) DeleteProjectReadmeContext(ctx context.Context, projectName string) error {
 	if err := c.checkRequiredAPIVersion(responses.ResourceResponse{}); err != nil {
 		return err
 	}
-	_, err := c.httpDelete("project/"+projectName+"/readme.md", requestExpects(204))
+	_, err := c.httpDelete(ctx, "project/"+projectName+"/readme.md", requestExpects(204))
 	return err
 }
 
 // GetProjectMotd gets a project's Motd.md
 // http://rundeck.org/docs/api/index.html#get-readme-file
 func (c *Client) GetProjectMotd(projectName string) (string, error) {
+	return c.GetProjectMotdContext(context.Background(), projectName)
+}
+
+// GetProjectMotdContext gets a project's Motd.md
+func (c *Client) GetProjectMotdContext(ctx context.Context, projectName string) (string, error) {
 	if err := c.checkRequiredAPIVersion(responses.ResourceResponse{}); err != nil {
 		return "", err
 	}
-	data, err := c.httpGet("project/"+projectName+"/motd.md", accept("text/plain"), requestExpects(200))
+	data, err := c.httpGet(ctx, "project/"+projectName+"/motd.md", accept("text/plain"), requestExpects(200))
 	if err != nil {
 		return "", err
 	}
@@ -106,18 +137,28 @@ func (c *Client) GetProjectMotd(projectName string) (string, error) {
 // PutProjectMotd creates or modifies a project's motd.md
 // http://rundeck.org/docs/api/index.html#put-readme-file
 func (c *Client) PutProjectMotd(projectName string, motd io.Reader) error {
+	return c.PutProjectMotdContext(context.Background(), projectName, motd)
+}
+
+// PutProjectMotdContext creates or modifies a project's motd.md
+func (c *Client) PutProjectMotdContext(ctx context.Context, projectName string, motd io.Reader) error {
 	if err := c.checkRequiredAPIVersion(responses.ResourceResponse{}); err != nil {
 		return err
 	}
-	_, err := c.httpPut("project/"+projectName+"/motd.md", withBody(motd), requestExpects(200), contentType("text/plain"))
+	_, err := c.httpPut(ctx, "project/"+projectName+"/motd.md", withBody(motd), requestExpects(200), contentType("text/plain"))
 	return err
 }
 
 // DeleteProjectMotd deletes a project's motd.md
 func (c *Client) DeleteProjectMotd(projectName string) error {
+	return c.DeleteProjectMotdContext(context.Background(), projectName)
+}
+
+// DeleteProjectMotdContext deletes a project's motd.md
+func (c *Client) DeleteProjectMotdContext(ctx context.Context, projectName string) error {
 	if err := c.checkRequiredAPIVersion(responses.ResourceResponse{}); err != nil {
 		return err
 	}
-	_, err := c.httpDelete("project/"+projectName+"/motd.md", requestExpects(204))
+	_, err := c.httpDelete(ctx, "project/"+projectName+"/motd.md", requestExpects(204))
 	return err
 }
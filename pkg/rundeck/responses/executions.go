@@ -1,5 +1,7 @@
 package responses
 
+import "encoding/json"
+
 // JobExecutionsResponse is the response for listing the executions for a job
 type JobExecutionsResponse ListRunningExecutionsResponse
 
@@ -268,29 +270,48 @@ func (a AbortExecutionResponse) deprecated() bool { return false }
 
 // ExecutionOutputResponse is the response for getting execution output
 type ExecutionOutputResponse struct {
-	ID             string  `json:"id"`
-	Offset         string  `json:"offset"`
-	Completed      bool    `json:"completed"`
-	ExecCompleted  bool    `json:"execCompleted"`
-	HasFailedNodes bool    `json:"hasFailedNodes"`
-	ExecState      string  `json:"execState"`
-	LastModified   string  `json:"lastModified"`
-	ExecDuration   int     `json:"execDuration"`
-	PercentLoaded  float64 `json:"percentLoaded"`
-	TotalSize      int     `json:"totalSize"`
-	RetryBackoff   int     `json:"retryBackoff"`
-	ClusterExec    bool    `json:"clusterExec"`
-	ServerNodeUUID string  `json:"serverNodeUUID"`
-	Compacted      bool    `json:"compacted"`
-	Entries        []struct {
-		Time         string    `json:"time"`
-		AbsoluteTime *JSONTime `json:"absolute_time"`
-		Log          string    `json:"log"`
-		Level        string    `json:"level"`
-		User         string    `json:"user"`
-		StepCTX      string    `json:"stepctx"`
-		Node         string    `json:"node"`
-	} `json:"entries"`
+	ID             string                         `json:"id"`
+	Offset         string                         `json:"offset"`
+	Completed      bool                           `json:"completed"`
+	ExecCompleted  bool                           `json:"execCompleted"`
+	HasFailedNodes bool                           `json:"hasFailedNodes"`
+	ExecState      string                         `json:"execState"`
+	LastModified   string                         `json:"lastModified"`
+	ExecDuration   int                            `json:"execDuration"`
+	PercentLoaded  float64                        `json:"percentLoaded"`
+	TotalSize      int                            `json:"totalSize"`
+	RetryBackoff   int                            `json:"retryBackoff"`
+	ClusterExec    bool                           `json:"clusterExec"`
+	ServerNodeUUID string                         `json:"serverNodeUUID"`
+	Compacted      bool                           `json:"compacted"`
+	CompactedAttr  string                         `json:"compactedAttr"`
+	Entries        []ExecutionOutputEntryResponse `json:"entries"`
+}
+
+// ExecutionOutputEntryResponse is a single log entry in an execution output response
+type ExecutionOutputEntryResponse struct {
+	Time         string    `json:"time"`
+	AbsoluteTime *JSONTime `json:"absolute_time"`
+	Log          string    `json:"log"`
+	Level        string    `json:"level"`
+	User         string    `json:"user"`
+	StepCTX      string    `json:"stepctx"`
+	Node         string    `json:"node"`
+}
+
+// UnmarshalJSON decodes an entry which in compacted output may be just the log message
+func (e *ExecutionOutputEntryResponse) UnmarshalJSON(data []byte) error {
+	if len(data) > 0 && data[0] == '"' {
+		return json.Unmarshal(data, &e.Log)
+	}
+	// the alias keeps json from calling this method again
+	type entry ExecutionOutputEntryResponse
+	v := entry{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*e = ExecutionOutputEntryResponse(v)
+	return nil
 }
 
 // ExecutionOutputResponseTestFile is test data for getting an output execution response
@@ -122,6 +122,9 @@ func (a JobOptionFileUploadResponse) minVersion() int  { return 19 }
 func (a JobOptionFileUploadResponse) maxVersion() int  { return CurrentVersion }
 func (a JobOptionFileUploadResponse) deprecated() bool { return false }
 
+// UploadedJobInputFileResponseTestFile is the test data for a UploadedJobInputFileResponse
+const UploadedJobInputFileResponseTestFile = "upload_job_input_file.json"
+
 // UploadedJobInputFileResponse represents an entry in an UploadedJobInputFilesResponse
 type UploadedJobInputFileResponse struct {
 	ID             string    `json:"id"`
@@ -153,3 +156,25 @@ type UploadedJobInputFilesResponse struct {
 func (a UploadedJobInputFilesResponse) minVersion() int  { return 19 }
 func (a UploadedJobInputFilesResponse) maxVersion() int  { return CurrentVersion }
 func (a UploadedJobInputFilesResponse) deprecated() bool { return false }
+
+// JobForecastResponseTestFile is the test data for a JobForecastResponse
+const JobForecastResponseTestFile = "get_job_forecast.json"
+
+// JobForecastResponse represents the upcoming scheduled executions of a job
+type JobForecastResponse struct {
+	ID                        string      `json:"id"`
+	Name                      string      `json:"name"`
+	Group                     string      `json:"group"`
+	Project                   string      `json:"project"`
+	Description               string      `json:"description"`
+	HRef                      string      `json:"href"`
+	Permalink                 string      `json:"permalink"`
+	Scheduled                 bool        `json:"scheduled"`
+	ScheduleEnabled           bool        `json:"scheduleEnabled"`
+	Enabled                   bool        `json:"enabled"`
+	FutureScheduledExecutions []*JSONTime `json:"futureScheduledExecutions"`
+}
+
+func (a JobForecastResponse) minVersion() int  { return 31 }
+func (a JobForecastResponse) maxVersion() int  { return CurrentVersion }
+func (a JobForecastResponse) deprecated() bool { return false }
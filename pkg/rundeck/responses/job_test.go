@@ -45,6 +45,18 @@ func TestJobReponses(t *testing.T) {
 			obj: &UploadedJobInputFilesResponse{},
 			testfile: UploadedJobInputFilesResponseTestFile,
 		},
+		{
+			name: "UploadedJobInputFileResponse",
+			placeholder: make(map[string]interface{}),
+			obj: &UploadedJobInputFileResponse{},
+			testfile: UploadedJobInputFileResponseTestFile,
+		},
+		{
+			name: "JobForecastResponse",
+			placeholder: make(map[string]interface{}),
+			obj: &JobForecastResponse{},
+			testfile: JobForecastResponseTestFile,
+		},
 		{
 			name: "JobOptionFileUpload",
 			placeholder: make(map[string]interface{}),
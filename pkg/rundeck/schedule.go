@@ -2,6 +2,7 @@ package rundeck
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 
 	multierror "github.com/hashicorp/go-multierror"
@@ -12,11 +13,16 @@ import (
 // DisableSchedule disables a scheduled job
 // http://rundeck.org/docs/api/index.html#disable-scheduling-for-a-job
 func (c *Client) DisableSchedule(id string) (bool, error) {
+	return c.DisableScheduleContext(context.Background(), id)
+}
+
+// DisableScheduleContext disables a scheduled job
+func (c *Client) DisableScheduleContext(ctx context.Context, id string) (bool, error) {
 	if err := c.checkRequiredAPIVersion(responses.ToggleResponse{}); err != nil {
 		return false, err
 	}
 	t := &responses.ToggleResponse{}
-	res, err := c.httpPost("job/"+id+"/schedule/disable", requestJSON(), requestExpects(200))
+	res, err := c.httpPost(ctx, "job/"+id+"/schedule/disable", requestJSON(), requestExpects(200))
 	if err != nil {
 		return false, err
 	}
@@ -29,11 +35,16 @@ func (c *Client) DisableSchedule(id string) (bool, error) {
 // EnableSchedule enables a scheduled job
 // http://rundeck.org/docs/api/index.html#enable-scheduling-for-a-job
 func (c *Client) EnableSchedule(id string) (bool, error) {
+	return c.EnableScheduleContext(context.Background(), id)
+}
+
+// EnableScheduleContext enables a scheduled job
+func (c *Client) EnableScheduleContext(ctx context.Context, id string) (bool, error) {
 	if err := c.checkRequiredAPIVersion(responses.ToggleResponse{}); err != nil {
 		return false, err
 	}
 	t := &responses.ToggleResponse{}
-	res, err := c.httpPost("job/"+id+"/schedule/enable", requestExpects(200), requestJSON())
+	res, err := c.httpPost(ctx, "job/"+id+"/schedule/enable", requestExpects(200), requestJSON())
 	if err != nil {
 		return false, err
 	}
@@ -46,6 +57,11 @@ func (c *Client) EnableSchedule(id string) (bool, error) {
 // BulkEnableSchedule enables scheduled jobs in bulk
 // http://rundeck.org/docs/api/index.html#bulk-toggle-job-schedules
 func (c *Client) BulkEnableSchedule(ids ...string) (*responses.BulkToggleResponse, error) {
+	return c.BulkEnableScheduleContext(context.Background(), ids...)
+}
+
+// BulkEnableScheduleContext enables scheduled jobs in bulk
+func (c *Client) BulkEnableScheduleContext(ctx context.Context, ids ...string) (*responses.BulkToggleResponse, error) {
 	if err := c.checkRequiredAPIVersion(responses.BulkToggleResponse{}); err != nil {
 		return nil, err
 	}
@@ -54,7 +70,7 @@ func (c *Client) BulkEnableSchedule(ids ...string) (*responses.BulkToggleRespons
 	}
 	results := &responses.BulkToggleResponse{}
 	data, _ := json.Marshal(req)
-	res, err := c.httpPost("jobs/schedule/enable",
+	res, err := c.httpPost(ctx, "jobs/schedule/enable",
 		withBody(bytes.NewReader(data)),
 		requestJSON(),
 		requestExpects(200))
@@ -70,6 +86,11 @@ func (c *Client) BulkEnableSchedule(ids ...string) (*responses.BulkToggleRespons
 // BulkDisableSchedule enables scheduled jobs in bulk
 // http://rundeck.org/docs/api/index.html#bulk-toggle-job-schedules
 func (c *Client) BulkDisableSchedule(ids ...string) (*responses.BulkToggleResponse, error) {
+	return c.BulkDisableScheduleContext(context.Background(), ids...)
+}
+
+// BulkDisableScheduleContext enables scheduled jobs in bulk
+func (c *Client) BulkDisableScheduleContext(ctx context.Context, ids ...string) (*responses.BulkToggleResponse, error) {
 	if err := c.checkRequiredAPIVersion(responses.BulkToggleResponse{}); err != nil {
 		return nil, err
 	}
@@ -78,7 +99,7 @@ func (c *Client) BulkDisableSchedule(ids ...string) (*responses.BulkToggleRespon
 	}
 	results := &responses.BulkToggleResponse{}
 	data, _ := json.Marshal(req)
-	res, err := c.httpPost("jobs/schedule/disable",
+	res, err := c.httpPost(ctx, "jobs/schedule/disable",
 		withBody(bytes.NewReader(data)),
 		requestJSON(),
 		requestExpects(200))
@@ -2,6 +2,7 @@ package rundeck
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 
@@ -55,17 +56,22 @@ type SCMJobDiff struct {
 // http://rundeck.org/docs/api/index.html#list-scm-plugins
 // One minor customization we do here is to return both import and export in this for a nicer bit of sugar
 func (c *Client) ListSCMPlugins(projectName string) (*SCMPlugins, error) {
+	return c.ListSCMPluginsContext(context.Background(), projectName)
+}
+
+// ListSCMPluginsContext list the available plugins for the specified integration
+func (c *Client) ListSCMPluginsContext(ctx context.Context, projectName string) (*SCMPlugins, error) {
 	if err := c.checkRequiredAPIVersion(responses.SCMResponse{}); err != nil {
 		return nil, err
 	}
 	importPluginsURL := fmt.Sprintf("project/%s/scm/import/plugins", projectName)
 	exportPluginsURL := fmt.Sprintf("project/%s/scm/export/plugins", projectName)
 	plugins := &SCMPlugins{}
-	impRes, impErr := c.httpGet(importPluginsURL, accept("application/json"), requestExpects(200))
+	impRes, impErr := c.httpGet(ctx, importPluginsURL, accept("application/json"), requestExpects(200))
 	if impErr != nil {
 		return nil, impErr
 	}
-	expRes, expErr := c.httpGet(exportPluginsURL, accept("application/json"), requestExpects(200))
+	expRes, expErr := c.httpGet(ctx, exportPluginsURL, accept("application/json"), requestExpects(200))
 	if expErr != nil {
 		return nil, expErr
 	}
@@ -85,13 +91,18 @@ func (c *Client) ListSCMPlugins(projectName string) (*SCMPlugins, error) {
 // GetProjectSCMPluginInputFields List the input fields for a specific plugin.
 // http://rundeck.org/docs/api/index.html#get-scm-plugin-input-fields
 func (c *Client) GetProjectSCMPluginInputFields(projectName, integration, pluginType string) (*SCMPluginInputFields, error) {
+	return c.GetProjectSCMPluginInputFieldsContext(context.Background(), projectName, integration, pluginType)
+}
+
+// GetProjectSCMPluginInputFieldsContext List the input fields for a specific plugin.
+func (c *Client) GetProjectSCMPluginInputFieldsContext(ctx context.Context, projectName, integration, pluginType string) (*SCMPluginInputFields, error) {
 	if err := c.checkRequiredAPIVersion(responses.SCMResponse{}); err != nil {
 		return nil, err
 	}
 	// [PROJECT]/scm/[INTEGRATION]/plugin/[TYPE]/input
 	u := fmt.Sprintf("project/%s/scm/%s/plugin/%s/input", projectName, integration, pluginType)
 	fields := &SCMPluginInputFields{}
-	res, resErr := c.httpGet(u, accept("application/json"), requestExpects(200))
+	res, resErr := c.httpGet(ctx, u, accept("application/json"), requestExpects(200))
 	if resErr != nil {
 		return nil, resErr
 	}
@@ -104,6 +115,11 @@ func (c *Client) GetProjectSCMPluginInputFields(projectName, integration, plugin
 // SetupSCMPluginForProject configures and enables a plugin for a project
 // http://rundeck.org/docs/api/index.html#setup-scm-plugin-for-a-project
 func (c *Client) SetupSCMPluginForProject(project, integration, pluginType string, params map[string]string) (*SCMPluginForProject, error) {
+	return c.SetupSCMPluginForProjectContext(context.Background(), project, integration, pluginType, params)
+}
+
+// SetupSCMPluginForProjectContext configures and enables a plugin for a project
+func (c *Client) SetupSCMPluginForProjectContext(ctx context.Context, project, integration, pluginType string, params map[string]string) (*SCMPluginForProject, error) {
 	if err := c.checkRequiredAPIVersion(responses.SCMResponse{}); err != nil {
 		return nil, err
 	}
@@ -116,7 +132,7 @@ func (c *Client) SetupSCMPluginForProject(project, integration, pluginType strin
 		return nil, reqBodyErr
 	}
 	results := &SCMPluginForProject{}
-	res, respErr := c.httpPost(u, withBody(bytes.NewReader(reqBody)), requestJSON(), requestExpects(200), requestExpects(400))
+	res, respErr := c.httpPost(ctx, u, withBody(bytes.NewReader(reqBody)), requestJSON(), requestExpects(200), requestExpects(400))
 	if respErr != nil {
 		return nil, respErr
 	}
@@ -136,12 +152,17 @@ func (c *Client) SetupSCMPluginForProject(project, integration, pluginType strin
 // EnableSCMPluginForProject enables a plugin for a project
 // http://rundeck.org/docs/api/index.html#enable-scm-plugin-for-a-project
 func (c *Client) EnableSCMPluginForProject(project, integration, pluginType string) error {
+	return c.EnableSCMPluginForProjectContext(context.Background(), project, integration, pluginType)
+}
+
+// EnableSCMPluginForProjectContext enables a plugin for a project
+func (c *Client) EnableSCMPluginForProjectContext(ctx context.Context, project, integration, pluginType string) error {
 	if err := c.checkRequiredAPIVersion(responses.SCMResponse{}); err != nil {
 		return err
 	}
 	u := fmt.Sprintf("project/%s/scm/%s/plugin/%s/enable",
 		project, integration, pluginType)
-	res, err := c.httpPost(u, withBody(nil), requestJSON(), requestExpects(200), requestExpects(400))
+	res, err := c.httpPost(ctx, u, withBody(nil), requestJSON(), requestExpects(200), requestExpects(400))
 	if err != nil {
 		return err
 	}
@@ -162,12 +183,17 @@ func (c *Client) EnableSCMPluginForProject(project, integration, pluginType stri
 // DisableSCMPluginForProject disables a plugin for a project
 // http://rundeck.org/docs/api/index.html#enable-scm-plugin-for-a-project
 func (c *Client) DisableSCMPluginForProject(project, integration, pluginType string) error {
+	return c.DisableSCMPluginForProjectContext(context.Background(), project, integration, pluginType)
+}
+
+// DisableSCMPluginForProjectContext disables a plugin for a project
+func (c *Client) DisableSCMPluginForProjectContext(ctx context.Context, project, integration, pluginType string) error {
 	if err := c.checkRequiredAPIVersion(responses.SCMResponse{}); err != nil {
 		return err
 	}
 	u := fmt.Sprintf("project/%s/scm/%s/plugin/%s/disable",
 		project, integration, pluginType)
-	res, err := c.httpPost(u, withBody(nil), requestJSON(), requestExpects(200), requestExpects(400))
+	res, err := c.httpPost(ctx, u, withBody(nil), requestJSON(), requestExpects(200), requestExpects(400))
 	if err != nil {
 		return err
 	}
@@ -188,13 +214,18 @@ func (c *Client) DisableSCMPluginForProject(project, integration, pluginType str
 // GetProjectSCMStatus Get the SCM plugin status and available actions for the project.
 // http://rundeck.org/docs/api/index.html#get-project-scm-status
 func (c *Client) GetProjectSCMStatus(project, integration string) (*SCMProjectStatus, error) {
+	return c.GetProjectSCMStatusContext(context.Background(), project, integration)
+}
+
+// GetProjectSCMStatusContext Get the SCM plugin status and available actions for the project.
+func (c *Client) GetProjectSCMStatusContext(ctx context.Context, project, integration string) (*SCMProjectStatus, error) {
 	if err := c.checkRequiredAPIVersion(responses.SCMResponse{}); err != nil {
 		return nil, err
 	}
 	// project/[PROJECT]/scm/[INTEGRATION]/status
 	u := fmt.Sprintf("project/%s/scm/%s/status", project, integration)
 	results := &SCMProjectStatus{}
-	res, resErr := c.httpGet(u, requestExpects(200), accept("application/json"))
+	res, resErr := c.httpGet(ctx, u, requestExpects(200), accept("application/json"))
 	if resErr != nil {
 		return nil, resErr
 	}
@@ -207,12 +238,17 @@ func (c *Client) GetProjectSCMStatus(project, integration string) (*SCMProjectSt
 // GetProjectSCMConfig Get the configuration properties for the current plugin.
 // http://rundeck.org/docs/api/index.html#get-project-scm-config
 func (c *Client) GetProjectSCMConfig(projectName, integration string) (*ProjectSCMConfig, error) {
+	return c.GetProjectSCMConfigContext(context.Background(), projectName, integration)
+}
+
+// GetProjectSCMConfigContext Get the configuration properties for the current plugin.
+func (c *Client) GetProjectSCMConfigContext(ctx context.Context, projectName, integration string) (*ProjectSCMConfig, error) {
 	if err := c.checkRequiredAPIVersion(responses.SCMResponse{}); err != nil {
 		return nil, err
 	}
 	u := fmt.Sprintf("project/%s/scm/%s/config", projectName, integration)
 	data := &ProjectSCMConfig{}
-	res, err := c.httpGet(u, requestJSON(), requestExpects(200))
+	res, err := c.httpGet(ctx, u, requestJSON(), requestExpects(200))
 	if err != nil {
 		return nil, err
 	}
@@ -225,13 +261,18 @@ func (c *Client) GetProjectSCMConfig(projectName, integration string) (*ProjectS
 // GetProjectSCMActionInputFields Get the input fields and selectable items for a specific action.
 // http://rundeck.org/docs/api/index.html#get-project-scm-action-input-fields
 func (c *Client) GetProjectSCMActionInputFields(project, integration, action string) (*SCMActionInputFields, error) {
+	return c.GetProjectSCMActionInputFieldsContext(context.Background(), project, integration, action)
+}
+
+// GetProjectSCMActionInputFieldsContext Get the input fields and selectable items for a specific action.
+func (c *Client) GetProjectSCMActionInputFieldsContext(ctx context.Context, project, integration, action string) (*SCMActionInputFields, error) {
 	if err := c.checkRequiredAPIVersion(responses.SCMResponse{}); err != nil {
 		return nil, err
 	}
 	// project/[PROJECT]/scm/[INTEGRATION]/action/[ACTION_ID]/input
 	u := fmt.Sprintf("project/%s/scm/%s/action/%s/input", project, integration, action)
 	resp := &SCMActionInputFields{}
-	res, resErr := c.httpGet(u, accept("application/json"), requestExpects(200))
+	res, resErr := c.httpGet(ctx, u, accept("application/json"), requestExpects(200))
 	if resErr != nil {
 		return nil, resErr
 	}
@@ -287,6 +328,11 @@ func SCMActionDeleted(deleted ...string) SCMActionOption {
 // PerformProjectSCMAction Perform the action for the SCM integration plugin, with a set of input parameters, selected Jobs, or Items, or Items to delete.
 // http://rundeck.org/docs/api/index.html#perform-project-scm-action
 func (c *Client) PerformProjectSCMAction(project, integration, action string, opts ...SCMActionOption) (*SCMPluginForProject, error) {
+	return c.PerformProjectSCMActionContext(context.Background(), project, integration, action, opts...)
+}
+
+// PerformProjectSCMActionContext Perform the action for the SCM integration plugin, with a set of input parameters, selected Jobs, or Items, or Items to delete.
+func (c *Client) PerformProjectSCMActionContext(ctx context.Context, project, integration, action string, opts ...SCMActionOption) (*SCMPluginForProject, error) {
 	if err := c.checkRequiredAPIVersion(responses.SCMResponse{}); err != nil {
 		return nil, err
 	}
@@ -315,7 +361,7 @@ func (c *Client) PerformProjectSCMAction(project, integration, action string, op
 	if marshalErr != nil {
 		return nil, marshalErr
 	}
-	post, postErr := c.httpPost(u, withBody(bytes.NewReader(requestBody)), requestJSON(), requestExpects(200), requestExpects(400))
+	post, postErr := c.httpPost(ctx, u, withBody(bytes.NewReader(requestBody)), requestJSON(), requestExpects(200), requestExpects(400))
 	if postErr != nil {
 		return nil, postErr
 	}
@@ -329,12 +375,17 @@ func (c *Client) PerformProjectSCMAction(project, integration, action string, op
 // GetJobSCMStatus gets a job's scm status
 // http://rundeck.org/docs/api/index.html#get-job-scm-status
 func (c *Client) GetJobSCMStatus(jobid, integration string) (*SCMJobStatus, error) {
+	return c.GetJobSCMStatusContext(context.Background(), jobid, integration)
+}
+
+// GetJobSCMStatusContext gets a job's scm status
+func (c *Client) GetJobSCMStatusContext(ctx context.Context, jobid, integration string) (*SCMJobStatus, error) {
 	if err := c.checkRequiredAPIVersion(responses.SCMResponse{}); err != nil {
 		return nil, err
 	}
 	u := fmt.Sprintf("job/%s/scm/%s/status", jobid, integration)
 	results := &SCMJobStatus{}
-	res, resErr := c.httpGet(u, requestExpects(200), accept("application/json"))
+	res, resErr := c.httpGet(ctx, u, requestExpects(200), accept("application/json"))
 	if resErr != nil {
 		return nil, resErr
 	}
@@ -347,12 +398,17 @@ func (c *Client) GetJobSCMStatus(jobid, integration string) (*SCMJobStatus, erro
 // GetJobSCMDiff Retrieve the file diff for the Job, if there are changes for the integration.
 // http://rundeck.org/docs/api/index.html#get-job-scm-diff
 func (c *Client) GetJobSCMDiff(jobid, integration string) (*SCMJobDiff, error) {
+	return c.GetJobSCMDiffContext(context.Background(), jobid, integration)
+}
+
+// GetJobSCMDiffContext Retrieve the file diff for the Job, if there are changes for the integration.
+func (c *Client) GetJobSCMDiffContext(ctx context.Context, jobid, integration string) (*SCMJobDiff, error) {
 	if err := c.checkRequiredAPIVersion(responses.SCMResponse{}); err != nil {
 		return nil, err
 	}
 	u := fmt.Sprintf("job/%s/scm/%s/diff", jobid, integration)
 	results := &SCMJobDiff{}
-	res, resErr := c.httpGet(u, requestExpects(200), accept("application/json"))
+	res, resErr := c.httpGet(ctx, u, requestExpects(200), accept("application/json"))
 	if resErr != nil {
 		return nil, resErr
 	}
@@ -365,13 +421,18 @@ func (c *Client) GetJobSCMDiff(jobid, integration string) (*SCMJobDiff, error) {
 // GetJobSCMActionInputFields Get the input fields and selectable items for a specific action.
 // http://rundeck.org/docs/api/index.html#get-job-scm-action-input-fields
 func (c *Client) GetJobSCMActionInputFields(jobid, integration, action string) (*SCMActionInputFields, error) {
+	return c.GetJobSCMActionInputFieldsContext(context.Background(), jobid, integration, action)
+}
+
+// GetJobSCMActionInputFieldsContext Get the input fields and selectable items for a specific action.
+func (c *Client) GetJobSCMActionInputFieldsContext(ctx context.Context, jobid, integration, action string) (*SCMActionInputFields, error) {
 	if err := c.checkRequiredAPIVersion(responses.SCMResponse{}); err != nil {
 		return nil, err
 	}
 	// /api/15/job/[ID]/scm/[INTEGRATION]/action/[ACTION_ID]/input
 	u := fmt.Sprintf("job/%s/scm/%s/action/%s/input", jobid, integration, action)
 	resp := &SCMActionInputFields{}
-	res, resErr := c.httpGet(u, accept("application/json"), requestExpects(200))
+	res, resErr := c.httpGet(ctx, u, accept("application/json"), requestExpects(200))
 	if resErr != nil {
 		return nil, resErr
 	}
@@ -384,6 +445,11 @@ func (c *Client) GetJobSCMActionInputFields(jobid, integration, action string) (
 // PerformJobSCMAction Perform the action for the SCM integration plugin, with a set of input parameters, selected Jobs, or Items, or Items to delete.
 // http://rundeck.org/docs/api/index.html#perform-job-scm-action
 func (c *Client) PerformJobSCMAction() error {
+	return c.PerformJobSCMActionContext(context.Background())
+}
+
+// PerformJobSCMActionContext Perform the action for the SCM integration plugin, with a set of input parameters, selected Jobs, or Items, or Items to delete.
+func (c *Client) PerformJobSCMActionContext(ctx context.Context) error {
 	if err := c.checkRequiredAPIVersion(responses.SCMResponse{}); err != nil {
 		return err
 	}
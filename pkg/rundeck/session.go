@@ -0,0 +1,54 @@
+package rundeck
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+
+	httpclient "github.com/lusis/go-rundeck/pkg/httpclient"
+)
+
+// basicAuthSession tracks the login shared by every request made with a basic auth client
+// the session cookie itself lives in the client's cookie jar
+type basicAuthSession struct {
+	sync.Mutex
+	valid bool
+	// generation is bumped on every login so a request that failed with an old
+	// session doesn't throw away a newer one another goroutine just created
+	generation uint64
+}
+
+// ensureSession logs in if there is no valid session and returns the session generation
+func (rc *Client) ensureSession(ctx context.Context) (uint64, error) {
+	rc.session.Lock()
+	defer rc.session.Unlock()
+	if rc.session.valid {
+		return rc.session.generation, nil
+	}
+	if err := rc.basicAuth(ctx); err != nil {
+		return 0, err
+	}
+	rc.session.valid = true
+	rc.session.generation++
+	return rc.session.generation, nil
+}
+
+// invalidateSession marks the session from the given generation as expired
+func (rc *Client) invalidateSession(generation uint64) {
+	rc.session.Lock()
+	defer rc.session.Unlock()
+	if rc.session.generation == generation {
+		rc.session.valid = false
+	}
+}
+
+// sessionExpired reports if a request failed because rundeck no longer accepts our session
+// rundeck either redirects to the login page or returns 401/403
+func sessionExpired(resp *httpclient.Response, err error) bool {
+	var authErr *AuthError
+	if errors.As(err, &authErr) {
+		return true
+	}
+	return resp != nil && (resp.Status == http.StatusUnauthorized || resp.Status == http.StatusForbidden)
+}
@@ -0,0 +1,149 @@
+package rundeck
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// testSessionServer fakes rundeck's form login
+// api requests without the current session cookie are redirected to the login page
+type testSessionServer struct {
+	sync.Mutex
+	logins   int
+	current  string
+	apiCalls int
+	bodies   []string
+}
+
+func (s *testSessionServer) expire() {
+	s.Lock()
+	defer s.Unlock()
+	s.current = ""
+}
+
+func (s *testSessionServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.Lock()
+	defer s.Unlock()
+	switch r.URL.Path {
+	case "/j_security_check":
+		_ = r.ParseForm()
+		if r.PostForm.Get("j_password") != "12345" {
+			http.Redirect(w, r, "/user/error", http.StatusFound)
+			return
+		}
+		s.logins++
+		s.current = strconv.Itoa(s.logins)
+		http.SetCookie(w, &http.Cookie{Name: "JSESSIONID", Value: s.current, Path: "/"})
+		w.WriteHeader(http.StatusOK)
+	default:
+		s.apiCalls++
+		cookie, err := r.Cookie("JSESSIONID")
+		if err != nil || s.current == "" || cookie.Value != s.current {
+			http.Redirect(w, r, "/user/login", http.StatusFound)
+			return
+		}
+		b, _ := ioutil.ReadAll(r.Body)
+		s.bodies = append(s.bodies, string(b))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{}`))
+	}
+}
+
+func newTestSessionClient(password string) (*Client, *testSessionServer, *httptest.Server, error) {
+	s := &testSessionServer{}
+	server := httptest.NewServer(s)
+	client, err := NewBasicAuthClient("admin", password, server.URL)
+	return client, s, server, err
+}
+
+func TestBasicAuthSessionReused(t *testing.T) {
+	client, s, server, err := newTestSessionClient("12345")
+	defer server.Close()
+	require.NoError(t, err)
+	for i := 0; i < 3; i++ {
+		_, err := client.httpGet(context.Background(), "system/info", requestExpects(200))
+		require.NoError(t, err)
+	}
+	require.Equal(t, 1, s.logins)
+	require.Equal(t, 3, s.apiCalls)
+}
+
+func TestBasicAuthSessionExpired(t *testing.T) {
+	client, s, server, err := newTestSessionClient("12345")
+	defer server.Close()
+	require.NoError(t, err)
+	_, err = client.httpGet(context.Background(), "system/info", requestExpects(200))
+	require.NoError(t, err)
+	s.expire()
+	_, err = client.httpPost(context.Background(), "job/abc123/run", withBody(strings.NewReader("payload")), requestExpects(200))
+	require.NoError(t, err)
+	require.Equal(t, 2, s.logins)
+	require.Equal(t, []string{"", "payload"}, s.bodies)
+}
+
+func TestBasicAuthSessionExpiredLargeUpload(t *testing.T) {
+	client, s, server, err := newTestSessionClient("12345")
+	defer server.Close()
+	require.NoError(t, err)
+	_, err = client.httpGet(context.Background(), "system/info", requestExpects(200))
+	require.NoError(t, err)
+	s.expire()
+	// a reader that can't be seeked and is too large to buffer is only sent once
+	payload := strings.Repeat("x", maxReplayBody+1)
+	_, err = client.httpPut(context.Background(), "project/test/import", withBody(struct{ io.Reader }{strings.NewReader(payload)}), requestExpects(200))
+	require.Error(t, err)
+	var authErr *AuthError
+	require.True(t, errors.As(err, &authErr))
+	require.Equal(t, 2, s.apiCalls)
+	require.Equal(t, []string{""}, s.bodies)
+
+	// the session was invalidated so the next upload logs in before it is sent
+	_, err = client.httpPut(context.Background(), "project/test/import", withBody(struct{ io.Reader }{strings.NewReader(payload)}), requestExpects(200))
+	require.NoError(t, err)
+	require.Equal(t, 2, s.logins)
+	require.Equal(t, 3, s.apiCalls)
+	require.Len(t, s.bodies[1], len(payload))
+}
+
+func TestBasicAuthSessionConcurrent(t *testing.T) {
+	client, s, server, err := newTestSessionClient("12345")
+	defer server.Close()
+	require.NoError(t, err)
+	var wg sync.WaitGroup
+	errs := make(chan error, 20)
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := client.httpGet(context.Background(), "system/info", requestExpects(200))
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		require.NoError(t, err)
+	}
+	require.Equal(t, 1, s.logins)
+}
+
+func TestBasicAuthInvalidPassword(t *testing.T) {
+	client, s, server, err := newTestSessionClient("wrong")
+	defer server.Close()
+	require.NoError(t, err)
+	_, err = client.httpGet(context.Background(), "system/info", requestExpects(200))
+	require.Error(t, err)
+	var authErr *AuthError
+	require.True(t, errors.As(err, &authErr))
+	require.Equal(t, 0, s.apiCalls)
+}
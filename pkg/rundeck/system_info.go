@@ -1,6 +1,7 @@
 package rundeck
 
 import (
+	"context"
 	"encoding/json"
 
 	multierror "github.com/hashicorp/go-multierror"
@@ -15,11 +16,16 @@ type SystemInfo struct {
 // GetSystemInfo gets system information from the rundeck server
 // http://rundeck.org/docs/api/index.html#system-info
 func (c *Client) GetSystemInfo() (*SystemInfo, error) {
+	return c.GetSystemInfoContext(context.Background())
+}
+
+// GetSystemInfoContext gets system information from the rundeck server
+func (c *Client) GetSystemInfoContext(ctx context.Context) (*SystemInfo, error) {
 	if err := c.checkRequiredAPIVersion(responses.SystemInfoResponse{}); err != nil {
 		return nil, err
 	}
 	ls := SystemInfo{}
-	data, err := c.httpGet("system/info", requestJSON(), requestExpects(200))
+	data, err := c.httpGet(ctx, "system/info", requestJSON(), requestExpects(200))
 	if err != nil {
 		return nil, err
 	}
@@ -2,6 +2,7 @@ package rundeck
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"strings"
@@ -46,11 +47,16 @@ func TokenRoles(roles ...string) TokenOption {
 // ListTokens gets all tokens for the current user
 // http://rundeck.org/docs/api/index.html#list-tokens
 func (c *Client) ListTokens() ([]*Token, error) {
+	return c.ListTokensContext(context.Background())
+}
+
+// ListTokensContext gets all tokens for the current user
+func (c *Client) ListTokensContext(ctx context.Context) ([]*Token, error) {
 	if err := c.checkRequiredAPIVersion(responses.ListTokensResponse{}); err != nil {
 		return nil, err
 	}
 	tokens := []*Token{}
-	data, err := c.httpGet("tokens", requestJSON(), requestExpects(200))
+	data, err := c.httpGet(ctx, "tokens", requestJSON(), requestExpects(200))
 	if err != nil {
 		return nil, err
 	}
@@ -64,10 +70,15 @@ func (c *Client) ListTokens() ([]*Token, error) {
 // ListTokensForUser gets the api tokens for a user
 // http://rundeck.org/docs/api/index.html#list-tokens
 func (c *Client) ListTokensForUser(user string) ([]*Token, error) {
+	return c.ListTokensForUserContext(context.Background(), user)
+}
+
+// ListTokensForUserContext gets the api tokens for a user
+func (c *Client) ListTokensForUserContext(ctx context.Context, user string) ([]*Token, error) {
 	if err := c.checkRequiredAPIVersion(responses.ListTokensResponse{}); err != nil {
 		return nil, err
 	}
-	data, err := c.httpGet("tokens/"+user, requestJSON(), requestExpects(200))
+	data, err := c.httpGet(ctx, "tokens/"+user, requestJSON(), requestExpects(200))
 	if err != nil {
 		return nil, err
 	}
@@ -82,11 +93,16 @@ func (c *Client) ListTokensForUser(user string) ([]*Token, error) {
 // GetToken gets a token
 // http://rundeck.org/docs/api/index.html#get-a-token
 func (c *Client) GetToken(tokenID string) (*Token, error) {
+	return c.GetTokenContext(context.Background(), tokenID)
+}
+
+// GetTokenContext gets a token
+func (c *Client) GetTokenContext(ctx context.Context, tokenID string) (*Token, error) {
 	if err := c.checkRequiredAPIVersion(responses.TokenResponse{}); err != nil {
 		return nil, err
 	}
 
-	data, err := c.httpGet("token/"+tokenID, requestJSON(), requestExpects(200))
+	data, err := c.httpGet(ctx, "token/"+tokenID, requestJSON(), requestExpects(200))
 	if err != nil {
 		return nil, err
 	}
@@ -101,6 +117,11 @@ func (c *Client) GetToken(tokenID string) (*Token, error) {
 // CreateToken creates a token
 // http://rundeck.org/docs/api/index.html#create-a-token
 func (c *Client) CreateToken(username string, opts ...TokenOption) (*Token, error) {
+	return c.CreateTokenContext(context.Background(), username, opts...)
+}
+
+// CreateTokenContext creates a token
+func (c *Client) CreateTokenContext(ctx context.Context, username string, opts ...TokenOption) (*Token, error) {
 	if err := c.checkRequiredAPIVersion(responses.TokenResponse{}); err != nil {
 		return nil, err
 	}
@@ -120,7 +141,7 @@ func (c *Client) CreateToken(username string, opts ...TokenOption) (*Token, erro
 		return nil, marshalErr
 	}
 	url := "tokens"
-	data, err := c.httpPost(url, requestJSON(), withBody(bytes.NewReader(newToken)), requestExpects(201))
+	data, err := c.httpPost(ctx, url, requestJSON(), withBody(bytes.NewReader(newToken)), requestExpects(201))
 	if err != nil {
 		return nil, err
 	}
@@ -135,10 +156,15 @@ func (c *Client) CreateToken(username string, opts ...TokenOption) (*Token, erro
 // DeleteToken deletes a token
 // http://rundeck.org/docs/api/index.html#delete-a-token
 func (c *Client) DeleteToken(token string) error {
+	return c.DeleteTokenContext(context.Background(), token)
+}
+
+// DeleteTokenContext deletes a token
+func (c *Client) DeleteTokenContext(ctx context.Context, token string) error {
 	if err := c.checkRequiredAPIVersion(responses.TokenResponse{}); err != nil {
 		return err
 	}
 	url := fmt.Sprintf("token/%s", token)
-	_, err := c.httpDelete(url, requestJSON(), requestExpects(204))
+	_, err := c.httpDelete(ctx, url, requestJSON(), requestExpects(204))
 	return err
 }
@@ -2,6 +2,7 @@ package rundeck
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"time"
@@ -29,12 +30,17 @@ type Users []User
 // ListUsers returns all rundeck users
 // http://rundeck.org/docs/api/index.html#list-users
 func (c *Client) ListUsers() (Users, error) {
+	return c.ListUsersContext(context.Background())
+}
+
+// ListUsersContext returns all rundeck users
+func (c *Client) ListUsersContext(ctx context.Context) (Users, error) {
 	if err := c.checkRequiredAPIVersion(responses.ListUsersResponse{}); err != nil {
 		return nil, err
 	}
 	users := Users{}
 	listusers := responses.ListUsersResponse{}
-	res, err := c.httpGet("user/list", requestJSON(), requestExpects(200))
+	res, err := c.httpGet(ctx, "user/list", requestJSON(), requestExpects(200))
 	if err != nil {
 		return nil, err
 	}
@@ -62,11 +68,16 @@ func (c *Client) ListUsers() (Users, error) {
 // GetCurrentUserProfile returns information about the current user
 // http://rundeck.org/docs/api/index.html#get-user-profile
 func (c *Client) GetCurrentUserProfile() (*User, error) {
+	return c.GetCurrentUserProfileContext(context.Background())
+}
+
+// GetCurrentUserProfileContext returns information about the current user
+func (c *Client) GetCurrentUserProfileContext(ctx context.Context) (*User, error) {
 	if err := c.checkRequiredAPIVersion(responses.UserProfileResponse{}); err != nil {
 		return nil, err
 	}
 	user := &User{}
-	res, err := c.httpGet("user/info", requestJSON(), requestExpects(200))
+	res, err := c.httpGet(ctx, "user/info", requestJSON(), requestExpects(200))
 	if err != nil {
 		return nil, err
 	}
@@ -79,11 +90,16 @@ func (c *Client) GetCurrentUserProfile() (*User, error) {
 // GetUserProfile returns information about the named user - requires admin privileges
 // http://rundeck.org/docs/api/index.html#get-another-user-profile
 func (c *Client) GetUserProfile(login string) (*User, error) {
+	return c.GetUserProfileContext(context.Background(), login)
+}
+
+// GetUserProfileContext returns information about the named user - requires admin privileges
+func (c *Client) GetUserProfileContext(ctx context.Context, login string) (*User, error) {
 	if err := c.checkRequiredAPIVersion(responses.UserProfileResponse{}); err != nil {
 		return nil, err
 	}
 	user := &User{}
-	res, err := c.httpGet("user/info/"+login, requestJSON(), requestExpects(200))
+	res, err := c.httpGet(ctx, "user/info/"+login, requestJSON(), requestExpects(200))
 	if err != nil {
 		return nil, err
 	}
@@ -96,10 +112,15 @@ func (c *Client) GetUserProfile(login string) (*User, error) {
 // ModifyUserProfile updates a user
 // http://rundeck.org/docs/api/index.html#modify-user-profile
 func (c *Client) ModifyUserProfile(u *User) (*User, error) {
+	return c.ModifyUserProfileContext(context.Background(), u)
+}
+
+// ModifyUserProfileContext updates a user
+func (c *Client) ModifyUserProfileContext(ctx context.Context, u *User) (*User, error) {
 	if err := c.checkRequiredAPIVersion(responses.UserProfileResponse{}); err != nil {
 		return nil, err
 	}
-	currentUser, currentUserErr := c.GetCurrentUserProfile()
+	currentUser, currentUserErr := c.GetCurrentUserProfileContext(ctx)
 	if currentUserErr != nil {
 		return nil, currentUserErr
 	}
@@ -119,7 +140,7 @@ func (c *Client) ModifyUserProfile(u *User) (*User, error) {
 	if postDataErr != nil {
 		return nil, postDataErr
 	}
-	res, resErr := c.httpPost(updatePath, withBody(bytes.NewReader(postData)), requestJSON(), requestExpects(200))
+	res, resErr := c.httpPost(ctx, updatePath, withBody(bytes.NewReader(postData)), requestJSON(), requestExpects(200))
 	if resErr != nil {
 		return nil, resErr
 	}